@@ -6,8 +6,8 @@ import (
 	"image/png"
 	"io"
 	"os"
+	"strings"
 
-	"github.com/inkyblackness/res/audio/mem"
 	"github.com/inkyblackness/res/movi"
 
 	"github.com/inkyblackness/chunkie/convert/wav"
@@ -18,6 +18,17 @@ var subtitleLanguages = map[movi.SubtitleControl]string{
 	movi.SubtitleTextFrn: "fr",
 	movi.SubtitleTextGer: "de"}
 
+// subtitleControlOf returns the control for the language of a subtitle file, as named by the export.
+func subtitleControlOf(fileName string) (movi.SubtitleControl, bool) {
+	lowerName := strings.ToLower(fileName)
+	for control, language := range subtitleLanguages {
+		if strings.HasSuffix(lowerName, "_"+language+".srt") {
+			return control, true
+		}
+	}
+	return 0, false
+}
+
 type subtitleEntry struct {
 	file    io.WriteCloser
 	counter int
@@ -30,9 +41,6 @@ type exportingMediaHandler struct {
 	mediaDuration float32
 	fileBaseName  string
 
-	sampleRate float32
-	audio      []byte
-
 	subtitles map[movi.SubtitleControl]*subtitleEntry
 
 	frameCounter       int
@@ -42,13 +50,12 @@ type exportingMediaHandler struct {
 	framesPerSecond float32
 }
 
-func newExportingMediaHandler(fileBaseName string, mediaDuration float32, framesPerSecond float32, sampleRate float32) *exportingMediaHandler {
+func newExportingMediaHandler(fileBaseName string, mediaDuration float32, framesPerSecond float32) *exportingMediaHandler {
 	return &exportingMediaHandler{
 		mediaDuration:   mediaDuration,
 		fileBaseName:    fileBaseName,
 		subtitles:       make(map[movi.SubtitleControl]*subtitleEntry),
-		framesPerSecond: framesPerSecond,
-		sampleRate:      sampleRate}
+		framesPerSecond: framesPerSecond}
 }
 
 func (handler *exportingMediaHandler) finish() {
@@ -57,14 +64,19 @@ func (handler *exportingMediaHandler) finish() {
 		handler.finishSubtitle(entry, handler.mediaDuration)
 		_ = entry.file.Close()
 	}
-	if len(handler.audio) > 0 {
-		soundData := mem.NewL8SoundData(handler.sampleRate, handler.audio)
-		wav.ExportToWav(handler.fileBaseName+".wav", soundData)
+}
+
+// exportAudio writes the audio track of given container, if it has one.
+// The samples are streamed from the container instead of being collected during dispatch.
+func (handler *exportingMediaHandler) exportAudio(container movi.Container) error {
+	source := movi.NewAudioSource(container)
+	if source.SampleCount() == 0 {
+		return nil
 	}
+	return wav.ExportSourceToWav(handler.fileBaseName+".wav", source)
 }
 
 func (handler *exportingMediaHandler) OnAudio(timestamp float32, samples []byte) {
-	handler.audio = append(handler.audio, samples...)
 }
 
 func (handler *exportingMediaHandler) OnSubtitle(timestamp float32, control movi.SubtitleControl, text string) {
@@ -0,0 +1,23 @@
+package convert
+
+import (
+	goimage "image"
+
+	"github.com/inkyblackness/res/image"
+)
+
+// BitmapFromImage takes a paletted image and returns it as bitmap, to be stored
+// with given properties.
+func BitmapFromImage(img *goimage.Paletted, withPrivatePalette bool, compressed, forceTransparency bool) image.Bitmap {
+	palette := img.Palette
+	imgType := image.UncompressedBitmap
+
+	if !withPrivatePalette {
+		palette = nil
+	}
+	if compressed {
+		imgType = image.CompressedBitmap
+	}
+
+	return image.WithStorage(image.ToBitmap(img, palette), imgType, forceTransparency)
+}
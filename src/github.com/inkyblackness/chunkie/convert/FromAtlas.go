@@ -0,0 +1,60 @@
+package convert
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	goimage "image"
+	"image/png"
+	"io/ioutil"
+	"os"
+
+	"github.com/inkyblackness/res/image"
+	"github.com/inkyblackness/res/image/atlas"
+)
+
+var errSheetNotPaletted = errors.New("sheet is not a paletted image")
+
+// FromAtlas slices a PNG sheet according to the layout next to it and returns
+// the encoded bitmaps, ordered by block index. The palette indices of the sheet
+// are used as they are.
+func FromAtlas(sheetFile string) ([][]byte, error) {
+	layoutData, layoutErr := ioutil.ReadFile(AtlasLayoutFileName(sheetFile))
+	if layoutErr != nil {
+		return nil, layoutErr
+	}
+	var layout atlas.Layout
+	layoutErr = json.Unmarshal(layoutData, &layout)
+	if layoutErr != nil {
+		return nil, fmt.Errorf("failed to parse layout: %v", layoutErr)
+	}
+	file, fileErr := os.Open(sheetFile)
+	if fileErr != nil {
+		return nil, fileErr
+	}
+	defer file.Close()
+	img, imgErr := png.Decode(file)
+	if imgErr != nil {
+		return nil, imgErr
+	}
+	sheet, isPaletted := img.(*goimage.Paletted)
+	if !isPaletted {
+		return nil, errSheetNotPaletted
+	}
+
+	bitmaps, sliceErr := atlas.Slice(sheet, &layout)
+	if sliceErr != nil {
+		return nil, sliceErr
+	}
+	blocks := make([][]byte, len(bitmaps))
+	for blockID, bmp := range bitmaps {
+		buffer := bytes.NewBuffer(nil)
+		writeErr := image.Write(buffer, bmp, bmp.Type(), bmp.Transparent(), 0)
+		if writeErr != nil {
+			return nil, fmt.Errorf("failed to encode block %d: %v", blockID, writeErr)
+		}
+		blocks[blockID] = buffer.Bytes()
+	}
+	return blocks, nil
+}
@@ -0,0 +1,41 @@
+package convert
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/inkyblackness/res/font"
+)
+
+// FromFont creates the data of a game font from a font file. BDF files are read at their size,
+// while TrueType and OpenType files are rasterized at the given pixel size.
+func FromFont(fileName string, pixelSize float64, options font.BuildOptions) ([]byte, error) {
+	var source font.GlyphSource
+	var err error
+
+	if strings.ToLower(filepath.Ext(fileName)) == ".bdf" {
+		file, fileErr := os.Open(fileName)
+		if fileErr != nil {
+			return nil, fileErr
+		}
+		defer file.Close()
+		source, err = font.NewBdfSource(file)
+	} else {
+		data, dataErr := ioutil.ReadFile(fileName)
+		if dataErr != nil {
+			return nil, dataErr
+		}
+		source, err = font.NewSfntSource(data, pixelSize)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	built, buildErr := font.Build(source, options)
+	if buildErr != nil {
+		return nil, buildErr
+	}
+	return font.Save(built), nil
+}
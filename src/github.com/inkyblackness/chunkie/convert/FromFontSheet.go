@@ -0,0 +1,41 @@
+package convert
+
+import (
+	"encoding/json"
+	"fmt"
+	goimage "image"
+	"image/png"
+	"io/ioutil"
+	"os"
+
+	"github.com/inkyblackness/res/font"
+)
+
+// FromFontSheet creates a game font from a PNG sheet and the metrics next to it,
+// as saved by ToFontSheet. The palette indices of the sheet are used as they are.
+func FromFontSheet(sheetFile string) (font.Font, error) {
+	metricsData, metricsErr := ioutil.ReadFile(FontMetricsFileName(sheetFile))
+	if metricsErr != nil {
+		return nil, metricsErr
+	}
+	var metrics font.Metrics
+	metricsErr = json.Unmarshal(metricsData, &metrics)
+	if metricsErr != nil {
+		return nil, fmt.Errorf("failed to parse metrics: %v", metricsErr)
+	}
+	file, fileErr := os.Open(sheetFile)
+	if fileErr != nil {
+		return nil, fileErr
+	}
+	defer file.Close()
+	img, imgErr := png.Decode(file)
+	if imgErr != nil {
+		return nil, imgErr
+	}
+	sheet, isPaletted := img.(*goimage.Paletted)
+	if !isPaletted {
+		return nil, errSheetNotPaletted
+	}
+
+	return font.FromSheet(sheet, &metrics)
+}
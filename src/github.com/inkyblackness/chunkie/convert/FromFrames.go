@@ -0,0 +1,200 @@
+package convert
+
+import (
+	"fmt"
+	goimage "image"
+	"image/color"
+	"image/gif"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/inkyblackness/res/image"
+	"github.com/inkyblackness/res/movi"
+)
+
+var timedFrameName = regexp.MustCompile(`_(\d+)\.(\d{3})\.png$`)
+
+// FromFrames reads the frames of a video from either a folder of paletted PNG files,
+// or from an animated GIF file.
+//
+// PNG files are ordered by name. With a positive frame rate, the frames follow each other
+// in this rate. Otherwise, the timestamp is taken from the file name, as it is exported
+// without frame rate; Such as "movie_012.500.png" for a frame at 12.5 seconds.
+// The frames of a GIF file are timed by their delays and drawn on top of each other.
+//
+// Each frame keeps its own palette. Use MapFramesToPalettes to map them to the palettes
+// of the video.
+func FromFrames(source string, framesPerSecond float32) ([]movi.TimedFrame, error) {
+	if strings.ToLower(filepath.Ext(source)) == ".gif" {
+		return fromGif(source)
+	}
+	return fromPngFolder(source, framesPerSecond)
+}
+
+func fromPngFolder(folder string, framesPerSecond float32) ([]movi.TimedFrame, error) {
+	fileInfos, dirErr := ioutil.ReadDir(folder)
+	if dirErr != nil {
+		return nil, dirErr
+	}
+	var names []string
+	for _, info := range fileInfos {
+		if !info.IsDir() && (strings.ToLower(filepath.Ext(info.Name())) == ".png") {
+			names = append(names, info.Name())
+		}
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no PNG files found in %v", folder)
+	}
+
+	frames := make([]movi.TimedFrame, len(names))
+	for index, name := range names {
+		frame, frameErr := loadPalettedPng(filepath.Join(folder, name))
+		if frameErr != nil {
+			return nil, frameErr
+		}
+		frame.Palette = fullPalette(frame.Palette)
+		frames[index].Frame = frame
+		if framesPerSecond > 0 {
+			frames[index].Timestamp = float32(index) / framesPerSecond
+		} else {
+			match := timedFrameName.FindStringSubmatch(name)
+			if match == nil {
+				return nil, fmt.Errorf("%v is not named after its timestamp, and no frame rate is given", name)
+			}
+			seconds, _ := strconv.ParseUint(match[1], 10, 32)
+			millis, _ := strconv.ParseUint(match[2], 10, 32)
+			frames[index].Timestamp = float32(seconds) + float32(millis)/1000
+		}
+	}
+	return frames, nil
+}
+
+func fromGif(fileName string) ([]movi.TimedFrame, error) {
+	file, fileErr := os.Open(fileName)
+	if fileErr != nil {
+		return nil, fileErr
+	}
+	defer file.Close()
+	animation, gifErr := gif.DecodeAll(file)
+	if gifErr != nil {
+		return nil, gifErr
+	}
+	if len(animation.Image) == 0 {
+		return nil, fmt.Errorf("no frames found in %v", fileName)
+	}
+
+	bounds := goimage.Rect(0, 0, animation.Config.Width, animation.Config.Height)
+	canvas := goimage.NewPaletted(bounds, fullPalette(animation.Image[0].Palette))
+	frames := make([]movi.TimedFrame, len(animation.Image))
+	timestamp := float32(0)
+	for index, img := range animation.Image {
+		drawGifFrame(canvas, img)
+		frame := goimage.NewPaletted(bounds, canvas.Palette)
+		copy(frame.Pix, canvas.Pix)
+		frames[index] = movi.TimedFrame{Timestamp: timestamp, Frame: frame}
+		timestamp += float32(animation.Delay[index]) / 100
+	}
+	return frames, nil
+}
+
+// MapFramesToPalettes maps the frames to the palette that is in use at their timestamp.
+// Before the first palette change, this is the palette of the first frame; A video knows
+// only one palette at a time. Frames with a different palette are replaced by a copy,
+// with each color mapped to the closest entry of the palette in use.
+func MapFramesToPalettes(frames []movi.TimedFrame, palettes []movi.TimedPalette) {
+	if len(frames) == 0 {
+		return
+	}
+	palette := frames[0].Frame.Palette
+	nextChange := 0
+	for index := range frames {
+		frame := &frames[index]
+		for (nextChange < len(palettes)) && (palettes[nextChange].Timestamp <= frame.Timestamp) {
+			palette = palettes[nextChange].Palette
+			nextChange++
+		}
+		if !isSamePalette(frame.Frame.Palette, palette) {
+			frame.Frame = inPalette(frame.Frame, palette)
+		}
+	}
+}
+
+// inPalette returns a copy of given image that uses the given palette.
+func inPalette(img *goimage.Paletted, palette color.Palette) *goimage.Paletted {
+	var mapping [256]byte
+	for index, entry := range img.Palette {
+		if (index < len(palette)) && (color.NRGBAModel.Convert(palette[index]) == color.NRGBAModel.Convert(entry)) {
+			mapping[index] = byte(index)
+		} else {
+			mapping[index] = byte(palette.Index(entry))
+		}
+	}
+	bounds := img.Bounds()
+	result := goimage.NewPaletted(bounds, palette)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			result.SetColorIndex(x, y, mapping[img.ColorIndexAt(x, y)])
+		}
+	}
+	return result
+}
+
+func isSamePalette(a, b color.Palette) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for index := range a {
+		if color.NRGBAModel.Convert(a[index]) != color.NRGBAModel.Convert(b[index]) {
+			return false
+		}
+	}
+	return true
+}
+
+// drawGifFrame draws the opaque pixel of given image onto the canvas. Colors that differ
+// from the palette of the canvas are mapped to their closest entry.
+func drawGifFrame(canvas, img *goimage.Paletted) {
+	var mapping [256]int
+	for index, entry := range img.Palette {
+		_, _, _, alpha := entry.RGBA()
+		switch {
+		case alpha == 0:
+			mapping[index] = -1
+		case (index < len(canvas.Palette)) && (canvas.Palette[index] == entry):
+			mapping[index] = index
+		default:
+			mapping[index] = canvas.Palette.Index(entry)
+		}
+	}
+	area := img.Bounds().Intersect(canvas.Bounds())
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		for x := area.Min.X; x < area.Max.X; x++ {
+			if value := mapping[img.ColorIndexAt(x, y)]; value >= 0 {
+				canvas.SetColorIndex(x, y, byte(value))
+			}
+		}
+	}
+}
+
+// fullPalette returns the given palette extended to the size of the game palettes.
+// Missing entries are black.
+func fullPalette(palette color.Palette) color.Palette {
+	if len(palette) == image.ColorsPerPixel {
+		return palette
+	}
+	result := make(color.Palette, image.ColorsPerPixel)
+	for index := range result {
+		if index < len(palette) {
+			result[index] = palette[index]
+		} else {
+			result[index] = color.NRGBA{A: 0xFF}
+		}
+	}
+	return result
+}
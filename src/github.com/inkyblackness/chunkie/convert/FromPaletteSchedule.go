@@ -0,0 +1,70 @@
+package convert
+
+import (
+	"encoding/json"
+	"fmt"
+	goimage "image"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/inkyblackness/res/movi"
+)
+
+// PaletteChange is one entry of a palette schedule file.
+type PaletteChange struct {
+	// Time is the timestamp, in seconds, from which the palette is used.
+	Time float32 `json:"time"`
+	// Image is the name of a paletted PNG file that provides the palette,
+	// relative to the schedule file.
+	Image string `json:"image"`
+}
+
+// FromPaletteSchedule reads a JSON file with a list of palette changes and returns
+// the palettes, ordered by time.
+func FromPaletteSchedule(fileName string) ([]movi.TimedPalette, error) {
+	scheduleData, scheduleErr := ioutil.ReadFile(fileName)
+	if scheduleErr != nil {
+		return nil, scheduleErr
+	}
+	var changes []PaletteChange
+	scheduleErr = json.Unmarshal(scheduleData, &changes)
+	if scheduleErr != nil {
+		return nil, fmt.Errorf("failed to parse palette schedule: %v", scheduleErr)
+	}
+
+	palettes := make([]movi.TimedPalette, len(changes))
+	for index, change := range changes {
+		if (index > 0) && (change.Time < changes[index-1].Time) {
+			return nil, fmt.Errorf("palette change %d is out of order", index)
+		}
+		imageFile := change.Image
+		if !filepath.IsAbs(imageFile) {
+			imageFile = filepath.Join(filepath.Dir(fileName), imageFile)
+		}
+		img, imgErr := loadPalettedPng(imageFile)
+		if imgErr != nil {
+			return nil, fmt.Errorf("palette change %d: %v", index, imgErr)
+		}
+		palettes[index] = movi.TimedPalette{Timestamp: change.Time, Palette: fullPalette(img.Palette)}
+	}
+	return palettes, nil
+}
+
+func loadPalettedPng(fileName string) (*goimage.Paletted, error) {
+	file, fileErr := os.Open(fileName)
+	if fileErr != nil {
+		return nil, fileErr
+	}
+	defer file.Close()
+	img, imgErr := png.Decode(file)
+	if imgErr != nil {
+		return nil, imgErr
+	}
+	paletted, isPaletted := img.(*goimage.Paletted)
+	if !isPaletted {
+		return nil, fmt.Errorf("%v is not a paletted image", fileName)
+	}
+	return paletted, nil
+}
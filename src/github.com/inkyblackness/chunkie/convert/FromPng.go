@@ -1,16 +1,39 @@
 package convert
 
 import (
-	"image"
+	goimage "image"
 	"image/png"
 	"os"
+
+	"github.com/inkyblackness/res/image"
 )
 
-// FromPng reads a PNG file and encodes it as block.
-func FromPng(fileName string, privatePalette bool, compressed, forceTransparency bool) []byte {
-	file, _ := os.Open(fileName)
+// FromPng reads a PNG file and returns it as bitmap.
+// Paletted images are encoded with their indices as they are. Any other image
+// is mapped according to the given mapping. Should the image not be paletted
+// and the mapping not be possible, nil is returned.
+func FromPng(fileName string, compressed, forceTransparency bool, mapping PaletteMapping) image.Bitmap {
+	file, fileErr := os.Open(fileName)
+	if fileErr != nil {
+		return nil
+	}
 	defer file.Close()
-	img, _ := png.Decode(file)
+	img, imgErr := png.Decode(file)
+	if imgErr != nil {
+		return nil
+	}
+
+	palettedImg, isPaletted := img.(*goimage.Paletted)
+	if !isPaletted {
+		if !mapping.CanMap() {
+			return nil
+		}
+		var mapErr error
+		palettedImg, mapErr = mapping.quantize(img)
+		if mapErr != nil {
+			return nil
+		}
+	}
 
-	return EncodeImage(img.(*image.Paletted), privatePalette, compressed, forceTransparency)
+	return BitmapFromImage(palettedImg, mapping.Private, compressed, forceTransparency)
 }
@@ -0,0 +1,101 @@
+package convert
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/inkyblackness/res/movi"
+)
+
+// FromSrt reads a subtitle file in the format as it is exported and returns its texts
+// for the given control. Each text is a block of a counter line, a line with the time span
+// like "00:01:02,500 --> 00:01:04,000", and the lines of the text, ended by an empty line.
+func FromSrt(fileName string, control movi.SubtitleControl) ([]movi.TimedSubtitle, error) {
+	file, fileErr := os.Open(fileName)
+	if fileErr != nil {
+		return nil, fileErr
+	}
+	defer file.Close()
+
+	var subtitles []movi.TimedSubtitle
+	var block []string
+	finishBlock := func(lineNumber int) error {
+		if len(block) == 0 {
+			return nil
+		}
+		if len(block) < 2 {
+			return fmt.Errorf("line %d: incomplete subtitle", lineNumber)
+		}
+		subtitle, err := parseSrtBlock(block[1], block[2:])
+		if err != nil {
+			return fmt.Errorf("line %d: %v", lineNumber, err)
+		}
+		subtitle.Control = control
+		subtitles = append(subtitles, subtitle)
+		block = nil
+		return nil
+	}
+
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if lineNumber == 1 {
+			line = strings.TrimPrefix(line, "\uFEFF")
+		}
+		if len(strings.TrimSpace(line)) == 0 {
+			err := finishBlock(lineNumber)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			block = append(block, line)
+		}
+	}
+	if scanErr := scanner.Err(); scanErr != nil {
+		return nil, scanErr
+	}
+	err := finishBlock(lineNumber)
+	if err != nil {
+		return nil, err
+	}
+	return subtitles, nil
+}
+
+func parseSrtBlock(timing string, lines []string) (subtitle movi.TimedSubtitle, err error) {
+	parts := strings.Split(timing, "-->")
+	if len(parts) != 2 {
+		return subtitle, fmt.Errorf("invalid time span %q", timing)
+	}
+	subtitle.Start, err = parseSrtTimestamp(parts[0])
+	if err != nil {
+		return
+	}
+	subtitle.End, err = parseSrtTimestamp(parts[1])
+	if err != nil {
+		return
+	}
+	subtitle.Text = strings.Join(lines, "\n")
+	return
+}
+
+// parseSrtTimestamp parses a time like "01:02:03,456" into seconds. The fraction may also
+// be separated with a dot.
+func parseSrtTimestamp(text string) (float32, error) {
+	trimmed := strings.Replace(strings.TrimSpace(text), ",", ".", 1)
+	fields := strings.Split(trimmed, ":")
+	if len(fields) != 3 {
+		return 0, fmt.Errorf("invalid timestamp %q", text)
+	}
+	hours, hoursErr := strconv.ParseUint(fields[0], 10, 16)
+	minutes, minutesErr := strconv.ParseUint(fields[1], 10, 8)
+	seconds, secondsErr := strconv.ParseFloat(fields[2], 32)
+	if (hoursErr != nil) || (minutesErr != nil) || (secondsErr != nil) || (minutes >= 60) || (seconds < 0) || (seconds >= 60) {
+		return 0, fmt.Errorf("invalid timestamp %q", text)
+	}
+	return float32(float64(hours*3600+minutes*60) + seconds), nil
+}
@@ -0,0 +1,39 @@
+package convert
+
+import (
+	goimage "image"
+	"image/color"
+
+	"github.com/inkyblackness/res/image"
+)
+
+// PaletteMapping describes how images are mapped to a palette for import.
+type PaletteMapping struct {
+	// Palette is the palette that images without palette are mapped to.
+	// With a private palette, it provides the entries of the reserved ranges.
+	Palette color.Palette
+	// Dithering specifies how colors that are not in the palette are approximated.
+	Dithering image.Dithering
+	// Reserved lists the palette ranges that are not used for the pixels of images.
+	Reserved []image.IndexRange
+	// Private requests to store a private palette with the bitmap. For images
+	// without palette, the private palette is derived from the image.
+	Private bool
+}
+
+// CanMap returns true if images without palette can be mapped.
+func (mapping PaletteMapping) CanMap() bool {
+	return mapping.Private || (mapping.Palette != nil)
+}
+
+func (mapping PaletteMapping) quantize(img goimage.Image) (*goimage.Paletted, error) {
+	palette := mapping.Palette
+	if mapping.Private {
+		palette = image.BuildPalette(img, mapping.Palette, mapping.Reserved...)
+	}
+	quantizer, err := image.NewQuantizer(palette, mapping.Dithering, mapping.Reserved...)
+	if err != nil {
+		return nil, err
+	}
+	return quantizer.Quantize(img), nil
+}
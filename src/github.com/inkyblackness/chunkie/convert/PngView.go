@@ -0,0 +1,31 @@
+package convert
+
+import (
+	"bytes"
+	"image/color"
+
+	"github.com/inkyblackness/res/chunk"
+	"github.com/inkyblackness/res/image"
+)
+
+type pngView struct {
+	palette color.Palette
+}
+
+func (view pngView) Extension() string {
+	return "png"
+}
+
+func (view pngView) Applies(data *chunk.Chunk) bool {
+	return data.ContentType == chunk.Bitmap
+}
+
+func (view pngView) Convert(data *chunk.Chunk, blockIndex int, blockData []byte) ([]byte, error) {
+	bitmap, err := image.Read(bytes.NewReader(blockData))
+	if err != nil {
+		return nil, err
+	}
+	buffer := bytes.NewBuffer(nil)
+	err = writePng(buffer, bitmap, view.palette)
+	return buffer.Bytes(), err
+}
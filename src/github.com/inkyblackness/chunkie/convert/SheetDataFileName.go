@@ -0,0 +1,11 @@
+package convert
+
+import (
+	"path"
+	"strings"
+)
+
+// sheetDataFileName returns the name of the JSON file that accompanies given sheet file.
+func sheetDataFileName(sheetFile string) string {
+	return strings.TrimSuffix(sheetFile, path.Ext(sheetFile)) + ".json"
+}
@@ -0,0 +1,71 @@
+package convert
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"os"
+
+	"github.com/inkyblackness/res/chunk"
+	"github.com/inkyblackness/res/image"
+	"github.com/inkyblackness/res/image/atlas"
+)
+
+var errNoSheetPalette = errors.New("no palette for the sheet; provide a palette file")
+
+// AtlasLayoutFileName returns the name of the JSON file that describes the
+// layout of given sheet file.
+func AtlasLayoutFileName(sheetFile string) string {
+	return sheetDataFileName(sheetFile)
+}
+
+// ToAtlas packs all bitmap blocks of given chunk into one PNG sheet and saves
+// the layout next to it. The given palette is used for the sheet. Should it be
+// nil, the first private palette of the bitmaps is used.
+func ToAtlas(sheetFile string, selectedChunk *chunk.Chunk, palette color.Palette) error {
+	bitmaps := make([]image.Bitmap, selectedChunk.BlockCount())
+	for blockID := range bitmaps {
+		blockReader, blockErr := selectedChunk.Block(blockID)
+		if blockErr != nil {
+			return fmt.Errorf("failed to access block %d: %v", blockID, blockErr)
+		}
+		blockData, dataErr := ioutil.ReadAll(blockReader)
+		if dataErr != nil {
+			return fmt.Errorf("failed to read block %d: %v", blockID, dataErr)
+		}
+		bmp, bmpErr := image.Read(bytes.NewReader(blockData))
+		if bmpErr != nil {
+			return fmt.Errorf("block %d is not a bitmap: %v", blockID, bmpErr)
+		}
+		if palette == nil {
+			palette = bmp.Palette()
+		}
+		bitmaps[blockID] = bmp
+	}
+	if palette == nil {
+		return errNoSheetPalette
+	}
+
+	sheet, layout, packErr := atlas.Pack(bitmaps, palette)
+	if packErr != nil {
+		return packErr
+	}
+	layoutData, layoutErr := json.MarshalIndent(layout, "", "  ")
+	if layoutErr != nil {
+		return layoutErr
+	}
+	file, fileErr := os.Create(sheetFile)
+	if fileErr != nil {
+		return fileErr
+	}
+	defer file.Close()
+	encodeErr := png.Encode(file, sheet)
+	if encodeErr != nil {
+		return encodeErr
+	}
+	return ioutil.WriteFile(AtlasLayoutFileName(sheetFile), layoutData, os.FileMode(0644))
+}
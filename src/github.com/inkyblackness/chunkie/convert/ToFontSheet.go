@@ -0,0 +1,42 @@
+package convert
+
+import (
+	"encoding/json"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"os"
+
+	"github.com/inkyblackness/res/font"
+)
+
+// FontMetricsFileName returns the name of the JSON file that holds the metrics
+// of the font in given sheet file.
+func FontMetricsFileName(sheetFile string) string {
+	return sheetDataFileName(sheetFile)
+}
+
+// ToFontSheet saves the bitmap of given font as a PNG sheet, with the metrics
+// next to it. The given palette is used for color fonts.
+func ToFontSheet(sheetFile string, fontValue font.Font, palette color.Palette) (err error) {
+	sheet, metrics := font.ToSheet(fontValue, palette)
+	metricsData, err := json.MarshalIndent(metrics, "", "  ")
+	if err != nil {
+		return
+	}
+	file, err := os.Create(sheetFile)
+	if err != nil {
+		return
+	}
+	defer func() {
+		closeErr := file.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+	err = png.Encode(file, sheet)
+	if err != nil {
+		return
+	}
+	return ioutil.WriteFile(FontMetricsFileName(sheetFile), metricsData, os.FileMode(0644))
+}
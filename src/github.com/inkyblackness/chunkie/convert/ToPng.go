@@ -1,29 +1,27 @@
 package convert
 
 import (
-	"bytes"
 	"image/color"
 	"image/png"
+	"io"
 	"os"
 
 	"github.com/inkyblackness/res/image"
 )
 
-// ToPng extracts a bitmap from given block data and saves it to a file.
+// ToPng saves given bitmap to a file.
 // The given palette is used should the bitmap not have a private palette.
-func ToPng(fileName string, blockData []byte, palette color.Palette) (result bool) {
-	bitmap, _ := image.Read(bytes.NewReader(blockData))
+func ToPng(fileName string, bitmap image.Bitmap, palette color.Palette) (result bool) {
+	file, _ := os.Create(fileName)
 
-	if bitmap != nil {
-		img := image.FromBitmap(bitmap, palette)
-		file, _ := os.Create(fileName)
-
-		if file != nil {
-			defer file.Close()
-			png.Encode(file, img)
-			result = true
-		}
+	if file != nil {
+		defer file.Close()
+		result = writePng(file, bitmap, palette) == nil
 	}
 
 	return
 }
+
+func writePng(writer io.Writer, bitmap image.Bitmap, palette color.Palette) error {
+	return png.Encode(writer, image.FromBitmap(bitmap, palette))
+}
@@ -1,7 +1,6 @@
 package convert
 
 import (
-	"bytes"
 	"fmt"
 	"image/color"
 	"io"
@@ -9,7 +8,6 @@ import (
 	"path"
 
 	"github.com/inkyblackness/res/geometry"
-	"github.com/inkyblackness/res/geometry/command"
 )
 
 type wavefrontWriter struct {
@@ -123,38 +121,33 @@ func (writer *wavefrontWriter) TextureMapped(face geometry.TextureMappedFace) {
 	writer.vtCounter += len(face.TextureCoordinates())
 }
 
-// ToWavefrontObj extracts a geometry model from given block data and saves
-// the 3D model as a Wavefront OBJ file with accompanying material file.
-func ToWavefrontObj(fileName string, blockData []byte, palette color.Palette) (result bool) {
-	model, err := command.LoadModel(bytes.NewReader(blockData))
+// ToWavefrontObj saves given 3D model as a Wavefront OBJ file with accompanying material file.
+func ToWavefrontObj(fileName string, model geometry.Model, palette color.Palette) (result bool) {
+	objFile, _ := os.Create(fileName + ".obj")
+	mtlFile, _ := os.Create(fileName + ".mtl")
 
-	if err == nil {
-		objFile, _ := os.Create(fileName + ".obj")
-		mtlFile, _ := os.Create(fileName + ".mtl")
-
-		if objFile != nil {
-			defer objFile.Close()
-		}
-		if mtlFile != nil {
-			defer mtlFile.Close()
-		}
-		if objFile != nil && mtlFile != nil {
-			fmt.Fprintf(objFile, "mtllib %s\n", path.Base(fileName+".mtl"))
-			vertexCount := model.VertexCount()
-			for i := 0; i < vertexCount; i++ {
-				position := model.Vertex(i).Position()
-				fmt.Fprintf(objFile, "v %f %f %f\n", -position.X(), -position.Y(), -position.Z())
-			}
-
-			writer := &wavefrontWriter{
-				objFile:       objFile,
-				mtlFile:       mtlFile,
-				palette:       palette,
-				usedMaterials: make(map[string]bool)}
-			model.WalkAnchors(writer)
-
-			result = true
+	if objFile != nil {
+		defer objFile.Close()
+	}
+	if mtlFile != nil {
+		defer mtlFile.Close()
+	}
+	if objFile != nil && mtlFile != nil {
+		fmt.Fprintf(objFile, "mtllib %s\n", path.Base(fileName+".mtl"))
+		vertexCount := model.VertexCount()
+		for i := 0; i < vertexCount; i++ {
+			position := model.Vertex(i).Position()
+			fmt.Fprintf(objFile, "v %f %f %f\n", -position.X(), -position.Y(), -position.Z())
 		}
+
+		writer := &wavefrontWriter{
+			objFile:       objFile,
+			mtlFile:       mtlFile,
+			palette:       palette,
+			usedMaterials: make(map[string]bool)}
+		model.WalkAnchors(writer)
+
+		result = true
 	}
 
 	return
@@ -0,0 +1,20 @@
+package convert
+
+import (
+	"github.com/inkyblackness/res/chunk"
+	"github.com/inkyblackness/res/text"
+)
+
+type txtView struct{}
+
+func (view txtView) Extension() string {
+	return "txt"
+}
+
+func (view txtView) Applies(data *chunk.Chunk) bool {
+	return data.ContentType == chunk.Text
+}
+
+func (view txtView) Convert(data *chunk.Chunk, blockIndex int, blockData []byte) ([]byte, error) {
+	return []byte(text.DefaultCodepage().Decode(blockData)), nil
+}
@@ -0,0 +1,14 @@
+package convert
+
+import (
+	"image/color"
+
+	"github.com/inkyblackness/res/chunk/chunkfs"
+)
+
+// Views returns the views that present blocks in common file formats: .png for
+// bitmaps, .wav for audio, and .txt for texts. The given palette is used for
+// bitmaps without a private palette.
+func Views(palette color.Palette) []chunkfs.View {
+	return []chunkfs.View{pngView{palette: palette}, wavView{}, txtView{}}
+}
@@ -0,0 +1,33 @@
+package convert
+
+import (
+	"bytes"
+
+	"github.com/inkyblackness/res/audio"
+	"github.com/inkyblackness/res/chunk"
+
+	"github.com/inkyblackness/chunkie/convert/wav"
+)
+
+type wavView struct{}
+
+func (view wavView) Extension() string {
+	return "wav"
+}
+
+func (view wavView) Applies(data *chunk.Chunk) bool {
+	return data.ContentType == chunk.Sound
+}
+
+func (view wavView) Convert(data *chunk.Chunk, blockIndex int, blockData []byte) ([]byte, error) {
+	soundData, err := audio.DecodeSoundChunk(blockData)
+	if err != nil {
+		return nil, err
+	}
+	buffer := bytes.NewBuffer(nil)
+	err = wav.WriteWav(buffer, soundData)
+	if err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
@@ -1,6 +1,7 @@
 package wav
 
 import (
+	"io"
 	"os"
 
 	"github.com/inkyblackness/res/audio"
@@ -8,8 +9,26 @@ import (
 )
 
 // ExportToWav writes a file in the RIFF WAVE format, based on the provided sound data.
-func ExportToWav(fileName string, soundData audio.SoundData) {
-	file, _ := os.Create(fileName)
-	defer file.Close()
-	wav.Save(file, soundData.SampleRate(), soundData.Samples(0, soundData.SampleCount()))
+func ExportToWav(fileName string, soundData audio.SoundData) error {
+	return ExportSourceToWav(fileName, audio.SourceFromData(soundData))
+}
+
+// ExportSourceToWav writes a file in the RIFF WAVE format, streaming the samples of the provided source.
+func ExportSourceToWav(fileName string, source audio.SoundSource) (err error) {
+	file, err := os.Create(fileName)
+	if err != nil {
+		return
+	}
+	defer func() {
+		closeErr := file.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+	return wav.SaveSource(file, source)
+}
+
+// WriteWav writes the provided sound data in the RIFF WAVE format.
+func WriteWav(writer io.Writer, soundData audio.SoundData) error {
+	return wav.SaveSource(writer, audio.SourceFromData(soundData))
 }
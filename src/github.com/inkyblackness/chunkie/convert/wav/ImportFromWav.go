@@ -8,12 +8,19 @@ import (
 )
 
 // ImportFromWav reads the file identified by given name and returns a SoundData instance
-// that wraps the contained samples.
-func ImportFromWav(fileName string) audio.SoundData {
-	file, _ := os.Open(fileName)
+// in the format of the game. The sound is resampled to given rate, unless it is zero.
+// Returns nil if the file could not be read.
+func ImportFromWav(fileName string, sampleRate float32) audio.SoundData {
+	file, fileErr := os.Open(fileName)
+	if fileErr != nil {
+		return nil
+	}
 	defer file.Close()
 
-	data, _ := wav.Load(file)
+	soundData, soundErr := wav.LoadSoundData(file, sampleRate)
+	if soundErr != nil {
+		return nil
+	}
 
-	return data
+	return soundData
 }
@@ -7,17 +7,25 @@ import (
 	goImage "image"
 	"image/color"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"path"
 	"strconv"
+	"strings"
 
 	"github.com/docopt/docopt-go"
 
 	"github.com/inkyblackness/res/audio"
 	"github.com/inkyblackness/res/chunk"
+	"github.com/inkyblackness/res/chunk/chunkfs"
+	"github.com/inkyblackness/res/chunk/content"
+	"github.com/inkyblackness/res/chunk/folder"
+	"github.com/inkyblackness/res/chunk/patch"
 	"github.com/inkyblackness/res/chunk/resfile"
 	"github.com/inkyblackness/res/compress/rle"
 	"github.com/inkyblackness/res/data"
+	"github.com/inkyblackness/res/font"
+	"github.com/inkyblackness/res/geometry"
 	"github.com/inkyblackness/res/image"
 	"github.com/inkyblackness/res/movi"
 	"github.com/inkyblackness/res/serial"
@@ -26,6 +34,9 @@ import (
 	"github.com/inkyblackness/chunkie/convert/wav"
 )
 
+// contents decodes and encodes the blocks of all known content types.
+var contents = content.NewRegistry()
+
 const (
 	// Version contains the current version number
 	Version = "1.1.0"
@@ -40,7 +51,17 @@ func usage() string {
 
 Usage:
   chunkie export <resource-file> <chunk-id> [--block=<block-id>] [--raw] [--pal=<palette-file>] [--pal-id=<palette-id>] [--fps=<framerate>] [<folder>]
-  chunkie import <resource-file> <chunk-id> [--block=<block-id>] [--compressed] [--force-transparency] <source-file>
+  chunkie import <resource-file> <chunk-id> [--block=<block-id>] [--compressed] [--force-transparency] [--private-palette] [--pal=<palette-file>] [--pal-id=<palette-id>] [--dither=<method>] [--reserved=<ranges>] [--sample-rate=<rate>] <source-file>
+  chunkie atlas export <resource-file> <chunk-id> [--pal=<palette-file>] [--pal-id=<palette-id>] <sheet-file>
+  chunkie atlas import <resource-file> <chunk-id> <sheet-file>
+  chunkie font import <resource-file> <chunk-id> [--block=<block-id>] [--shades=<indices>] [--size=<pixels>] [--first=<char>] [--last=<char>] [--height=<rows>] [--baseline=<row>] [--spacing=<pixels>] <font-file>
+  chunkie media import <resource-file> <chunk-id> [--block=<block-id>] [--fps=<framerate>] [--audio=<wav-file>] [--sample-rate=<rate>] [--palettes=<schedule-file>] [--subtitles=<srt-files>] <frames>
+  chunkie validate <resource-file> [--salvage=<file>]
+  chunkie serve <resource-file> [--pal=<palette-file>] [--address=<address>]
+  chunkie unpack <resource-file> <folder>
+  chunkie pack <folder> <resource-file>
+  chunkie patch create <base-file> <modified-file> <patch-file>
+  chunkie patch apply <resource-file> <patch-file> <target-file>
   chunkie -h | --help
   chunkie --version
 
@@ -53,9 +74,32 @@ Options:
   --force-transparency   With this flag, imported bitmaps will be marked to have transparency. [default: false]
   --pal=<palette-file>   For handling bitmaps & models, use this palette file to write color information
   --pal-id=<palette-id>  Optional palette chunk identifier. If not provided, uses first palette found in palette-file.
-  --fps=<framerate>      The frames per second to emulate when exporting movies. 0 names files after timestamp. [default: 0]
+  --private-palette      With this flag, imported bitmaps get a private palette. For true-color images, it is derived from the image.
+  --dither=<method>      How true-color images are mapped to the palette: none, floyd-steinberg, or ordered. [default: floyd-steinberg]
+  --reserved=<ranges>    Palette index ranges not to be used for true-color images, such as "0x03-0x07,0x10-0x1F". Private palettes copy them from --pal.
+  --audio=<wav-file>     The audio track of imported media.
+  --sample-rate=<rate>   The sample rate imported audio is converted to, such as 11025 or 22050. 0 keeps the rate of the file. [default: 0]
+  --palettes=<schedule-file>  A JSON list of palette changes for imported media, such as [{"time": 2.5, "image": "dark.png"}].
+  --subtitles=<srt-files>     Comma separated subtitle files of imported media. Their language is taken from the name suffix _en, _fr, or _de.
+  --shades=<indices>     Comma separated palette indices for an imported color font, from least to most covered pixels. Without, the font is monochrome.
+  --size=<pixels>        The pixel size TrueType and OpenType fonts are rasterized at. [default: 8]
+  --first=<char>         The first character of an imported font. [default: 32]
+  --last=<char>          The last character of an imported font. [default: 255]
+  --height=<rows>        The height of an imported font. -1 covers ascent and descent of the source. [default: -1]
+  --baseline=<row>       The baseline of an imported font. -1 takes the ascent of the source. [default: -1]
+  --spacing=<pixels>     Additional pixels between the glyphs of an imported font. [default: 0]
+  --fps=<framerate>      The frames per second to emulate when exporting movies, or of imported PNG frames. 0 names files after timestamp. [default: 0]
   <folder>               The path of the folder to use. [default: .]
   <source-file>          The source file to import.
+  <font-file>            A BDF, TrueType, or OpenType font file to import.
+  <frames>               A folder of paletted PNG files, or an animated GIF file, for the video of imported media.
+  <sheet-file>           The PNG sheet of an atlas. Its layout is stored in a JSON file of the same base name.
+  --salvage=<file>       Write all readable chunks of the validated resource file into this new file.
+  --address=<address>    The network address to serve the resources on. [default: localhost:8080]
+  <base-file>            The original resource file a patch is based on.
+  <modified-file>        The modified resource file a patch shall produce.
+  <patch-file>           The patch file to create or apply.
+  <target-file>          The new resource file to write the patched resources into.
   -h --help              Show this screen.
   --version              Show version.
 `
@@ -65,7 +109,67 @@ func main() {
 	arguments, _ := docopt.Parse(usage(), nil, true, Title, false)
 	fmt.Printf("%v\n", arguments)
 
-	if arguments["export"].(bool) {
+	if arguments["atlas"].(bool) {
+		resourceFile := arguments["<resource-file>"].(string)
+		chunkID, _ := strconv.ParseUint(arguments["<chunk-id>"].(string), 0, 16)
+		sheetFile := arguments["<sheet-file>"].(string)
+		if arguments["export"].(bool) {
+			var palette color.Palette
+			if palArgument := arguments["--pal"]; palArgument != nil {
+				paletteID := uint64(0)
+				if palIDArgument := arguments["--pal-id"]; palIDArgument != nil {
+					paletteID, _ = strconv.ParseUint(palIDArgument.(string), 0, 16)
+				}
+				palette = loadPalette(palArgument.(string), chunk.ID(uint16(paletteID)))
+			}
+			exportAtlas(resourceFile, chunk.ID(uint16(chunkID)), palette, sheetFile)
+		} else {
+			importAtlas(resourceFile, chunk.ID(uint16(chunkID)), sheetFile)
+		}
+	} else if arguments["font"].(bool) {
+		resourceFile := arguments["<resource-file>"].(string)
+		chunkID, _ := strconv.ParseUint(arguments["<chunk-id>"].(string), 0, 16)
+		blockID, _ := strconv.ParseUint(arguments["--block"].(string), 0, 16)
+		pixelSize, _ := strconv.ParseFloat(arguments["--size"].(string), 64)
+		var options font.BuildOptions
+		var optionsErr error
+		options.FirstCharacter, optionsErr = parseFontOption(arguments, "--first", optionsErr)
+		options.LastCharacter, optionsErr = parseFontOption(arguments, "--last", optionsErr)
+		options.Height, optionsErr = parseFontOption(arguments, "--height", optionsErr)
+		options.Baseline, optionsErr = parseFontOption(arguments, "--baseline", optionsErr)
+		options.Spacing, optionsErr = parseFontOption(arguments, "--spacing", optionsErr)
+		if shadesArgument := arguments["--shades"]; (shadesArgument != nil) && (optionsErr == nil) {
+			options.Shades, optionsErr = parseIndexList(shadesArgument.(string))
+		}
+		if optionsErr != nil {
+			fmt.Printf("Invalid font option: %v\n", optionsErr)
+			return
+		}
+
+		importFont(resourceFile, chunk.ID(uint16(chunkID)), int(blockID), arguments["<font-file>"].(string),
+			pixelSize, options)
+	} else if arguments["media"].(bool) {
+		resourceFile := arguments["<resource-file>"].(string)
+		chunkID, _ := strconv.ParseUint(arguments["<chunk-id>"].(string), 0, 16)
+		blockID, _ := strconv.ParseUint(arguments["--block"].(string), 0, 16)
+		framesPerSecond, _ := strconv.ParseFloat(arguments["--fps"].(string), 32)
+		sampleRate, _ := strconv.ParseFloat(arguments["--sample-rate"].(string), 32)
+		audioFile := ""
+		if audioArgument := arguments["--audio"]; audioArgument != nil {
+			audioFile = audioArgument.(string)
+		}
+		scheduleFile := ""
+		if palettesArgument := arguments["--palettes"]; palettesArgument != nil {
+			scheduleFile = palettesArgument.(string)
+		}
+		var subtitleFiles []string
+		if subtitlesArgument := arguments["--subtitles"]; subtitlesArgument != nil {
+			subtitleFiles = strings.Split(subtitlesArgument.(string), ",")
+		}
+
+		importMedia(resourceFile, chunk.ID(uint16(chunkID)), int(blockID), arguments["<frames>"].(string),
+			float32(framesPerSecond), audioFile, float32(sampleRate), scheduleFile, subtitleFiles)
+	} else if arguments["export"].(bool) {
 		resourceFile := arguments["<resource-file>"].(string)
 		inFile, inFileErr := os.Open(resourceFile)
 		if inFileErr != nil {
@@ -142,8 +246,62 @@ func main() {
 		sourceFile := arguments["<source-file>"].(string)
 		compressed := arguments["--compressed"].(bool)
 		forceTransparency := arguments["--force-transparency"].(bool)
+		mapping := convert.PaletteMapping{Private: arguments["--private-palette"].(bool)}
+		if palArgument := arguments["--pal"]; palArgument != nil {
+			paletteID := uint64(0)
+			if palIDArgument := arguments["--pal-id"]; palIDArgument != nil {
+				paletteID, _ = strconv.ParseUint(palIDArgument.(string), 0, 16)
+			}
+			mapping.Palette = loadPalette(palArgument.(string), chunk.ID(uint16(paletteID)))
+			if mapping.Palette == nil {
+				fmt.Printf("No palette found for importing images\n")
+				return
+			}
+		}
+		var mappingErr error
+		mapping.Dithering, mappingErr = image.ParseDithering(arguments["--dither"].(string))
+		if mappingErr != nil {
+			fmt.Printf("Invalid dithering: %v\n", mappingErr)
+			return
+		}
+		if reservedArgument := arguments["--reserved"]; reservedArgument != nil {
+			mapping.Reserved, mappingErr = parseIndexRanges(reservedArgument.(string))
+			if mappingErr != nil {
+				fmt.Printf("Invalid reserved ranges: %v\n", mappingErr)
+				return
+			}
+		}
+
+		sampleRate, _ := strconv.ParseFloat(arguments["--sample-rate"].(string), 32)
+
+		importData(resourceFile, chunk.ID(uint16(chunkID)), int(blockID), sourceFile, compressed, forceTransparency, mapping,
+			float32(sampleRate))
+	} else if arguments["validate"].(bool) {
+		resourceFile := arguments["<resource-file>"].(string)
+		salvageFile := ""
+		if salvageArgument := arguments["--salvage"]; salvageArgument != nil {
+			salvageFile = salvageArgument.(string)
+		}
 
-		importData(resourceFile, chunk.ID(uint16(chunkID)), int(blockID), sourceFile, compressed, forceTransparency)
+		validateFile(resourceFile, salvageFile)
+	} else if arguments["serve"].(bool) {
+		var palette color.Palette
+		if palArgument := arguments["--pal"]; palArgument != nil {
+			palette = loadPalette(palArgument.(string), chunk.ID(0))
+		}
+
+		serveFile(arguments["<resource-file>"].(string), palette, arguments["--address"].(string))
+	} else if arguments["unpack"].(bool) {
+		unpackFile(arguments["<resource-file>"].(string), arguments["<folder>"].(string))
+	} else if arguments["pack"].(bool) {
+		packFolder(arguments["<folder>"].(string), arguments["<resource-file>"].(string))
+	} else if arguments["patch"].(bool) {
+		patchFile := arguments["<patch-file>"].(string)
+		if arguments["create"].(bool) {
+			createPatch(arguments["<base-file>"].(string), arguments["<modified-file>"].(string), patchFile)
+		} else {
+			applyPatch(arguments["<resource-file>"].(string), patchFile, arguments["<target-file>"].(string))
+		}
 	}
 }
 
@@ -163,24 +321,11 @@ func exportFile(provider chunk.Provider, selectedChunk *chunk.Chunk, blockID int
 		return
 	}
 	if !exportRaw {
-		if contentType == chunk.Sound {
-			soundData, _ := audio.DecodeSoundChunk(blockData)
-			wav.ExportToWav(outFileName+".wav", soundData)
-		} else if contentType == chunk.Media {
-			exportRaw = exportMedia(blockData, outFileName, framesPerSecond)
-		} else if contentType == chunk.Bitmap {
-			exportRaw = !convert.ToPng(outFileName+".png", blockData, palette)
-		} else if contentType == chunk.Geometry {
-			exportRaw = !convert.ToWavefrontObj(outFileName, blockData, palette)
-		} else if contentType == chunk.VideoClip {
-			exportRaw = exportVideoClip(provider, blockData, outFileName, framesPerSecond, palette)
-		} else if contentType == chunk.Text {
-			// Don't recreate whole XML for each block since convert.ToTxt merge them into one file
-			if blockID == 0 {
-				exportRaw = !convert.ToTxt(outFileName+".xml", selectedChunk)
-			}
-		} else {
+		value, decodeErr := contents.DecodeData(contentType, blockData)
+		if decodeErr != nil {
 			exportRaw = true
+		} else {
+			exportRaw = exportValue(provider, selectedChunk, blockID, value, outFileName, palette, framesPerSecond)
 		}
 	}
 	if exportRaw {
@@ -188,6 +333,39 @@ func exportFile(provider chunk.Provider, selectedChunk *chunk.Chunk, blockID int
 	}
 }
 
+// exportValue saves the decoded value of a block in the format known for its type.
+// It returns true if the type has no such format and the raw data shall be exported instead.
+func exportValue(provider chunk.Provider, selectedChunk *chunk.Chunk, blockID int, value interface{},
+	outFileName string, palette color.Palette, framesPerSecond float32) (exportRaw bool) {
+	switch typed := value.(type) {
+	case audio.SoundData:
+		if err := wav.ExportToWav(outFileName+".wav", typed); err != nil {
+			fmt.Printf("Failed to export audio: %v\n", err)
+		}
+	case movi.Container:
+		exportRaw = exportMedia(typed, outFileName, framesPerSecond)
+	case font.Font:
+		if err := convert.ToFontSheet(outFileName+".png", typed, palette); err != nil {
+			fmt.Printf("Failed to export font sheet, exporting raw data instead: %v\n", err)
+			exportRaw = true
+		}
+	case image.Bitmap:
+		exportRaw = !convert.ToPng(outFileName+".png", typed, palette)
+	case geometry.Model:
+		exportRaw = !convert.ToWavefrontObj(outFileName, typed, palette)
+	case *data.VideoClipSequence:
+		exportRaw = exportVideoClip(provider, typed, outFileName, framesPerSecond, palette)
+	case string:
+		// Don't recreate whole XML for each block since convert.ToTxt merge them into one file
+		if blockID == 0 {
+			exportRaw = !convert.ToTxt(outFileName+".xml", selectedChunk)
+		}
+	default:
+		exportRaw = true
+	}
+	return
+}
+
 func loadPalette(fileName string, paletteID chunk.Identifier) (pal color.Palette) {
 	if len(fileName) > 0 {
 		inFile, _ := os.Open(fileName)
@@ -224,19 +402,19 @@ func loadPalette(fileName string, paletteID chunk.Identifier) (pal color.Palette
 	return
 }
 
-func exportMedia(blockData []byte, fileBaseName string, framesPerSecond float32) (failed bool) {
-	container, err := movi.Read(bytes.NewReader(blockData))
-
-	if err == nil {
-		handler := newExportingMediaHandler(fileBaseName, container.MediaDuration(), framesPerSecond, float32(container.AudioSampleRate()))
-		dispatcher := movi.NewMediaDispatcher(container, handler)
-		more := true
+func exportMedia(container movi.Container, fileBaseName string, framesPerSecond float32) (failed bool) {
+	handler := newExportingMediaHandler(fileBaseName, container.MediaDuration(), framesPerSecond)
+	dispatcher := movi.NewMediaDispatcher(container, handler)
+	more := true
+	var err error
 
-		for more && err == nil {
-			more, err = dispatcher.DispatchNext()
-		}
-		if !more {
-			handler.finish()
+	for more && err == nil {
+		more, err = dispatcher.DispatchNext()
+	}
+	if !more {
+		handler.finish()
+		if audioErr := handler.exportAudio(container); audioErr != nil {
+			fmt.Printf("Failed to export audio: %v\n", audioErr)
 		}
 	}
 
@@ -246,16 +424,13 @@ func exportMedia(blockData []byte, fileBaseName string, framesPerSecond float32)
 	return
 }
 
-func exportVideoClip(provider chunk.Provider, blockData []byte, fileBaseName string, framesPerSecond float32, pal color.Palette) (failed bool) {
-	reader := bytes.NewReader(blockData)
-	sequence := data.DefaultVideoClipSequence((len(blockData) - data.VideoClipSequenceBaseSize) / data.VideoClipSequenceEntrySize)
+func exportVideoClip(provider chunk.Provider, sequence *data.VideoClipSequence, fileBaseName string, framesPerSecond float32, pal color.Palette) (failed bool) {
 	var err error
 	clipPalette := make([]color.Color, len(pal))
 
 	clipPalette[0] = color.NRGBA{R: 0, G: 0, B: 0, A: 0xFF}
 	copy(clipPalette[1:], pal[1:])
 
-	sequence.Code(serial.NewDecoder(reader))
 	{
 		var times []float32
 		mediaDuration := float32(0.0)
@@ -275,7 +450,7 @@ func exportVideoClip(provider chunk.Provider, blockData []byte, fileBaseName str
 		}
 		imageRect := goImage.Rect(0, 0, int(sequence.Width), int(sequence.Height))
 		img := goImage.NewPaletted(imageRect, clipPalette)
-		handler := newExportingMediaHandler(fileBaseName, mediaDuration, framesPerSecond, 0.0)
+		handler := newExportingMediaHandler(fileBaseName, mediaDuration, framesPerSecond)
 		for frameID := 0; frameID < framesChunk.BlockCount() && err == nil; frameID++ {
 			frameReader, frameErr := framesChunk.Block(frameID)
 			var header image.BitmapHeader
@@ -298,8 +473,394 @@ func exportVideoClip(provider chunk.Provider, blockData []byte, fileBaseName str
 	return
 }
 
+func exportAtlas(resourceFile string, chunkID chunk.Identifier, palette color.Palette, sheetFile string) {
+	inFile, inFileErr := os.Open(resourceFile)
+	if inFileErr != nil {
+		fmt.Printf("Failed to open input file: %v\n", inFileErr)
+		return
+	}
+	defer inFile.Close()
+	reader, readerErr := resfile.ReaderFrom(inFile)
+	if readerErr != nil {
+		fmt.Printf("Failed to read resources from input file: %v\n", readerErr)
+		return
+	}
+	selectedChunk, chunkErr := reader.Chunk(chunkID)
+	if chunkErr != nil {
+		fmt.Printf("Failed to read chunk %v: %v\n", chunkID, chunkErr)
+		return
+	}
+	if selectedChunk.ContentType != chunk.Bitmap {
+		fmt.Printf("Chunk %v does not contain bitmaps\n", chunkID)
+		return
+	}
+	err := convert.ToAtlas(sheetFile, selectedChunk, palette)
+	if err != nil {
+		fmt.Printf("Failed to export atlas: %v\n", err)
+		return
+	}
+	fmt.Printf("Exported %d bitmap(s) into %v\n", selectedChunk.BlockCount(), sheetFile)
+}
+
+func importAtlas(resourceFile string, chunkID chunk.Identifier, sheetFile string) {
+	blocks, blocksErr := convert.FromAtlas(sheetFile)
+	if blocksErr != nil {
+		fmt.Printf("Failed to import atlas: %v\n", blocksErr)
+		return
+	}
+	inFile, inFileErr := os.Open(resourceFile)
+	if inFileErr != nil {
+		fmt.Printf("Failed to open input file: %v\n", inFileErr)
+		return
+	}
+	defer inFile.Close()
+	reader, readerErr := resfile.ReaderFrom(inFile)
+	if readerErr != nil {
+		fmt.Printf("Failed to read resources from input file: %v\n", readerErr)
+		return
+	}
+	store := chunk.NewProviderBackedStore(reader)
+	oldChunk, chunkErr := store.Chunk(chunkID)
+	if chunkErr != nil {
+		fmt.Printf("Failed to access chunk to modify: %v\n", chunkErr)
+		return
+	}
+	if oldChunk.ContentType != chunk.Bitmap {
+		fmt.Printf("Chunk %v does not contain bitmaps\n", chunkID)
+		return
+	}
+	store.Put(chunkID, &chunk.Chunk{
+		ContentType:   oldChunk.ContentType,
+		Compressed:    oldChunk.Compressed,
+		Fragmented:    oldChunk.Fragmented || (len(blocks) != 1),
+		BlockProvider: chunk.MemoryBlockProvider(blocks)})
+
+	buffer := serial.NewByteStore()
+	writeErr := resfile.Write(buffer, store)
+	if writeErr != nil {
+		fmt.Printf("Failed to re-encode data: %v\n", writeErr)
+		return
+	}
+	err := ioutil.WriteFile(resourceFile, buffer.Data(), os.FileMode(0644))
+	if err != nil {
+		fmt.Printf("Failed to save file: %v\n", err)
+		return
+	}
+	fmt.Printf("Imported %d bitmap(s) from %v\n", len(blocks), sheetFile)
+}
+
+func validateFile(resourceFile string, salvageFile string) {
+	inFile, inFileErr := os.Open(resourceFile)
+	if inFileErr != nil {
+		fmt.Printf("Failed to open input file: %v\n", inFileErr)
+		return
+	}
+	defer inFile.Close()
+	info, infoErr := inFile.Stat()
+	if infoErr != nil {
+		fmt.Printf("Failed to determine size of input file: %v\n", infoErr)
+		return
+	}
+
+	report := resfile.Validate(inFile, info.Size())
+	for _, issue := range report.Issues {
+		fmt.Printf("%v\n", issue)
+	}
+	if report.Valid() {
+		fmt.Printf("No issues found\n")
+	} else {
+		fmt.Printf("Found %d issue(s)\n", len(report.Issues))
+	}
+
+	if len(salvageFile) > 0 {
+		buffer := serial.NewByteStore()
+		salvaged, salvageErr := resfile.Salvage(inFile, info.Size(), buffer)
+		if salvageErr != nil {
+			fmt.Printf("Failed to salvage chunks: %v\n", salvageErr)
+			return
+		}
+		err := ioutil.WriteFile(salvageFile, buffer.Data(), os.FileMode(0644))
+		if err != nil {
+			fmt.Printf("Failed to save file: %v\n", err)
+			return
+		}
+		fmt.Printf("Salvaged %d chunk(s) into %v\n", len(salvaged), salvageFile)
+	}
+}
+
+func serveFile(resourceFile string, palette color.Palette, address string) {
+	inFile, inFileErr := os.Open(resourceFile)
+	if inFileErr != nil {
+		fmt.Printf("Failed to open input file: %v\n", inFileErr)
+		return
+	}
+	defer inFile.Close()
+	reader, readerErr := resfile.ReaderFrom(inFile)
+	if readerErr != nil {
+		fmt.Printf("Failed to read resources from input file: %v\n", readerErr)
+		return
+	}
+	fmt.Printf("Serving %v on http://%v/\n", resourceFile, address)
+	err := http.ListenAndServe(address, http.FileServer(http.FS(chunkfs.New(reader, convert.Views(palette)...))))
+	if err != nil {
+		fmt.Printf("Failed to serve resources: %v\n", err)
+	}
+}
+
+func unpackFile(resourceFile string, folderPath string) {
+	inFile, inFileErr := os.Open(resourceFile)
+	if inFileErr != nil {
+		fmt.Printf("Failed to open input file: %v\n", inFileErr)
+		return
+	}
+	defer inFile.Close()
+	reader, readerErr := resfile.ReaderFrom(inFile)
+	if readerErr != nil {
+		fmt.Printf("Failed to read resources from input file: %v\n", readerErr)
+		return
+	}
+	err := folder.Unpack(reader, folderPath)
+	if err != nil {
+		fmt.Printf("Failed to unpack resources: %v\n", err)
+		return
+	}
+	fmt.Printf("Unpacked %d chunk(s) into %v\n", len(reader.IDs()), folderPath)
+}
+
+func packFolder(folderPath string, resourceFile string) {
+	store, storeErr := folder.Pack(folderPath)
+	if storeErr != nil {
+		fmt.Printf("Failed to pack resources: %v\n", storeErr)
+		return
+	}
+	buffer := serial.NewByteStore()
+	writeErr := resfile.Write(buffer, store)
+	if writeErr != nil {
+		fmt.Printf("Failed to encode resources: %v\n", writeErr)
+		return
+	}
+	err := ioutil.WriteFile(resourceFile, buffer.Data(), os.FileMode(0644))
+	if err != nil {
+		fmt.Printf("Failed to save file: %v\n", err)
+		return
+	}
+	fmt.Printf("Packed %d chunk(s) into %v\n", len(store.IDs()), resourceFile)
+}
+
+func createPatch(baseFile, modifiedFile, patchFile string) {
+	baseIn, baseErr := os.Open(baseFile)
+	if baseErr != nil {
+		fmt.Printf("Failed to open base file: %v\n", baseErr)
+		return
+	}
+	defer baseIn.Close()
+	modifiedIn, modifiedErr := os.Open(modifiedFile)
+	if modifiedErr != nil {
+		fmt.Printf("Failed to open modified file: %v\n", modifiedErr)
+		return
+	}
+	defer modifiedIn.Close()
+	base, baseErr := resfile.ReaderFrom(baseIn)
+	if baseErr != nil {
+		fmt.Printf("Failed to read resources from base file: %v\n", baseErr)
+		return
+	}
+	modified, modifiedErr := resfile.ReaderFrom(modifiedIn)
+	if modifiedErr != nil {
+		fmt.Printf("Failed to read resources from modified file: %v\n", modifiedErr)
+		return
+	}
+
+	created, createErr := patch.Create(base, modified)
+	if createErr != nil {
+		fmt.Printf("Failed to create patch: %v\n", createErr)
+		return
+	}
+	buffer := serial.NewByteStore()
+	encodeErr := created.Encode(buffer)
+	if encodeErr != nil {
+		fmt.Printf("Failed to encode patch: %v\n", encodeErr)
+		return
+	}
+	err := ioutil.WriteFile(patchFile, buffer.Data(), os.FileMode(0644))
+	if err != nil {
+		fmt.Printf("Failed to save file: %v\n", err)
+		return
+	}
+	for _, change := range created.Changes {
+		fmt.Printf("%v: %v\n", change.ID, change.Kind)
+	}
+	fmt.Printf("Created patch with %d change(s)\n", len(created.Changes))
+}
+
+func applyPatch(resourceFile, patchFile, targetFile string) {
+	patchIn, patchErr := os.Open(patchFile)
+	if patchErr != nil {
+		fmt.Printf("Failed to open patch file: %v\n", patchErr)
+		return
+	}
+	defer patchIn.Close()
+	loaded, loadErr := patch.Decode(patchIn)
+	if loadErr != nil {
+		fmt.Printf("Failed to read patch: %v\n", loadErr)
+		return
+	}
+	inFile, inFileErr := os.Open(resourceFile)
+	if inFileErr != nil {
+		fmt.Printf("Failed to open input file: %v\n", inFileErr)
+		return
+	}
+	defer inFile.Close()
+	reader, readerErr := resfile.ReaderFrom(inFile)
+	if readerErr != nil {
+		fmt.Printf("Failed to read resources from input file: %v\n", readerErr)
+		return
+	}
+	store := chunk.NewProviderBackedStore(reader)
+
+	applyErr := loaded.Apply(store)
+	if applyErr != nil {
+		fmt.Printf("Failed to apply patch: %v\n", applyErr)
+		return
+	}
+	buffer := serial.NewByteStore()
+	writeErr := resfile.WritePreserving(buffer, store)
+	if writeErr != nil {
+		fmt.Printf("Failed to encode patched resources: %v\n", writeErr)
+		return
+	}
+	err := ioutil.WriteFile(targetFile, buffer.Data(), os.FileMode(0644))
+	if err != nil {
+		fmt.Printf("Failed to save file: %v\n", err)
+		return
+	}
+	fmt.Printf("Applied %d change(s)\n", len(loaded.Changes))
+}
+
+func parseIndexRanges(text string) (ranges []image.IndexRange, err error) {
+	for _, rangeText := range strings.Split(text, ",") {
+		limits := strings.SplitN(strings.TrimSpace(rangeText), "-", 2)
+		first, firstErr := strconv.ParseUint(limits[0], 0, 8)
+		last := first
+		var lastErr error
+		if len(limits) > 1 {
+			last, lastErr = strconv.ParseUint(limits[1], 0, 8)
+		}
+		if (firstErr != nil) || (lastErr != nil) || (last < first) {
+			return nil, fmt.Errorf("invalid index range %q", rangeText)
+		}
+		ranges = append(ranges, image.IndexRange{First: int(first), Count: int(last-first) + 1})
+	}
+	return
+}
+
+// parseFontOption returns the integer value of given option, unless a previous option already failed.
+func parseFontOption(arguments map[string]interface{}, name string, previousErr error) (int, error) {
+	if previousErr != nil {
+		return 0, previousErr
+	}
+	value, err := strconv.ParseInt(arguments[name].(string), 0, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value for %v: %q", name, arguments[name])
+	}
+	return int(value), nil
+}
+
+func parseIndexList(text string) (indices []byte, err error) {
+	for _, indexText := range strings.Split(text, ",") {
+		index, indexErr := strconv.ParseUint(strings.TrimSpace(indexText), 0, 8)
+		if indexErr != nil {
+			return nil, fmt.Errorf("invalid palette index %q", indexText)
+		}
+		indices = append(indices, byte(index))
+	}
+	return
+}
+
 func importData(resourceFile string, chunkID chunk.Identifier, blockID int, sourceFile string,
-	compressed, forceTransparency bool) {
+	compressed, forceTransparency bool, mapping convert.PaletteMapping, sampleRate float32) {
+	modifyBlock(resourceFile, chunkID, blockID, func(contentType chunk.ContentType) ([]byte, error) {
+		return importFile(sourceFile, contentType, compressed, forceTransparency, mapping, sampleRate), nil
+	})
+}
+
+func importMedia(resourceFile string, chunkID chunk.Identifier, blockID int, framesSource string, framesPerSecond float32,
+	audioFile string, sampleRate float32, scheduleFile string, subtitleFiles []string) {
+	var composition movi.Composition
+	var err error
+
+	composition.Frames, err = convert.FromFrames(framesSource, framesPerSecond)
+	if err != nil {
+		fmt.Printf("Failed to read frames: %v\n", err)
+		return
+	}
+	if framesPerSecond > 0 {
+		composition.Duration = float32(len(composition.Frames)) / framesPerSecond
+	}
+	if len(audioFile) > 0 {
+		composition.Sound = wav.ImportFromWav(audioFile, sampleRate)
+		if composition.Sound == nil {
+			fmt.Printf("Failed to read audio from %v\n", audioFile)
+			return
+		}
+	}
+	if len(scheduleFile) > 0 {
+		composition.Palettes, err = convert.FromPaletteSchedule(scheduleFile)
+		if err != nil {
+			fmt.Printf("Failed to read palette schedule: %v\n", err)
+			return
+		}
+	}
+	convert.MapFramesToPalettes(composition.Frames, composition.Palettes)
+	for _, subtitleFile := range subtitleFiles {
+		control, known := subtitleControlOf(subtitleFile)
+		if !known {
+			fmt.Printf("Unknown language of subtitle file %v\n", subtitleFile)
+			return
+		}
+		subtitles, subtitlesErr := convert.FromSrt(subtitleFile, control)
+		if subtitlesErr != nil {
+			fmt.Printf("Failed to read subtitles from %v: %v\n", subtitleFile, subtitlesErr)
+			return
+		}
+		composition.Subtitles = append(composition.Subtitles, subtitles...)
+	}
+
+	container, composeErr := movi.Compose(composition)
+	if composeErr != nil {
+		fmt.Printf("Failed to compose media: %v\n", composeErr)
+		return
+	}
+	buffer := bytes.NewBuffer(nil)
+	movi.Write(buffer, container)
+	modifyBlock(resourceFile, chunkID, blockID, func(contentType chunk.ContentType) ([]byte, error) {
+		if contentType != chunk.Media {
+			return nil, fmt.Errorf("chunk %v does not contain media", chunkID)
+		}
+		return buffer.Bytes(), nil
+	})
+}
+
+// importFont builds a font from given font file and stores it in a block of a font chunk.
+func importFont(resourceFile string, chunkID chunk.Identifier, blockID int, fontFile string,
+	pixelSize float64, options font.BuildOptions) {
+	data, err := convert.FromFont(fontFile, pixelSize, options)
+	if err != nil {
+		fmt.Printf("Failed to create font: %v\n", err)
+		return
+	}
+	modifyBlock(resourceFile, chunkID, blockID, func(contentType chunk.ContentType) ([]byte, error) {
+		if contentType != chunk.Font {
+			return nil, fmt.Errorf("chunk %v does not contain a font", chunkID)
+		}
+		return data, nil
+	})
+}
+
+// modifyBlock replaces a block of a chunk in the resource file with the data produced
+// for the content type of the chunk.
+func modifyBlock(resourceFile string, chunkID chunk.Identifier, blockID int,
+	produce func(contentType chunk.ContentType) ([]byte, error)) {
 	inFile, inFileErr := os.Open(resourceFile)
 	if inFileErr != nil {
 		fmt.Printf("Failed to open input file: %v\n", inFileErr)
@@ -318,7 +879,12 @@ func importData(resourceFile string, chunkID chunk.Identifier, blockID int, sour
 		fmt.Printf("Failed to access chunk to modify: %v\n", chunkErr)
 		return
 	}
-	modChunk.SetBlock(blockID, importFile(sourceFile, modChunk.ContentType, compressed, forceTransparency))
+	blockData, produceErr := produce(modChunk.ContentType)
+	if produceErr != nil {
+		fmt.Printf("Failed to import data: %v\n", produceErr)
+		return
+	}
+	modChunk.SetBlock(blockID, blockData)
 
 	buffer := serial.NewByteStore()
 	writeErr := resfile.Write(buffer, store)
@@ -333,22 +899,34 @@ func importData(resourceFile string, chunkID chunk.Identifier, blockID int, sour
 	}
 }
 
-func importFile(sourceFile string, contentType chunk.ContentType, compressed, forceTransparency bool) (data []byte) {
+func importFile(sourceFile string, contentType chunk.ContentType, compressed, forceTransparency bool,
+	mapping convert.PaletteMapping, sampleRate float32) (data []byte) {
+	var value interface{}
 	extension := path.Ext(sourceFile)
 	switch extension {
 	case ".wav":
 		{
-			soundData := wav.ImportFromWav(sourceFile)
-			if contentType == chunk.Sound {
-				data = audio.EncodeSoundChunk(soundData)
-			} else if contentType == chunk.Media {
-				data = movi.ContainSoundData(soundData)
+			soundData := wav.ImportFromWav(sourceFile, sampleRate)
+			if soundData == nil {
+				fmt.Printf("Failed to read audio from %v\n", sourceFile)
+			} else {
+				value = soundData
 			}
 		}
 	case ".png":
 		{
-			if contentType == chunk.Bitmap {
-				data = convert.FromPng(sourceFile, false, compressed, forceTransparency)
+			// A sheet with metrics next to it is a font, any other image a bitmap.
+			if _, metricsErr := os.Stat(convert.FontMetricsFileName(sourceFile)); metricsErr == nil {
+				fontValue, fontErr := convert.FromFontSheet(sourceFile)
+				if fontErr != nil {
+					fmt.Printf("Failed to read font from %v: %v\n", sourceFile, fontErr)
+				} else {
+					value = fontValue
+				}
+			} else if bmp := convert.FromPng(sourceFile, compressed, forceTransparency, mapping); bmp != nil {
+				value = bmp
+			} else if !mapping.CanMap() {
+				fmt.Printf("Images without palette require a palette file (--pal) or a private palette (--private-palette)\n")
 			}
 		}
 	default:
@@ -360,6 +938,13 @@ func importFile(sourceFile string, contentType chunk.ContentType, compressed, fo
 			}
 		}
 	}
+	if value != nil {
+		var encodeErr error
+		data, encodeErr = contents.EncodeData(contentType, value)
+		if encodeErr != nil {
+			fmt.Printf("Failed to encode data: %v\n", encodeErr)
+		}
+	}
 	if data == nil {
 		fmt.Printf("No data produced from source file - Is the target chunk compatible with input file?\n")
 	}
@@ -2,6 +2,7 @@ package audio
 
 import (
 	"github.com/inkyblackness/res/audio/mem"
+	"github.com/inkyblackness/res/audio/voc"
 
 	check "gopkg.in/check.v1"
 )
@@ -14,7 +15,8 @@ var _ = check.Suite(&CodeSoundChunkSuite{})
 func (suite *CodeSoundChunkSuite) TestChunkTypeReturnsProvidedValue(c *check.C) {
 	rawSoundSamples := []byte{0x00, 0x20, 0x40, 0x80, 0xC0, 0xFF}
 	rawSound := mem.NewL8SoundData(20000.0, rawSoundSamples)
-	encoded := EncodeSoundChunk(rawSound)
+	encoded, encodeErr := EncodeSoundChunk(rawSound)
+	c.Assert(encodeErr, check.IsNil)
 	decoded, err := DecodeSoundChunk(encoded)
 
 	c.Assert(err, check.IsNil)
@@ -24,3 +26,32 @@ func (suite *CodeSoundChunkSuite) TestChunkTypeReturnsProvidedValue(c *check.C)
 	samples := decoded.Samples(0, decoded.SampleCount())
 	c.Check(samples, check.DeepEquals, rawSoundSamples)
 }
+
+func (suite *CodeSoundChunkSuite) TestEncodeReturnsErrorForOversizedBlock(c *check.C) {
+	sound, soundErr := voc.NewSound([]voc.Block{voc.TextBlock{Text: string(make([]byte, 0x1000000))}})
+	c.Assert(soundErr, check.IsNil)
+
+	_, err := EncodeSoundChunk(sound)
+
+	c.Check(err, check.NotNil)
+}
+
+func (suite *CodeSoundChunkSuite) TestDecodedChunkEncodesToSameBytes(c *check.C) {
+	sound, soundErr := voc.NewSound([]voc.Block{
+		voc.SoundDataBlock{Divisor: 0x9C, Codec: voc.CodecUnsigned8, Samples: []byte{0x10, 0x20}},
+		voc.RepeatStartBlock{Count: voc.InfiniteRepeat},
+		voc.SilenceBlock{Length: 4, Divisor: 0x9C},
+		voc.MarkerBlock{ID: 1},
+		voc.RepeatEndBlock{}})
+	c.Assert(soundErr, check.IsNil)
+	encoded, encodeErr := EncodeSoundChunk(sound)
+	c.Assert(encodeErr, check.IsNil)
+	decoded, err := DecodeSoundChunk(encoded)
+
+	c.Assert(err, check.IsNil)
+
+	c.Check(decoded.SampleCount(), check.Equals, 6)
+	reencoded, reencodeErr := EncodeSoundChunk(decoded)
+	c.Assert(reencodeErr, check.IsNil)
+	c.Check(reencoded, check.DeepEquals, encoded)
+}
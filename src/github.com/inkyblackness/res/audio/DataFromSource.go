@@ -0,0 +1,43 @@
+package audio
+
+import (
+	"io"
+	"sync"
+)
+
+type sourceData struct {
+	mutex  sync.Mutex
+	source SoundSource
+}
+
+// DataFromSource returns sound data that reads the requested samples from given source when needed.
+// Requesting samples changes the position of the source. Should the source fail to provide samples,
+// the returned range is shorter than requested.
+// The returned data is safe for concurrent use, as long as the source is not used by anything else
+// while the data is in use.
+// A source that was created by SourceFromData returns the sound data it was created from.
+func DataFromSource(source SoundSource) SoundData {
+	if adapter, isAdapter := source.(*dataSource); isAdapter {
+		return adapter.data
+	}
+	return &sourceData{source: source}
+}
+
+func (data *sourceData) SampleRate() float32 {
+	return data.source.SampleRate()
+}
+
+func (data *sourceData) SampleCount() int {
+	return data.source.SampleCount()
+}
+
+func (data *sourceData) Samples(from, to int) []byte {
+	samples := make([]byte, to-from)
+	data.mutex.Lock()
+	defer data.mutex.Unlock()
+	if _, err := data.source.Seek(int64(from), io.SeekStart); err != nil {
+		return samples[:0]
+	}
+	read, _ := io.ReadFull(data.source, samples)
+	return samples[:read]
+}
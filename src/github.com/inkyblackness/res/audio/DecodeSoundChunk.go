@@ -7,6 +7,11 @@ import (
 )
 
 // DecodeSoundChunk decodes the data of a chunk of type SoundData.
+// The returned data is a *voc.Sound, which keeps all blocks of the chunk.
 func DecodeSoundChunk(data []byte) (SoundData, error) {
-	return voc.Load(bytes.NewReader(data))
+	sound, err := voc.LoadSound(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return sound, nil
 }
@@ -7,10 +7,18 @@ import (
 )
 
 // EncodeSoundChunk encodes the provided sound data into a byte array for a chunk.
-func EncodeSoundChunk(soundData SoundData) []byte {
+// A *voc.Sound is encoded with all its blocks; Any other sound data as one block of samples.
+func EncodeSoundChunk(soundData SoundData) ([]byte, error) {
 	writer := bytes.NewBuffer(nil)
 
-	voc.Save(writer, soundData.SampleRate(), soundData.Samples(0, soundData.SampleCount()))
+	if sound, isSound := soundData.(*voc.Sound); isSound {
+		err := voc.SaveSound(writer, sound)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		voc.Save(writer, soundData.SampleRate(), soundData.Samples(0, soundData.SampleCount()))
+	}
 
-	return writer.Bytes()
+	return writer.Bytes(), nil
 }
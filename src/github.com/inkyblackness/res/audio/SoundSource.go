@@ -0,0 +1,19 @@
+package audio
+
+import (
+	"io"
+)
+
+// SoundSource provides the samples of a sound in sequence, without requiring all of them in memory.
+// The format is the same as for SoundData: mono with unsigned 8-bit PCM coding.
+//
+// As one sample is one byte, Read fills the given buffer with the samples from the current position,
+// and Seek sets the position as an index of a sample. Read returns io.EOF at the end of the sound.
+type SoundSource interface {
+	io.ReadSeeker
+
+	// SampleRate returns the amount of samples per second.
+	SampleRate() float32
+	// SampleCount returns the number of samples available from this source.
+	SampleCount() int
+}
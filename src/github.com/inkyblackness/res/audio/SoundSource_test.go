@@ -0,0 +1,93 @@
+package audio
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+
+	"github.com/inkyblackness/res/audio/mem"
+
+	check "gopkg.in/check.v1"
+)
+
+type SoundSourceSuite struct {
+}
+
+var _ = check.Suite(&SoundSourceSuite{})
+
+func (suite *SoundSourceSuite) TestSourceFromDataReadsSamples(c *check.C) {
+	data := mem.NewL8SoundData(11025.0, []byte{0x10, 0x20, 0x30, 0x40})
+	source := SourceFromData(data)
+
+	c.Check(source.SampleRate(), check.Equals, float32(11025.0))
+	c.Check(source.SampleCount(), check.Equals, 4)
+	source.Seek(1, io.SeekStart)
+	samples, err := ioutil.ReadAll(source)
+	c.Assert(err, check.IsNil)
+	c.Check(samples, check.DeepEquals, []byte{0x20, 0x30, 0x40})
+}
+
+func (suite *SoundSourceSuite) TestDataFromSourceReadsRequestedSamples(c *check.C) {
+	source := SourceFromData(mem.NewL8SoundData(11025.0, []byte{0x10, 0x20, 0x30, 0x40}))
+	data := DataFromSource(&wrappedSource{source})
+
+	c.Check(data.SampleRate(), check.Equals, float32(11025.0))
+	c.Check(data.SampleCount(), check.Equals, 4)
+	c.Check(data.Samples(1, 3), check.DeepEquals, []byte{0x20, 0x30})
+	c.Check(data.Samples(0, 1), check.DeepEquals, []byte{0x10})
+}
+
+func (suite *SoundSourceSuite) TestDataFromSourceReturnsShorterRangeAtEnd(c *check.C) {
+	source := SourceFromData(mem.NewL8SoundData(11025.0, []byte{0x10, 0x20}))
+	data := DataFromSource(&wrappedSource{source})
+
+	c.Check(data.Samples(1, 4), check.DeepEquals, []byte{0x20})
+}
+
+func (suite *SoundSourceSuite) TestDataFromSourceSupportsConcurrentRequests(c *check.C) {
+	samples := make([]byte, 1000)
+	for index := range samples {
+		samples[index] = byte(index)
+	}
+	data := DataFromSource(&wrappedSource{SourceFromData(mem.NewL8SoundData(11025.0, samples))})
+	results := make(chan bool)
+
+	for routine := 0; routine < 10; routine++ {
+		go func(from int) {
+			matching := true
+			for run := 0; run < 100; run++ {
+				matching = matching && bytes.Equal(data.Samples(from, from+10), samples[from:from+10])
+			}
+			results <- matching
+		}(routine * 100)
+	}
+	for routine := 0; routine < 10; routine++ {
+		c.Check(<-results, check.Equals, true)
+	}
+}
+
+func (suite *SoundSourceSuite) TestDataFromSourceUnwrapsSourceFromData(c *check.C) {
+	data := mem.NewL8SoundData(11025.0, []byte{0x10})
+
+	c.Check(DataFromSource(SourceFromData(data)), check.Equals, data)
+}
+
+func (suite *SoundSourceSuite) TestSourceFromDataKeepsAdaptedSourceGuarded(c *check.C) {
+	source := &wrappedSource{SourceFromData(mem.NewL8SoundData(11025.0, []byte{0x10, 0x20, 0x30}))}
+	data := DataFromSource(source)
+	first := SourceFromData(data)
+	second := SourceFromData(data)
+
+	c.Assert(first, check.Not(check.Equals), source)
+	buffer := make([]byte, 2)
+	_, _ = first.Read(buffer)
+	read, _ := io.ReadFull(second, make([]byte, 3))
+	c.Check(read, check.Equals, 3)
+	_, _ = first.Read(buffer[:1])
+	c.Check(buffer[:1], check.DeepEquals, []byte{0x30})
+}
+
+// wrappedSource hides the type of the source it wraps.
+type wrappedSource struct {
+	SoundSource
+}
@@ -0,0 +1,48 @@
+package audio
+
+import (
+	"io"
+)
+
+type dataSource struct {
+	*io.SectionReader
+	data SoundData
+}
+
+// SourceFromData returns a source that reads the samples of given sound data.
+// Each returned source has its own position; Sound data that was created by DataFromSource
+// is read through its guarded access to the source it was created from.
+func SourceFromData(data SoundData) SoundSource {
+	return &dataSource{
+		SectionReader: io.NewSectionReader(dataReader{data}, 0, int64(data.SampleCount())),
+		data:          data}
+}
+
+func (source *dataSource) SampleRate() float32 {
+	return source.data.SampleRate()
+}
+
+func (source *dataSource) SampleCount() int {
+	return source.data.SampleCount()
+}
+
+// dataReader provides random access to the samples of sound data.
+type dataReader struct {
+	data SoundData
+}
+
+func (reader dataReader) ReadAt(buffer []byte, offset int64) (int, error) {
+	count := int64(reader.data.SampleCount())
+	if offset >= count {
+		return 0, io.EOF
+	}
+	end := offset + int64(len(buffer))
+	if end > count {
+		end = count
+	}
+	read := copy(buffer, reader.data.Samples(int(offset), int(end)))
+	if read < len(buffer) {
+		return read, io.EOF
+	}
+	return read, nil
+}
@@ -0,0 +1,50 @@
+package pcm
+
+import (
+	"math"
+)
+
+// ditherSeed makes the dithering, and thus conversions, reproducible.
+const ditherSeed = 0x1D1
+
+// ToL8 reduces the samples to unsigned 8-bit values, with 0x80 being silence.
+//
+// Before rounding, triangular noise of one quantization step is added. This dithering
+// turns the distortion of the reduction, which would follow the signal, into a constant
+// low noise. Samples that are exactly on a step, such as those of 8-bit data, are kept
+// as they are. Samples out of range are clipped.
+func ToL8(samples []float32) []byte {
+	return ToL8From(samples, 0)
+}
+
+// ToL8From reduces the samples like ToL8 does, for samples that start at given index of a signal.
+// The noise only depends on the index of each sample, so reducing a signal in parts results
+// in the same values as reducing it at once.
+func ToL8From(samples []float32, start int) []byte {
+	result := make([]byte, len(samples))
+	for index, sample := range samples {
+		value := float64(sample)*128.0 + 128.0
+		if value != math.Floor(value) {
+			value += ditherNoise(uint64(start + index))
+		}
+		value = math.Floor(value + 0.5)
+		if value < 0 {
+			value = 0
+		} else if value > 0xFF {
+			value = 0xFF
+		}
+		result[index] = byte(value)
+	}
+	return result
+}
+
+// ditherNoise returns triangular noise in the range (-1.0, 1.0) for the sample of given index.
+// The index is scrambled with the mixing function of SplitMix64, the two halves of which
+// provide the uniform values the noise is made of.
+func ditherNoise(index uint64) float64 {
+	mixed := ditherSeed + index*0x9E3779B97F4A7C15
+	mixed = (mixed ^ (mixed >> 30)) * 0xBF58476D1CE4E5B9
+	mixed = (mixed ^ (mixed >> 27)) * 0x94D049BB133111EB
+	mixed ^= mixed >> 31
+	return (float64(mixed>>32) - float64(mixed&0xFFFFFFFF)) / (1 << 32)
+}
@@ -0,0 +1,62 @@
+package pcm
+
+import (
+	check "gopkg.in/check.v1"
+)
+
+type QuantizeSuite struct {
+}
+
+var _ = check.Suite(&QuantizeSuite{})
+
+func (suite *QuantizeSuite) constant(value float32, count int) []float32 {
+	samples := make([]float32, count)
+	for index := range samples {
+		samples[index] = value
+	}
+	return samples
+}
+
+func (suite *QuantizeSuite) TestSilenceStaysAroundCenter(c *check.C) {
+	result := ToL8(suite.constant(0.0, 1000))
+
+	for _, value := range result {
+		c.Assert((value >= 0x7F) && (value <= 0x81), check.Equals, true, check.Commentf("value %02X", value))
+	}
+}
+
+func (suite *QuantizeSuite) TestDitheringKeepsAverageBetweenSteps(c *check.C) {
+	result := ToL8(suite.constant(0.25/128.0, 10000))
+	sum := 0
+	for _, value := range result {
+		sum += int(value) - 0x80
+	}
+
+	average := float64(sum) / float64(len(result))
+	c.Check((average > 0.2) && (average < 0.3), check.Equals, true, check.Commentf("average %v", average))
+}
+
+func (suite *QuantizeSuite) TestSamplesAreClipped(c *check.C) {
+	result := ToL8([]float32{-2.0, 2.0})
+
+	c.Check(result, check.DeepEquals, []byte{0x00, 0xFF})
+}
+
+func (suite *QuantizeSuite) TestResultIsReproducible(c *check.C) {
+	samples := suite.constant(0.1, 100)
+
+	c.Check(ToL8(samples), check.DeepEquals, ToL8(samples))
+}
+
+func (suite *QuantizeSuite) TestPartsAreReducedLikeWhole(c *check.C) {
+	samples := suite.constant(0.1, 100)
+	whole := ToL8(samples)
+
+	c.Check(append(ToL8From(samples[:30], 0), ToL8From(samples[30:], 30)...), check.DeepEquals, whole)
+}
+
+func (suite *QuantizeSuite) TestSamplesOnStepsAreKept(c *check.C) {
+	result := ToL8([]float32{-1.0, -0.5, 0.0, 0x30 / 128.0})
+
+	c.Check(result, check.DeepEquals, []byte{0x00, 0x40, 0x80, 0xB0})
+}
@@ -0,0 +1,68 @@
+package pcm
+
+import (
+	"math"
+)
+
+const (
+	// filterZeroCrossings is the amount of zero crossings of the filter on each side.
+	filterZeroCrossings = 16
+	// filterRolloff places the cutoff frequency below the Nyquist frequency, so that
+	// the transition band of the filter ends before it.
+	filterRolloff = 0.95
+)
+
+// Resample returns the signal at the given sample rate.
+//
+// The samples are interpolated with a windowed sinc filter. Its cutoff frequency is below the
+// lower of both Nyquist frequencies, so reducing the rate does not fold higher frequencies
+// back into the audible range.
+func Resample(signal Signal, sampleRate float32) Signal {
+	if (sampleRate == signal.SampleRate) || (signal.SampleCount() == 0) {
+		return signal
+	}
+	ratio := float64(sampleRate) / float64(signal.SampleRate)
+	cutoff := math.Min(1.0, ratio) * filterRolloff
+	halfWidth := filterZeroCrossings / cutoff
+	outputCount := int(math.Floor(float64(signal.SampleCount())*ratio + 0.5))
+
+	result := Signal{SampleRate: sampleRate, Channels: make([][]float32, len(signal.Channels))}
+	for channelIndex, input := range signal.Channels {
+		output := make([]float32, outputCount)
+		for index := range output {
+			center := float64(index) / ratio
+			first := int(math.Ceil(center - halfWidth))
+			last := int(math.Floor(center + halfWidth))
+			if first < 0 {
+				first = 0
+			}
+			if last >= len(input) {
+				last = len(input) - 1
+			}
+			sum := 0.0
+			for source := first; source <= last; source++ {
+				offset := center - float64(source)
+				sum += float64(input[source]) * cutoff * sinc(cutoff*offset) * blackman(offset/halfWidth)
+			}
+			output[index] = float32(sum)
+		}
+		result.Channels[channelIndex] = output
+	}
+	return result
+}
+
+func sinc(x float64) float64 {
+	if x == 0 {
+		return 1.0
+	}
+	return math.Sin(math.Pi*x) / (math.Pi * x)
+}
+
+// blackman returns the Blackman window for a position in the range of [-1.0, 1.0].
+func blackman(x float64) float64 {
+	if (x <= -1.0) || (x >= 1.0) {
+		return 0.0
+	}
+	phase := math.Pi * (x + 1.0)
+	return 0.42 - 0.5*math.Cos(phase) + 0.08*math.Cos(2*phase)
+}
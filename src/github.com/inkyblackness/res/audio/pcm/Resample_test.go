@@ -0,0 +1,72 @@
+package pcm
+
+import (
+	"math"
+
+	check "gopkg.in/check.v1"
+)
+
+type ResampleSuite struct {
+}
+
+var _ = check.Suite(&ResampleSuite{})
+
+func (suite *ResampleSuite) tone(sampleRate float32, frequency float64, count int) Signal {
+	samples := make([]float32, count)
+	for index := range samples {
+		samples[index] = float32(0.5 * math.Sin(2*math.Pi*frequency*float64(index)/float64(sampleRate)))
+	}
+	return Signal{SampleRate: sampleRate, Channels: [][]float32{samples}}
+}
+
+// amplitude returns the peak of the samples, ignoring the edges of the signal.
+func (suite *ResampleSuite) amplitude(samples []float32) float64 {
+	peak := 0.0
+	for _, sample := range samples[len(samples)/4 : len(samples)*3/4] {
+		peak = math.Max(peak, math.Abs(float64(sample)))
+	}
+	return peak
+}
+
+func (suite *ResampleSuite) TestSameRateReturnsSignal(c *check.C) {
+	signal := suite.tone(22050, 1000, 100)
+
+	c.Check(Resample(signal, 22050), check.DeepEquals, signal)
+}
+
+func (suite *ResampleSuite) TestSampleCountFollowsRatio(c *check.C) {
+	result := Resample(suite.tone(44100, 1000, 4410), 11025)
+
+	c.Check(result.SampleRate, check.Equals, float32(11025))
+	c.Check(result.SampleCount(), check.Equals, 1103)
+}
+
+func (suite *ResampleSuite) TestConstantSignalIsKept(c *check.C) {
+	samples := make([]float32, 1000)
+	for index := range samples {
+		samples[index] = 0.25
+	}
+
+	result := Resample(Signal{SampleRate: 44100, Channels: [][]float32{samples}}, 22050)
+
+	c.Check(math.Abs(suite.amplitude(result.Channels[0])-0.25) < 0.0001, check.Equals, true)
+}
+
+func (suite *ResampleSuite) TestToneBelowNyquistIsKept(c *check.C) {
+	result := Resample(suite.tone(44100, 2000, 8820), 11025)
+
+	c.Check(math.Abs(suite.amplitude(result.Channels[0])-0.5) < 0.01, check.Equals, true)
+}
+
+func (suite *ResampleSuite) TestToneAboveNyquistIsRemoved(c *check.C) {
+	result := Resample(suite.tone(44100, 8000, 8820), 11025)
+
+	c.Check(suite.amplitude(result.Channels[0]) < 0.01, check.Equals, true)
+}
+
+func (suite *ResampleSuite) TestUpsamplingKeepsTone(c *check.C) {
+	result := Resample(suite.tone(11025, 1000, 2205), 22050)
+
+	c.Check(result.SampleCount(), check.Equals, 4410)
+	c.Check(math.Abs(suite.amplitude(result.Channels[0])-0.5) < 0.01, check.Equals, true)
+}
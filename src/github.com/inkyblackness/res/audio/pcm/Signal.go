@@ -0,0 +1,32 @@
+package pcm
+
+// Signal is a sound of one or more channels. The samples are in the range of [-1.0, 1.0].
+type Signal struct {
+	// SampleRate is the amount of samples per second.
+	SampleRate float32
+	// Channels holds the samples of each channel. All channels have the same length.
+	Channels [][]float32
+}
+
+// SampleCount returns the amount of samples of each channel.
+func (signal Signal) SampleCount() int {
+	if len(signal.Channels) == 0 {
+		return 0
+	}
+	return len(signal.Channels[0])
+}
+
+// Mono returns the signal with all channels mixed into one. Each channel contributes equally.
+func (signal Signal) Mono() Signal {
+	if len(signal.Channels) <= 1 {
+		return signal
+	}
+	mixed := make([]float32, signal.SampleCount())
+	weight := 1 / float32(len(signal.Channels))
+	for _, channel := range signal.Channels {
+		for index, sample := range channel {
+			mixed[index] += sample * weight
+		}
+	}
+	return Signal{SampleRate: signal.SampleRate, Channels: [][]float32{mixed}}
+}
@@ -0,0 +1,29 @@
+package pcm
+
+import (
+	check "gopkg.in/check.v1"
+)
+
+type SignalSuite struct {
+}
+
+var _ = check.Suite(&SignalSuite{})
+
+func (suite *SignalSuite) TestSampleCountOfEmptySignalIsZero(c *check.C) {
+	c.Check(Signal{}.SampleCount(), check.Equals, 0)
+}
+
+func (suite *SignalSuite) TestMonoAveragesChannels(c *check.C) {
+	signal := Signal{SampleRate: 100, Channels: [][]float32{{1.0, 0.5, -1.0}, {0.0, 0.5, 1.0}}}
+
+	mono := signal.Mono()
+
+	c.Check(mono.SampleRate, check.Equals, float32(100))
+	c.Check(mono.Channels, check.DeepEquals, [][]float32{{0.5, 0.5, 0.0}})
+}
+
+func (suite *SignalSuite) TestMonoKeepsSingleChannel(c *check.C) {
+	signal := Signal{SampleRate: 100, Channels: [][]float32{{1.0, 0.5}}}
+
+	c.Check(signal.Mono(), check.DeepEquals, signal)
+}
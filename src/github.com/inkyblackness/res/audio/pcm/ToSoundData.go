@@ -0,0 +1,20 @@
+package pcm
+
+import (
+	"github.com/inkyblackness/res/audio/mem"
+)
+
+// ToSoundData converts the signal to the format of the game: One channel of unsigned 8-bit samples.
+// The channels are mixed, the signal is resampled to given sample rate, and the samples are reduced
+// with dithering. A sample rate of zero keeps the rate of the signal.
+func ToSoundData(signal Signal, sampleRate float32) *mem.L8SoundData {
+	mono := signal.Mono()
+	if sampleRate > 0 {
+		mono = Resample(mono, sampleRate)
+	}
+	var samples []byte
+	if len(mono.Channels) > 0 {
+		samples = ToL8(mono.Channels[0])
+	}
+	return mem.NewL8SoundData(mono.SampleRate, samples)
+}
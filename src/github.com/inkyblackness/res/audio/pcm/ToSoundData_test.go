@@ -0,0 +1,34 @@
+package pcm
+
+import (
+	check "gopkg.in/check.v1"
+)
+
+type ToSoundDataSuite struct {
+}
+
+var _ = check.Suite(&ToSoundDataSuite{})
+
+func (suite *ToSoundDataSuite) TestStereoIsMixedAndResampled(c *check.C) {
+	left := make([]float32, 400)
+	right := make([]float32, 400)
+	for index := range left {
+		left[index] = 0.5
+		right[index] = -0.5
+	}
+
+	data := ToSoundData(Signal{SampleRate: 44100, Channels: [][]float32{left, right}}, 22050)
+
+	c.Check(data.SampleRate(), check.Equals, float32(22050))
+	c.Check(data.SampleCount(), check.Equals, 200)
+	for _, value := range data.Samples(0, data.SampleCount()) {
+		c.Assert((value >= 0x7F) && (value <= 0x81), check.Equals, true)
+	}
+}
+
+func (suite *ToSoundDataSuite) TestZeroRateKeepsRate(c *check.C) {
+	data := ToSoundData(Signal{SampleRate: 8000, Channels: [][]float32{{0.0, 0.5}}}, 0)
+
+	c.Check(data.SampleRate(), check.Equals, float32(8000))
+	c.Check(data.SampleCount(), check.Equals, 2)
+}
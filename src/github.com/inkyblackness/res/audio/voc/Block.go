@@ -0,0 +1,93 @@
+package voc
+
+// Block is one entry of a Creative Voice file.
+type Block interface {
+	blockType() blockType
+}
+
+// Codec identifies how the samples of a sound data block are encoded.
+type Codec uint16
+
+const (
+	// CodecUnsigned8 is for unsigned 8-bit PCM samples.
+	CodecUnsigned8 = Codec(0x0000)
+	// CodecSigned16 is for signed 16-bit PCM samples, only used by SoundDataNewBlock.
+	CodecSigned16 = Codec(0x0004)
+)
+
+// InfiniteRepeat is the repeat count of a loop that is repeated until stopped.
+const InfiniteRepeat = uint16(0xFFFF)
+
+// SoundDataBlock contains samples, at a rate given by a frequency divisor.
+type SoundDataBlock struct {
+	Divisor byte
+	Codec   Codec
+	Samples []byte
+}
+
+// SoundContinueBlock contains further samples in the format of the previous sound data block.
+type SoundContinueBlock struct {
+	Samples []byte
+}
+
+// SilenceBlock describes a run of silent samples.
+type SilenceBlock struct {
+	// Length is the amount of silent samples, at least one.
+	Length  int
+	Divisor byte
+}
+
+// MarkerBlock marks a position within the sound.
+type MarkerBlock struct {
+	ID uint16
+}
+
+// TextBlock contains a comment.
+type TextBlock struct {
+	Text string
+}
+
+// RepeatStartBlock starts a loop that ends with a RepeatEndBlock.
+type RepeatStartBlock struct {
+	// Count is the amount of repetitions after the first play, or InfiniteRepeat.
+	Count uint16
+}
+
+// RepeatEndBlock ends the loop started by the most recent RepeatStartBlock.
+type RepeatEndBlock struct {
+}
+
+// ExtendedBlock provides the format of the next sound data block, overriding its divisor and codec.
+type ExtendedBlock struct {
+	TimeConstant uint16
+	Codec        Codec
+	// Mode is 0 for mono and 1 for stereo.
+	Mode byte
+}
+
+// SoundDataNewBlock contains samples in an explicitly described format.
+type SoundDataNewBlock struct {
+	SampleRate    uint32
+	BitsPerSample byte
+	Channels      byte
+	Codec         Codec
+	Reserved      [4]byte
+	Samples       []byte
+}
+
+// UnknownBlock keeps the data of a block of unknown type.
+type UnknownBlock struct {
+	Type byte
+	Data []byte
+}
+
+func (SoundDataBlock) blockType() blockType     { return soundData }
+func (SoundContinueBlock) blockType() blockType { return soundContinue }
+func (SilenceBlock) blockType() blockType       { return silence }
+func (MarkerBlock) blockType() blockType        { return marker }
+func (TextBlock) blockType() blockType          { return text }
+func (RepeatStartBlock) blockType() blockType   { return repeatStart }
+func (RepeatEndBlock) blockType() blockType     { return repeatEnd }
+func (ExtendedBlock) blockType() blockType      { return extended }
+func (SoundDataNewBlock) blockType() blockType  { return soundDataNew }
+func (block UnknownBlock) blockType() blockType { return blockType(block.Type) }
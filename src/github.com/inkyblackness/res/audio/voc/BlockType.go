@@ -3,6 +3,14 @@ package voc
 type blockType byte
 
 const (
-	terminator = blockType(0x00)
-	soundData  = blockType(0x01)
+	terminator    = blockType(0x00)
+	soundData     = blockType(0x01)
+	soundContinue = blockType(0x02)
+	silence       = blockType(0x03)
+	marker        = blockType(0x04)
+	text          = blockType(0x05)
+	repeatStart   = blockType(0x06)
+	repeatEnd     = blockType(0x07)
+	extended      = blockType(0x08)
+	soundDataNew  = blockType(0x09)
 )
@@ -12,6 +12,16 @@ var errNotACreativeVoiceSound = fmt.Errorf("Not a Creative Voice Sound")
 
 // Load reads from the provided source a Creative Voice Sound and returns the data.
 func Load(source io.Reader) (data *mem.L8SoundData, err error) {
+	sound, err := LoadSound(source)
+	if err == nil {
+		data = mem.NewL8SoundData(sound.SampleRate(), sound.Samples(0, sound.SampleCount()))
+	}
+
+	return
+}
+
+// LoadSound reads from the provided source a Creative Voice Sound and returns it with all its blocks.
+func LoadSound(source io.Reader) (sound *Sound, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			err = fmt.Errorf("%s", r)
@@ -22,16 +32,19 @@ func Load(source io.Reader) (data *mem.L8SoundData, err error) {
 		panic(fmt.Errorf("source is nil"))
 	}
 
-	readAndVerifyHeader(source)
-	data = readSoundData(source)
+	version, headerExtension := readAndVerifyHeader(source)
+	blocks := readBlocks(source)
+	sound, err = newSound(version, headerExtension, blocks)
+	if (err == nil) && (sound.SampleCount() == 0) {
+		panic(fmt.Errorf("No audio found"))
+	}
 
 	return
 }
 
-func readAndVerifyHeader(source io.Reader) {
+func readAndVerifyHeader(source io.Reader) (version uint16, headerExtension []byte) {
 	start := make([]byte, len(fileHeader))
 	headerSize := uint16(0)
-	version := uint16(0)
 	versionValidity := uint16(0)
 
 	source.Read(start)
@@ -44,48 +57,64 @@ func readAndVerifyHeader(source io.Reader) {
 		panic(fmt.Errorf("Version validity failed: 0x%04X != 0x%04X", calculated, versionValidity))
 	}
 
-	skip := make([]byte, headerSize-standardHeaderSize)
-	source.Read(skip)
+	if headerSize > standardHeaderSize {
+		headerExtension = make([]byte, headerSize-standardHeaderSize)
+		io.ReadFull(source, headerExtension)
+	}
+	return
 }
 
-func readSoundData(source io.Reader) (data *mem.L8SoundData) {
-	sampleRate := float32(0.0)
-	var samples []byte
-	done := false
-
-	for !done {
+func readBlocks(source io.Reader) (blocks []Block) {
+	for {
 		blockStart := make([]byte, 4)
 
-		source.Read(blockStart)
-		switch blockType(blockStart[0]) {
-		case soundData:
-			{
-				meta := make([]byte, 2)
-				source.Read(meta)
-				sampleRate = divisorToSampleRate(meta[0])
-
-				newCount := lengthFromBlockStart(blockStart) - len(meta)
-				buf := make([]byte, newCount)
-				source.Read(buf)
-
-				oldCount := len(samples)
-				newSamples := make([]byte, oldCount+newCount)
-				copy(newSamples, samples)
-				copy(newSamples[oldCount:], buf)
-				samples = newSamples
-			}
-		case terminator:
-			{
-				done = true
-			}
+		_, err := io.ReadFull(source, blockStart[:1])
+		if (err != nil) || (blockType(blockStart[0]) == terminator) {
+			return
+		}
+		_, err = io.ReadFull(source, blockStart[1:])
+		if err != nil {
+			panic(fmt.Errorf("Block header incomplete"))
+		}
+		data := make([]byte, lengthFromBlockStart(blockStart))
+		_, err = io.ReadFull(source, data)
+		if err != nil {
+			panic(fmt.Errorf("Block data incomplete"))
 		}
+		blocks = append(blocks, decodeBlock(blockType(blockStart[0]), data))
 	}
+}
 
-	if len(samples) == 0 {
-		panic(fmt.Errorf("No audio found"))
-	} else {
-		data = mem.NewL8SoundData(sampleRate, samples)
+// decodeBlock returns the block of given type. Blocks of unknown type, or with data that does not
+// match their type, are kept as UnknownBlock.
+func decodeBlock(block blockType, data []byte) Block {
+	word := func(offset int) uint16 { return binary.LittleEndian.Uint16(data[offset:]) }
+	switch {
+	case (block == soundData) && (len(data) >= 2):
+		return SoundDataBlock{Divisor: data[0], Codec: Codec(data[1]), Samples: data[2:]}
+	case block == soundContinue:
+		return SoundContinueBlock{Samples: data}
+	case (block == silence) && (len(data) == 3):
+		return SilenceBlock{Length: int(word(0)) + 1, Divisor: data[2]}
+	case (block == marker) && (len(data) == 2):
+		return MarkerBlock{ID: word(0)}
+	case (block == text) && (len(data) > 0) && (data[len(data)-1] == 0x00):
+		return TextBlock{Text: string(data[:len(data)-1])}
+	case (block == repeatStart) && (len(data) == 2):
+		return RepeatStartBlock{Count: word(0)}
+	case (block == repeatEnd) && (len(data) == 0):
+		return RepeatEndBlock{}
+	case (block == extended) && (len(data) == 4):
+		return ExtendedBlock{TimeConstant: word(0), Codec: Codec(data[2]), Mode: data[3]}
+	case (block == soundDataNew) && (len(data) >= 12):
+		newBlock := SoundDataNewBlock{
+			SampleRate:    binary.LittleEndian.Uint32(data[0:4]),
+			BitsPerSample: data[4],
+			Channels:      data[5],
+			Codec:         Codec(word(6)),
+			Samples:       data[12:]}
+		copy(newBlock.Reserved[:], data[8:12])
+		return newBlock
 	}
-
-	return
+	return UnknownBlock{Type: byte(block), Data: data}
 }
@@ -3,23 +3,44 @@ package voc
 import (
 	"bytes"
 	"encoding/binary"
+	"fmt"
 	"io"
 )
 
+// maxBlockSize is the largest amount of data a block can hold.
+const maxBlockSize = 0xFFFFFF
+
 // Save encodes the provided samples into the given writer
 func Save(writer io.Writer, sampleRate float32, samples []byte) {
-	writeHeader(writer)
+	writeHeader(writer, baseVersion, nil)
 	writeBasicSoundData(writer, sampleRate, samples)
 	writeEndOfFile(writer)
 }
 
-func writeHeader(writer io.Writer) {
-	version := baseVersion
+// SaveSound encodes the provided sound with all its blocks into the given writer.
+// A sound that was loaded is saved unchanged.
+func SaveSound(writer io.Writer, sound *Sound) error {
+	buffer := bytes.NewBuffer(nil)
+	writeHeader(buffer, sound.version, sound.headerExtension)
+	for index, block := range sound.blocks {
+		data := encodeBlock(block)
+		if len(data) > maxBlockSize {
+			return fmt.Errorf("block %d exceeds size limit with %d bytes", index, len(data))
+		}
+		writeBlockHeader(buffer, block.blockType(), len(data))
+		buffer.Write(data)
+	}
+	writeEndOfFile(buffer)
+	_, err := writer.Write(buffer.Bytes())
+	return err
+}
 
+func writeHeader(writer io.Writer, version uint16, headerExtension []byte) {
 	writer.Write(bytes.NewBufferString(fileHeader).Bytes())
-	binary.Write(writer, binary.LittleEndian, standardHeaderSize)
-	binary.Write(writer, binary.LittleEndian, baseVersion)
+	binary.Write(writer, binary.LittleEndian, standardHeaderSize+uint16(len(headerExtension)))
+	binary.Write(writer, binary.LittleEndian, version)
 	binary.Write(writer, binary.LittleEndian, uint16(uint16(^version)+versionCheckValue))
+	writer.Write(headerExtension)
 }
 
 func writeBlockHeader(writer io.Writer, block blockType, dataBytes int) {
@@ -38,3 +59,37 @@ func writeBasicSoundData(writer io.Writer, sampleRate float32, samples []byte) {
 func writeEndOfFile(writer io.Writer) {
 	writer.Write([]byte{byte(terminator)})
 }
+
+func encodeBlock(block Block) []byte {
+	buffer := bytes.NewBuffer(nil)
+	word := func(value uint16) { binary.Write(buffer, binary.LittleEndian, value) }
+	switch typed := block.(type) {
+	case SoundDataBlock:
+		buffer.Write([]byte{typed.Divisor, byte(typed.Codec)})
+		buffer.Write(typed.Samples)
+	case SoundContinueBlock:
+		buffer.Write(typed.Samples)
+	case SilenceBlock:
+		word(uint16(typed.Length - 1))
+		buffer.WriteByte(typed.Divisor)
+	case MarkerBlock:
+		word(typed.ID)
+	case TextBlock:
+		buffer.WriteString(typed.Text)
+		buffer.WriteByte(0x00)
+	case RepeatStartBlock:
+		word(typed.Count)
+	case ExtendedBlock:
+		word(typed.TimeConstant)
+		buffer.Write([]byte{byte(typed.Codec), typed.Mode})
+	case SoundDataNewBlock:
+		binary.Write(buffer, binary.LittleEndian, typed.SampleRate)
+		buffer.Write([]byte{typed.BitsPerSample, typed.Channels})
+		word(uint16(typed.Codec))
+		buffer.Write(typed.Reserved[:])
+		buffer.Write(typed.Samples)
+	case UnknownBlock:
+		buffer.Write(typed.Data)
+	}
+	return buffer.Bytes()
+}
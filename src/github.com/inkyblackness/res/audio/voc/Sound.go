@@ -0,0 +1,91 @@
+package voc
+
+// Marker is the position of a MarkerBlock within the samples of a sound.
+type Marker struct {
+	Position int
+	ID       uint16
+}
+
+// Loop is a range of samples that is repeated, as given by a pair of repeat blocks.
+type Loop struct {
+	Start int
+	End   int
+	// Count is the amount of repetitions after the first play, or InfiniteRepeat.
+	Count uint16
+}
+
+// Sound is the content of a Creative Voice file.
+//
+// It keeps all blocks, so that it can be saved unchanged. As a SoundData, it provides
+// the samples of all sound and silence blocks in sequence, with each loop contained once,
+// as unsigned 8-bit mono samples.
+type Sound struct {
+	version         uint16
+	headerExtension []byte
+	blocks          []Block
+
+	sampleRate float32
+	samples    []byte
+	markers    []Marker
+	loops      []Loop
+}
+
+// NewSound returns a sound consisting of given blocks.
+// An error is returned for samples of unsupported formats and for loops that are not closed.
+func NewSound(blocks []Block) (*Sound, error) {
+	return newSound(baseVersion, nil, blocks)
+}
+
+func newSound(version uint16, headerExtension []byte, blocks []Block) (*Sound, error) {
+	sound := &Sound{version: version, headerExtension: headerExtension, blocks: blocks}
+	err := sound.render()
+	if err != nil {
+		return nil, err
+	}
+	return sound, nil
+}
+
+// Blocks returns the blocks of the sound.
+func (sound *Sound) Blocks() []Block {
+	return append([]Block(nil), sound.blocks...)
+}
+
+// Markers returns the positions of all marker blocks.
+func (sound *Sound) Markers() []Marker {
+	return append([]Marker(nil), sound.markers...)
+}
+
+// Loops returns the ranges of all repeat blocks, in order of their end.
+func (sound *Sound) Loops() []Loop {
+	return append([]Loop(nil), sound.loops...)
+}
+
+// SampleRate returns the rate of the first sound data block.
+func (sound *Sound) SampleRate() float32 {
+	return sound.sampleRate
+}
+
+// SampleCount returns the amount of samples of all blocks.
+func (sound *Sound) SampleCount() int {
+	return len(sound.samples)
+}
+
+// Samples returns the samples in the given range.
+func (sound *Sound) Samples(from, to int) []byte {
+	return sound.samples[from:to]
+}
+
+func (sound *Sound) render() error {
+	layout, err := newSampleLayout(sound.blocks, func(index int) int { return len(samplesOf(sound.blocks[index])) })
+	if err != nil {
+		return err
+	}
+	sound.sampleRate = layout.sampleRate
+	sound.markers = layout.markers
+	sound.loops = layout.loops
+	sound.samples = make([]byte, 0, layout.sampleCount)
+	for _, seg := range layout.segments {
+		sound.samples = append(sound.samples, seg.samples(samplesOf(sound.blocks[seg.block]), seg.length)...)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package voc
+
+import (
+	"bytes"
+
+	check "gopkg.in/check.v1"
+)
+
+type SoundSuite struct {
+}
+
+var _ = check.Suite(&SoundSuite{})
+
+func (suite *SoundSuite) allBlocks() []Block {
+	return []Block{
+		TextBlock{Text: "effect"},
+		SoundDataBlock{Divisor: 0x9C, Codec: CodecUnsigned8, Samples: []byte{0x10, 0x20}},
+		MarkerBlock{ID: 7},
+		RepeatStartBlock{Count: InfiniteRepeat},
+		SoundContinueBlock{Samples: []byte{0x30}},
+		SilenceBlock{Length: 3, Divisor: 0x9C},
+		RepeatEndBlock{},
+		ExtendedBlock{TimeConstant: 0xD8F0, Codec: CodecUnsigned8, Mode: 1},
+		SoundDataBlock{Divisor: 0x00, Codec: CodecUnsigned8, Samples: []byte{0x70, 0x90, 0xFF, 0xFF}},
+		SoundDataNewBlock{SampleRate: 22050, BitsPerSample: 16, Channels: 1, Codec: CodecSigned16,
+			Reserved: [4]byte{1, 2, 3, 4}, Samples: []byte{0x00, 0x40, 0x00, 0xC0}},
+		UnknownBlock{Type: 0x0A, Data: []byte{0xAB, 0xCD}}}
+}
+
+func (suite *SoundSuite) TestSamplesContainAllSoundAndSilence(c *check.C) {
+	sound, err := NewSound(suite.allBlocks())
+
+	c.Assert(err, check.IsNil)
+	c.Check(sound.SampleRate(), check.Equals, float32(10000.0))
+	c.Check(sound.Samples(0, sound.SampleCount()), check.DeepEquals,
+		[]byte{0x10, 0x20, 0x30, 0x80, 0x80, 0x80, 0x80, 0xFF, 0xC0, 0x40})
+}
+
+func (suite *SoundSuite) TestMarkersAndLoopsReferToSamples(c *check.C) {
+	sound, err := NewSound(suite.allBlocks())
+
+	c.Assert(err, check.IsNil)
+	c.Check(sound.Markers(), check.DeepEquals, []Marker{{Position: 2, ID: 7}})
+	c.Check(sound.Loops(), check.DeepEquals, []Loop{{Start: 2, End: 6, Count: InfiniteRepeat}})
+}
+
+func (suite *SoundSuite) TestSilenceLengthIsScaledToSampleRateOfSound(c *check.C) {
+	sound, err := NewSound([]Block{
+		SoundDataBlock{Divisor: 0x9C, Codec: CodecUnsigned8, Samples: []byte{0x10}},
+		SilenceBlock{Length: 2, Divisor: 0x38},
+		MarkerBlock{ID: 1}})
+
+	c.Assert(err, check.IsNil)
+	c.Check(sound.Samples(0, sound.SampleCount()), check.DeepEquals, []byte{0x10, 0x80, 0x80, 0x80, 0x80})
+	c.Check(sound.Markers(), check.DeepEquals, []Marker{{Position: 5, ID: 1}})
+}
+
+func (suite *SoundSuite) TestNestedLoopsAreListedByEnd(c *check.C) {
+	sound, err := NewSound([]Block{
+		RepeatStartBlock{Count: 1},
+		SilenceBlock{Length: 1},
+		RepeatStartBlock{Count: 2},
+		SilenceBlock{Length: 2},
+		RepeatEndBlock{},
+		RepeatEndBlock{}})
+
+	c.Assert(err, check.IsNil)
+	c.Check(sound.Loops(), check.DeepEquals, []Loop{{Start: 1, End: 3, Count: 2}, {Start: 0, End: 3, Count: 1}})
+}
+
+func (suite *SoundSuite) TestReturnsErrorForOpenLoop(c *check.C) {
+	_, err := NewSound([]Block{RepeatStartBlock{Count: 1}, SilenceBlock{Length: 1}})
+
+	c.Check(err, check.NotNil)
+}
+
+func (suite *SoundSuite) TestReturnsErrorForUnmatchedRepeatEnd(c *check.C) {
+	_, err := NewSound([]Block{SilenceBlock{Length: 1}, RepeatEndBlock{}})
+
+	c.Check(err, check.NotNil)
+}
+
+func (suite *SoundSuite) TestReturnsErrorForContinuationWithoutSoundData(c *check.C) {
+	_, err := NewSound([]Block{SoundContinueBlock{Samples: []byte{0x80}}})
+
+	c.Check(err, check.NotNil)
+}
+
+func (suite *SoundSuite) TestReturnsErrorForUnsupportedCodec(c *check.C) {
+	_, err := NewSound([]Block{SoundDataBlock{Divisor: 0x9C, Codec: Codec(1), Samples: []byte{0x80}}})
+
+	c.Check(err, check.ErrorMatches, "block 0: unsupported codec 1")
+}
+
+func (suite *SoundSuite) TestSaveAndLoadKeepsBlocks(c *check.C) {
+	sound, err := NewSound(suite.allBlocks())
+	c.Assert(err, check.IsNil)
+	buffer := bytes.NewBuffer(nil)
+	c.Assert(SaveSound(buffer, sound), check.IsNil)
+
+	loaded, loadErr := LoadSound(bytes.NewReader(buffer.Bytes()))
+
+	c.Assert(loadErr, check.IsNil)
+	c.Check(loaded.Blocks(), check.DeepEquals, suite.allBlocks())
+}
+
+func (suite *SoundSuite) TestLoadAndSaveIsIdentical(c *check.C) {
+	writer := (&LoadSuite{}).newHeader()
+	writer.Bytes()[20] = 0x1C // header size
+	writer.Write([]byte{0xEE, 0xFF})
+	writer.Write([]byte{0x06, 0x02, 0x00, 0x00, 0x03, 0x00})                   // repeat start
+	writer.Write([]byte{0x01, 0x04, 0x00, 0x00, 0x9C, 0x00, 0x80, 0x81})       // sound data
+	writer.Write([]byte{0x03, 0x03, 0x00, 0x00, 0x09, 0x00, 0x9C})             // silence
+	writer.Write([]byte{0x07, 0x00, 0x00, 0x00})                               // repeat end
+	writer.Write([]byte{0x04, 0x03, 0x00, 0x00, 0x01, 0x02, 0x03})             // marker of wrong size
+	writer.Write([]byte{0x05, 0x04, 0x00, 0x00, 0x61, 0x62, 0x63, 0x00, 0x00}) // text
+	input := writer.Bytes()
+
+	sound, err := LoadSound(bytes.NewReader(input))
+	c.Assert(err, check.IsNil)
+	output := bytes.NewBuffer(nil)
+	c.Assert(SaveSound(output, sound), check.IsNil)
+
+	c.Check(output.Bytes(), check.DeepEquals, input)
+	c.Check(sound.SampleCount(), check.Equals, 12)
+	c.Check(sound.Loops(), check.DeepEquals, []Loop{{Start: 0, End: 12, Count: 3}})
+}
+
+func (suite *SoundSuite) TestLoadHandlesMissingTerminator(c *check.C) {
+	writer := (&LoadSuite{}).newHeader()
+	writer.Write([]byte{0x01, 0x03, 0x00, 0x00, 0x9C, 0x00, 0x80})
+
+	sound, err := LoadSound(bytes.NewReader(writer.Bytes()))
+
+	c.Assert(err, check.IsNil)
+	c.Check(sound.SampleCount(), check.Equals, 1)
+}
+
+func (suite *SoundSuite) TestLoadReturnsErrorForIncompleteBlock(c *check.C) {
+	writer := (&LoadSuite{}).newHeader()
+	writer.Write([]byte{0x01, 0x05, 0x00, 0x00, 0x9C, 0x00, 0x80})
+
+	_, err := LoadSound(bytes.NewReader(writer.Bytes()))
+
+	c.Check(err, check.ErrorMatches, "Block data incomplete")
+}
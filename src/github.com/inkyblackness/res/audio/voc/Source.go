@@ -0,0 +1,168 @@
+package voc
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Source provides the samples of a Creative Voice file without keeping them in memory.
+// Only the blocks without samples are loaded; The samples are read from the underlying reader
+// when they are requested. Like a Sound, a source provides each loop once.
+//
+// A source uses the position of the underlying reader and is not safe for concurrent use.
+type Source struct {
+	*io.SectionReader
+	layout *sampleLayout
+}
+
+// NewSource reads the structure of the Creative Voice file in given reader and returns a source
+// for its samples. The file is expected to start at the current position of the reader.
+func NewSource(source io.ReadSeeker) (result *Source, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%s", r)
+		}
+	}()
+
+	if source == nil {
+		panic(fmt.Errorf("source is nil"))
+	}
+
+	startPos, _ := source.Seek(0, io.SeekCurrent)
+	end, _ := source.Seek(0, io.SeekEnd)
+	source.Seek(startPos, io.SeekStart)
+	readAndVerifyHeader(source)
+	reader := &sourceReader{source: source}
+	blocks := reader.indexBlocks(end)
+	reader.layout, err = newSampleLayout(blocks, func(index int) int { return reader.dataSizes[index] })
+	if err != nil {
+		return nil, err
+	}
+	if reader.layout.sampleCount == 0 {
+		panic(fmt.Errorf("No audio found"))
+	}
+
+	result = &Source{
+		SectionReader: io.NewSectionReader(reader, 0, int64(reader.layout.sampleCount)),
+		layout:        reader.layout}
+	return
+}
+
+// SampleRate returns the rate of the first sound data block.
+func (source *Source) SampleRate() float32 {
+	return source.layout.sampleRate
+}
+
+// SampleCount returns the amount of samples of all blocks.
+func (source *Source) SampleCount() int {
+	return source.layout.sampleCount
+}
+
+// Markers returns the positions of all marker blocks.
+func (source *Source) Markers() []Marker {
+	return append([]Marker(nil), source.layout.markers...)
+}
+
+// Loops returns the ranges of all repeat blocks, in order of their end.
+func (source *Source) Loops() []Loop {
+	return append([]Loop(nil), source.layout.loops...)
+}
+
+// sourceReader provides random access to the samples of a file.
+type sourceReader struct {
+	source      io.ReadSeeker
+	layout      *sampleLayout
+	dataOffsets []int64
+	dataSizes   []int
+}
+
+// sampleDataStart returns the size of the data of a sound block that precedes its samples.
+// Returns -1 for blocks without samples.
+func sampleDataStart(block blockType) int {
+	switch block {
+	case soundData:
+		return 2
+	case soundContinue:
+		return 0
+	case soundDataNew:
+		return 12
+	}
+	return -1
+}
+
+// indexBlocks reads all blocks up to the terminator, or given end of the file, without their samples.
+func (reader *sourceReader) indexBlocks(end int64) (blocks []Block) {
+	for {
+		blockStart := make([]byte, 4)
+
+		_, err := io.ReadFull(reader.source, blockStart[:1])
+		if (err != nil) || (blockType(blockStart[0]) == terminator) {
+			return
+		}
+		_, err = io.ReadFull(reader.source, blockStart[1:])
+		if err != nil {
+			panic(fmt.Errorf("Block header incomplete"))
+		}
+		length := lengthFromBlockStart(blockStart)
+		dataStart := sampleDataStart(blockType(blockStart[0]))
+		if length < dataStart {
+			dataStart = -1
+		}
+		readLength := length
+		if dataStart >= 0 {
+			readLength = dataStart
+		}
+		data := make([]byte, readLength)
+		_, err = io.ReadFull(reader.source, data)
+		if err != nil {
+			panic(fmt.Errorf("Block data incomplete"))
+		}
+		offset, _ := reader.source.Seek(0, io.SeekCurrent)
+		dataSize := length - readLength
+		if offset+int64(dataSize) > end {
+			panic(fmt.Errorf("Block data incomplete"))
+		}
+		reader.source.Seek(int64(dataSize), io.SeekCurrent)
+
+		blocks = append(blocks, decodeBlock(blockType(blockStart[0]), data))
+		reader.dataOffsets = append(reader.dataOffsets, offset)
+		reader.dataSizes = append(reader.dataSizes, dataSize)
+	}
+}
+
+func (reader *sourceReader) ReadAt(buffer []byte, offset int64) (read int, err error) {
+	position := int(offset)
+	for (read < len(buffer)) && (err == nil) {
+		if position >= reader.layout.sampleCount {
+			return read, io.EOF
+		}
+		seg := reader.segmentAt(position)
+		count := seg.start + seg.length - position
+		if count > len(buffer)-read {
+			count = len(buffer) - read
+		}
+		var data []byte
+		if seg.frameSize > 0 {
+			data = make([]byte, count*seg.frameSize)
+			_, err = reader.source.Seek(reader.dataOffsets[seg.block]+int64((position-seg.start)*seg.frameSize), io.SeekStart)
+			if err == nil {
+				_, err = io.ReadFull(reader.source, data)
+			}
+		}
+		if err == nil {
+			read += copy(buffer[read:], seg.samples(data, count))
+			position += count
+		}
+	}
+	return
+}
+
+// segmentAt returns the segment that contains the sample at given position.
+func (reader *sourceReader) segmentAt(position int) segment {
+	segments := reader.layout.segments
+	index := sort.Search(len(segments), func(index int) bool {
+		return segments[index].start+segments[index].length > position
+	})
+	return segments[index]
+}
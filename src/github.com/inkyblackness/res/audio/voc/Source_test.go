@@ -0,0 +1,71 @@
+package voc
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+
+	check "gopkg.in/check.v1"
+)
+
+type SourceSuite struct {
+	sounds SoundSuite
+}
+
+var _ = check.Suite(&SourceSuite{})
+
+func (suite *SourceSuite) file(c *check.C) []byte {
+	sound, err := NewSound(suite.sounds.allBlocks())
+	c.Assert(err, check.IsNil)
+	buffer := bytes.NewBuffer(nil)
+	c.Assert(SaveSound(buffer, sound), check.IsNil)
+	return buffer.Bytes()
+}
+
+func (suite *SourceSuite) TestReturnsErrorOnNil(c *check.C) {
+	_, err := NewSource(nil)
+
+	c.Check(err, check.ErrorMatches, "source is nil")
+}
+
+func (suite *SourceSuite) TestProvidesSameAsSound(c *check.C) {
+	data := suite.file(c)
+	sound, _ := LoadSound(bytes.NewReader(data))
+
+	source, err := NewSource(bytes.NewReader(data))
+	c.Assert(err, check.IsNil)
+	samples, readErr := ioutil.ReadAll(source)
+
+	c.Assert(readErr, check.IsNil)
+	c.Check(source.SampleRate(), check.Equals, sound.SampleRate())
+	c.Check(source.SampleCount(), check.Equals, sound.SampleCount())
+	c.Check(samples, check.DeepEquals, sound.Samples(0, sound.SampleCount()))
+	c.Check(source.Markers(), check.DeepEquals, sound.Markers())
+	c.Check(source.Loops(), check.DeepEquals, sound.Loops())
+}
+
+func (suite *SourceSuite) TestReadsAnyRange(c *check.C) {
+	data := suite.file(c)
+	sound, _ := LoadSound(bytes.NewReader(data))
+	source, _ := NewSource(bytes.NewReader(data))
+	count := sound.SampleCount()
+
+	for from := 0; from < count; from++ {
+		for to := from + 1; to <= count; to++ {
+			buffer := make([]byte, to-from)
+			source.Seek(int64(from), io.SeekStart)
+			_, err := io.ReadFull(source, buffer)
+			c.Assert(err, check.IsNil)
+			c.Check(buffer, check.DeepEquals, sound.Samples(from, to), check.Commentf("range [%d, %d[", from, to))
+		}
+	}
+}
+
+func (suite *SourceSuite) TestReturnsErrorForIncompleteBlock(c *check.C) {
+	writer := (&LoadSuite{}).newHeader()
+	writer.Write([]byte{0x01, 0x05, 0x00, 0x00, 0x9C, 0x00, 0x80})
+
+	_, err := NewSource(bytes.NewReader(writer.Bytes()))
+
+	c.Check(err, check.ErrorMatches, "Block data incomplete")
+}
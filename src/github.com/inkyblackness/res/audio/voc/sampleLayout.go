@@ -0,0 +1,174 @@
+package voc
+
+import (
+	"fmt"
+)
+
+// extendedRateBase is the base of the time constant of extended blocks.
+const extendedRateBase float32 = 256000000.0
+
+// silenceSample is the value of a sample within a silence block.
+const silenceSample byte = 0x80
+
+// segment is a range of samples that stems from one block.
+type segment struct {
+	block  int
+	start  int
+	length int
+
+	// frameSize is the amount of bytes in the block for one sample. Zero for silence.
+	frameSize int
+	convert   func([]byte) []byte
+}
+
+// sampleLayout describes how the blocks of a sound form its samples.
+type sampleLayout struct {
+	sampleRate  float32
+	sampleCount int
+	segments    []segment
+	markers     []Marker
+	loops       []Loop
+}
+
+// samplesOf returns the sample data of given block, nil if it has none.
+func samplesOf(block Block) []byte {
+	switch typed := block.(type) {
+	case SoundDataBlock:
+		return typed.Samples
+	case SoundDataNewBlock:
+		return typed.Samples
+	case SoundContinueBlock:
+		return typed.Samples
+	}
+	return nil
+}
+
+// newSampleLayout arranges the samples of given blocks. The size of the sample data of a block is
+// queried separately, so that the blocks do not need to hold the data.
+func newSampleLayout(blocks []Block, sampleDataSize func(index int) int) (*sampleLayout, error) {
+	layout := &sampleLayout{}
+	var pendingFormat *ExtendedBlock
+	var convert func([]byte) []byte
+	var frameSize int
+	var openLoops []Loop
+	setSampleRate := func(rate float32) {
+		if layout.sampleRate == 0 {
+			layout.sampleRate = rate
+		}
+	}
+	addSegment := func(index int, length int) {
+		layout.segments = append(layout.segments,
+			segment{block: index, start: layout.sampleCount, length: length, frameSize: frameSize, convert: convert})
+		layout.sampleCount += length
+	}
+
+	for index, block := range blocks {
+		switch typed := block.(type) {
+		case SoundDataBlock:
+			rate := divisorToSampleRate(typed.Divisor)
+			codec := typed.Codec
+			channels := 1
+			if pendingFormat != nil {
+				channels = int(pendingFormat.Mode) + 1
+				rate = extendedRateBase / float32(0x10000-int(pendingFormat.TimeConstant)) / float32(channels)
+				codec = pendingFormat.Codec
+				pendingFormat = nil
+			}
+			convert, frameSize = converterFor(codec, 8, channels)
+			if convert == nil {
+				return nil, fmt.Errorf("block %d: unsupported codec %d", index, codec)
+			}
+			setSampleRate(rate)
+			addSegment(index, sampleDataSize(index)/frameSize)
+		case SoundDataNewBlock:
+			convert, frameSize = converterFor(typed.Codec, int(typed.BitsPerSample), int(typed.Channels))
+			if convert == nil {
+				return nil, fmt.Errorf("block %d: unsupported codec %d with %d bits and %d channels",
+					index, typed.Codec, typed.BitsPerSample, typed.Channels)
+			}
+			setSampleRate(float32(typed.SampleRate))
+			addSegment(index, sampleDataSize(index)/frameSize)
+		case SoundContinueBlock:
+			if convert == nil {
+				return nil, fmt.Errorf("block %d: continuation without sound data", index)
+			}
+			addSegment(index, sampleDataSize(index)/frameSize)
+		case SilenceBlock:
+			if (typed.Length < 1) || (typed.Length > 0x10000) {
+				return nil, fmt.Errorf("block %d: silence of %d samples out of range", index, typed.Length)
+			}
+			silenceRate := divisorToSampleRate(typed.Divisor)
+			setSampleRate(silenceRate)
+			length := int(float32(typed.Length)*layout.sampleRate/silenceRate + 0.5)
+			if length > 0 {
+				layout.segments = append(layout.segments, segment{block: index, start: layout.sampleCount, length: length})
+				layout.sampleCount += length
+			}
+		case MarkerBlock:
+			layout.markers = append(layout.markers, Marker{Position: layout.sampleCount, ID: typed.ID})
+		case RepeatStartBlock:
+			openLoops = append(openLoops, Loop{Start: layout.sampleCount, Count: typed.Count})
+		case RepeatEndBlock:
+			if len(openLoops) == 0 {
+				return nil, fmt.Errorf("block %d: repeat end without start", index)
+			}
+			loop := openLoops[len(openLoops)-1]
+			openLoops = openLoops[:len(openLoops)-1]
+			loop.End = layout.sampleCount
+			layout.loops = append(layout.loops, loop)
+		case ExtendedBlock:
+			format := typed
+			pendingFormat = &format
+		}
+	}
+	if len(openLoops) > 0 {
+		return nil, fmt.Errorf("%d loop(s) not closed", len(openLoops))
+	}
+	return layout, nil
+}
+
+// samples returns count samples of the segment, converted from given data of its block.
+// The data must start at the first of these samples. It is not used for silence.
+func (seg segment) samples(data []byte, count int) []byte {
+	if seg.frameSize == 0 {
+		result := make([]byte, count)
+		for index := range result {
+			result[index] = silenceSample
+		}
+		return result
+	}
+	return seg.convert(data[:count*seg.frameSize])
+}
+
+// converterFor returns a function that converts samples of given format to unsigned 8-bit mono,
+// together with the amount of bytes of one sample. Returns nil for unsupported formats.
+func converterFor(codec Codec, bitsPerSample int, channels int) (func([]byte) []byte, int) {
+	var sampleOf func(data []byte) int
+	switch {
+	case (codec == CodecUnsigned8) && (bitsPerSample == 8):
+		sampleOf = func(data []byte) int { return int(data[0]) - 0x80 }
+	case (codec == CodecSigned16) && (bitsPerSample == 16):
+		sampleOf = func(data []byte) int { return int(int8(data[1])) }
+	default:
+		return nil, 0
+	}
+	if channels < 1 {
+		return nil, 0
+	}
+	frameSize := channels * bitsPerSample / 8
+	if (channels == 1) && (codec == CodecUnsigned8) {
+		return func(data []byte) []byte { return data }, frameSize
+	}
+	return func(data []byte) []byte {
+		result := make([]byte, len(data)/frameSize)
+		for index := range result {
+			sum := 0
+			for channel := 0; channel < channels; channel++ {
+				offset := index*frameSize + channel*bitsPerSample/8
+				sum += sampleOf(data[offset:])
+			}
+			result[index] = byte(sum/channels + 0x80)
+		}
+		return result
+	}, frameSize
+}
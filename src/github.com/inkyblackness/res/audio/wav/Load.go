@@ -10,6 +10,8 @@ import (
 var errNotASupportedWave = fmt.Errorf("Not a supported WAV")
 
 // Load reads from the provided source and returns the data.
+// Only mono PCM data of 8 or 16 bit is supported, which is taken at its sample rate.
+// 16-bit samples are reduced by dropping their low byte; Use LoadSignal to convert any other data.
 func Load(source io.Reader) (data *mem.L8SoundData, err error) {
 	if source == nil {
 		err = fmt.Errorf("source is nil")
@@ -18,8 +20,18 @@ func Load(source io.Reader) (data *mem.L8SoundData, err error) {
 
 		loader.load(source)
 		err = loader.err
+		format := loader.format
+		if (err == nil) && ((format.base.FormatType != waveFormatTypePcm) ||
+			(format.base.Channels != 1) ||
+			((format.extension.BitsPerSample != 8) && (format.extension.BitsPerSample != 16))) {
+			err = fmt.Errorf("Unsupported WAVE format")
+		}
 		if err == nil {
-			data = mem.NewL8SoundData(loader.sampleRate, loader.samples)
+			dataConverter := l8FromL8
+			if format.extension.BitsPerSample == 16 {
+				dataConverter = l8FromL16
+			}
+			data = mem.NewL8SoundData(float32(format.base.SamplesPerSec), dataConverter(loader.samples))
 		}
 	}
 
@@ -0,0 +1,69 @@
+package wav
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/inkyblackness/res/audio/pcm"
+)
+
+// LoadSignal reads from the provided source and returns the contained sound with all its channels.
+// Supported are integer PCM data of 8, 16, 24 or 32 bit, as well as floating point data of 32 or 64 bit.
+func LoadSignal(source io.Reader) (signal pcm.Signal, err error) {
+	if source == nil {
+		return signal, fmt.Errorf("source is nil")
+	}
+	var loader waveLoader
+
+	loader.load(source)
+	if loader.err != nil {
+		return signal, loader.err
+	}
+	format := loader.format
+	decoder := sampleDecoderFor(loader.subFormat, format.extension.BitsPerSample)
+	if (decoder == nil) || (format.base.Channels == 0) {
+		return signal, fmt.Errorf("Unsupported WAVE format %d with %d bits and %d channels",
+			loader.subFormat, format.extension.BitsPerSample, format.base.Channels)
+	}
+	sampleSize := int(format.extension.BitsPerSample) / 8
+	channelCount := int(format.base.Channels)
+	frameSize := sampleSize * channelCount
+	frameCount := len(loader.samples) / frameSize
+
+	signal.SampleRate = float32(format.base.SamplesPerSec)
+	signal.Channels = make([][]float32, channelCount)
+	for channel := range signal.Channels {
+		samples := make([]float32, frameCount)
+		for frame := range samples {
+			offset := frame*frameSize + channel*sampleSize
+			samples[frame] = decoder(loader.samples[offset : offset+sampleSize])
+		}
+		signal.Channels[channel] = samples
+	}
+	return
+}
+
+type sampleDecoder func(data []byte) float32
+
+func sampleDecoderFor(formatType waveFormatType, bitsPerSample uint16) sampleDecoder {
+	switch {
+	case (formatType == waveFormatTypePcm) && (bitsPerSample == 8):
+		return func(data []byte) float32 { return (float32(data[0]) - 128.0) / 128.0 }
+	case (formatType == waveFormatTypePcm) && (bitsPerSample == 16):
+		return func(data []byte) float32 { return float32(int16(binary.LittleEndian.Uint16(data))) / (1 << 15) }
+	case (formatType == waveFormatTypePcm) && (bitsPerSample == 24):
+		return func(data []byte) float32 {
+			value := int32(uint32(data[0])<<8|uint32(data[1])<<16|uint32(data[2])<<24) >> 8
+			return float32(value) / (1 << 23)
+		}
+	case (formatType == waveFormatTypePcm) && (bitsPerSample == 32):
+		return func(data []byte) float32 { return float32(int32(binary.LittleEndian.Uint32(data))) / (1 << 31) }
+	case (formatType == waveFormatTypeIeeeFloat) && (bitsPerSample == 32):
+		return func(data []byte) float32 { return math.Float32frombits(binary.LittleEndian.Uint32(data)) }
+	case (formatType == waveFormatTypeIeeeFloat) && (bitsPerSample == 64):
+		return func(data []byte) float32 { return float32(math.Float64frombits(binary.LittleEndian.Uint64(data))) }
+	}
+	return nil
+}
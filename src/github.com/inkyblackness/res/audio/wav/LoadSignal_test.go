@@ -0,0 +1,132 @@
+package wav
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+
+	check "gopkg.in/check.v1"
+)
+
+type LoadSignalSuite struct {
+}
+
+var _ = check.Suite(&LoadSignalSuite{})
+
+// wave creates a file with given format chunk content and data, with a list chunk in between.
+func (suite *LoadSignalSuite) wave(format []byte, data []byte) []byte {
+	list := []byte("INFOISFT\x03\x00\x00\x00ab\x00")
+	buf := bytes.NewBuffer(nil)
+	writeChunk := func(chunkType riffChunkType, content []byte) {
+		binary.Write(buf, binary.LittleEndian, &riffChunkTag{ChunkType: chunkType, Size: uint32(len(content))})
+		buf.Write(content)
+		if len(content)%2 != 0 {
+			buf.WriteByte(0x00)
+		}
+	}
+	binary.Write(buf, binary.LittleEndian, &riffChunkTag{ChunkType: riffChunkTypeRiff})
+	binary.Write(buf, binary.LittleEndian, riffContentTypeWave)
+	writeChunk(riffChunkTypeFmt, format)
+	writeChunk(riffChunkType(0x5453494C), list)
+	writeChunk(riffChunkTypeData, data)
+	return buf.Bytes()
+}
+
+func (suite *LoadSignalSuite) format(formatType waveFormatType, channels uint16, bitsPerSample uint16) []byte {
+	buf := bytes.NewBuffer(nil)
+	blockAlign := channels * bitsPerSample / 8
+	binary.Write(buf, binary.LittleEndian, &waveFormat{
+		FormatType:     formatType,
+		Channels:       channels,
+		SamplesPerSec:  44100,
+		AvgBytesPerSec: 44100 * uint32(blockAlign),
+		BlockAlign:     blockAlign})
+	binary.Write(buf, binary.LittleEndian, bitsPerSample)
+	return buf.Bytes()
+}
+
+func (suite *LoadSignalSuite) TestReturnsErrorOnNil(c *check.C) {
+	_, err := LoadSignal(nil)
+
+	c.Check(err, check.NotNil)
+}
+
+func (suite *LoadSignalSuite) TestDecodesStereoL16(c *check.C) {
+	data := []byte{0x00, 0x40, 0x00, 0xC0, 0xFF, 0x7F, 0x00, 0x80}
+
+	signal, err := LoadSignal(bytes.NewReader(suite.wave(suite.format(waveFormatTypePcm, 2, 16), data)))
+
+	c.Assert(err, check.IsNil)
+	c.Check(signal.SampleRate, check.Equals, float32(44100))
+	c.Check(signal.Channels, check.DeepEquals, [][]float32{{0.5, float32(0x7FFF) / 0x8000}, {-0.5, -1.0}})
+}
+
+func (suite *LoadSignalSuite) TestDecodesL8(c *check.C) {
+	data := []byte{0x80, 0xC0, 0x00}
+
+	signal, err := LoadSignal(bytes.NewReader(suite.wave(suite.format(waveFormatTypePcm, 1, 8), data)))
+
+	c.Assert(err, check.IsNil)
+	c.Check(signal.Channels, check.DeepEquals, [][]float32{{0.0, 0.5, -1.0}})
+}
+
+func (suite *LoadSignalSuite) TestDecodesL24(c *check.C) {
+	data := []byte{0x00, 0x00, 0x40, 0x00, 0x00, 0xE0}
+
+	signal, err := LoadSignal(bytes.NewReader(suite.wave(suite.format(waveFormatTypePcm, 1, 24), data)))
+
+	c.Assert(err, check.IsNil)
+	c.Check(signal.Channels, check.DeepEquals, [][]float32{{0.5, -0.25}})
+}
+
+func (suite *LoadSignalSuite) TestDecodesFloat(c *check.C) {
+	data := make([]byte, 8)
+	binary.LittleEndian.PutUint32(data[0:4], math.Float32bits(0.75))
+	binary.LittleEndian.PutUint32(data[4:8], math.Float32bits(-0.125))
+
+	signal, err := LoadSignal(bytes.NewReader(suite.wave(suite.format(waveFormatTypeIeeeFloat, 1, 32), data)))
+
+	c.Assert(err, check.IsNil)
+	c.Check(signal.Channels, check.DeepEquals, [][]float32{{0.75, -0.125}})
+}
+
+func (suite *LoadSignalSuite) TestDecodesExtensibleFormat(c *check.C) {
+	format := suite.format(waveFormatTypeExtensible, 1, 16)
+	extension := []byte{22, 0, 16, 0, 0x04, 0x00, 0x00, 0x00, 0x01, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x10, 0x00, 0x80, 0x00, 0x00, 0xAA, 0x00, 0x38, 0x9B, 0x71}
+	data := []byte{0x00, 0x20}
+
+	signal, err := LoadSignal(bytes.NewReader(suite.wave(append(format, extension...), data)))
+
+	c.Assert(err, check.IsNil)
+	c.Check(signal.Channels, check.DeepEquals, [][]float32{{0.25}})
+}
+
+func (suite *LoadSignalSuite) TestReturnsErrorForUnsupportedFormat(c *check.C) {
+	_, err := LoadSignal(bytes.NewReader(suite.wave(suite.format(waveFormatTypePcm, 1, 12), []byte{0x00, 0x00})))
+
+	c.Check(err, check.NotNil)
+}
+
+func (suite *LoadSignalSuite) TestLoadSkipsOtherChunks(c *check.C) {
+	data, err := Load(bytes.NewReader(suite.wave(suite.format(waveFormatTypePcm, 1, 8), []byte{0x10, 0x20})))
+
+	c.Assert(err, check.IsNil)
+	c.Check(data.Samples(0, data.SampleCount()), check.DeepEquals, []byte{0x10, 0x20})
+}
+
+func (suite *LoadSignalSuite) TestLoadSoundDataMixesAndResamplesSignal(c *check.C) {
+	data := make([]byte, 4*4410)
+
+	soundData, err := LoadSoundData(bytes.NewReader(suite.wave(suite.format(waveFormatTypePcm, 2, 16), data)), 22050)
+
+	c.Assert(err, check.IsNil)
+	c.Check(soundData.SampleRate(), check.Equals, float32(22050))
+	c.Check(soundData.SampleCount(), check.Equals, 2205)
+}
+
+func (suite *LoadSignalSuite) TestLoadSoundDataReturnsErrorOnNil(c *check.C) {
+	_, err := LoadSoundData(nil, 0)
+
+	c.Check(err, check.NotNil)
+}
@@ -0,0 +1,19 @@
+package wav
+
+import (
+	"io"
+
+	"github.com/inkyblackness/res/audio/mem"
+	"github.com/inkyblackness/res/audio/pcm"
+)
+
+// LoadSoundData reads from the provided source and returns the sound in the format of the game.
+// Any data supported by LoadSignal is converted with pcm.ToSoundData, which resamples the sound
+// to given rate, unless it is zero.
+func LoadSoundData(source io.Reader, sampleRate float32) (*mem.L8SoundData, error) {
+	signal, err := LoadSignal(source)
+	if err != nil {
+		return nil, err
+	}
+	return pcm.ToSoundData(signal, sampleRate), nil
+}
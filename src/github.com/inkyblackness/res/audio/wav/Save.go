@@ -3,11 +3,33 @@ package wav
 import (
 	"encoding/binary"
 	"io"
+
+	"github.com/inkyblackness/res/audio"
 )
 
 // Save encodes the provided samples into the given writer
 func Save(writer io.Writer, sampleRate float32, samples []byte) {
-	dataSize := uint32(len(samples))
+	writeHeader(writer, sampleRate, uint32(len(samples)))
+	writer.Write(samples)
+}
+
+// SaveSource encodes all samples of the provided source into the given writer.
+// The samples are read in sequence from the start of the source, which is left at its end.
+func SaveSource(writer io.Writer, source audio.SoundSource) error {
+	sampleCount := source.SampleCount()
+	_, err := source.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+	err = writeHeader(writer, source.SampleRate(), uint32(sampleCount))
+	if err != nil {
+		return err
+	}
+	_, err = io.CopyN(writer, source, int64(sampleCount))
+	return err
+}
+
+func writeHeader(writer io.Writer, sampleRate float32, dataSize uint32) error {
 	var fmt formatHeader
 	fmtSize := fmt.size()
 	contentType := riffContentTypeWave
@@ -24,11 +46,13 @@ func Save(writer io.Writer, sampleRate float32, samples []byte) {
 
 	riffTag := riffChunkTag{riffChunkTypeRiff, tagSizes + contentTypeSize + fmtSize + dataSize}
 
-	binary.Write(writer, binary.LittleEndian, &riffTag)
-	binary.Write(writer, binary.LittleEndian, &contentType)
-	binary.Write(writer, binary.LittleEndian, &riffChunkTag{riffChunkTypeFmt, fmtSize})
-	binary.Write(writer, binary.LittleEndian, &fmt.base)
-	binary.Write(writer, binary.LittleEndian, &fmt.extension)
-	binary.Write(writer, binary.LittleEndian, &riffChunkTag{riffChunkTypeData, dataSize})
-	writer.Write(samples)
+	header := []interface{}{&riffTag, &contentType, &riffChunkTag{riffChunkTypeFmt, fmtSize},
+		&fmt.base, &fmt.extension, &riffChunkTag{riffChunkTypeData, dataSize}}
+	for _, value := range header {
+		err := binary.Write(writer, binary.LittleEndian, value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -2,6 +2,7 @@ package wav
 
 import (
 	"bytes"
+	"io"
 
 	check "gopkg.in/check.v1"
 )
@@ -35,3 +36,17 @@ func (suite *SaveSuite) TestSaveStoresBytes(c *check.C) {
 		0x05, 0x00, 0x00, 0x00, // len(data)
 		0x00, 0x40, 0x80, 0xC0, 0xFF}) // data
 }
+
+func (suite *SaveSuite) TestSaveSourceStoresSameAsSave(c *check.C) {
+	samples := []byte{0x00, 0x40, 0x80, 0xC0, 0xFF}
+	expected := bytes.NewBuffer(nil)
+	Save(expected, 22050.0, samples)
+	source, _ := NewSource(bytes.NewReader(expected.Bytes()))
+	source.Seek(3, io.SeekStart)
+	buf := bytes.NewBuffer(nil)
+
+	err := SaveSource(buf, source)
+
+	c.Assert(err, check.IsNil)
+	c.Check(buf.Bytes(), check.DeepEquals, expected.Bytes())
+}
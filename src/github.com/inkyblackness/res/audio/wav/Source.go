@@ -0,0 +1,104 @@
+package wav
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/inkyblackness/res/audio/pcm"
+)
+
+// Source provides the samples of a WAVE file without keeping them in memory.
+// The samples are read from the underlying reader when they are requested.
+//
+// All formats of LoadSignal are supported. The channels are mixed to one, and the samples are reduced
+// to unsigned 8-bit like pcm.ToSoundData does, at the sample rate of the file. A source uses the position of the underlying
+// reader and is not safe for concurrent use.
+type Source struct {
+	*io.SectionReader
+	sampleRate  float32
+	sampleCount int
+}
+
+// NewSource reads the format of the WAVE file in given reader and returns a source for its samples.
+func NewSource(source io.ReadSeeker) (*Source, error) {
+	if source == nil {
+		return nil, fmt.Errorf("source is nil")
+	}
+	var loader waveLoader
+
+	startPos, _ := source.Seek(0, io.SeekCurrent)
+	end, _ := source.Seek(0, io.SeekEnd)
+	source.Seek(startPos, io.SeekStart)
+	loader.index(source)
+	if loader.err != nil {
+		return nil, loader.err
+	}
+	if !loader.dataRead || !loader.formatRead {
+		return nil, errNotASupportedWave
+	}
+	if loader.dataOffset+int64(loader.dataSize) > end {
+		return nil, io.ErrUnexpectedEOF
+	}
+	format := loader.format
+	decoder := sampleDecoderFor(loader.subFormat, format.extension.BitsPerSample)
+	if (decoder == nil) || (format.base.Channels == 0) {
+		return nil, fmt.Errorf("Unsupported WAVE format %d with %d bits and %d channels",
+			loader.subFormat, format.extension.BitsPerSample, format.base.Channels)
+	}
+	reader := &sourceReader{
+		source:     source,
+		dataOffset: loader.dataOffset,
+		decoder:    decoder,
+		channels:   int(format.base.Channels),
+		sampleSize: int(format.extension.BitsPerSample) / 8}
+	sampleCount := int(loader.dataSize) / (reader.channels * reader.sampleSize)
+
+	return &Source{
+		SectionReader: io.NewSectionReader(reader, 0, int64(sampleCount)),
+		sampleRate:    float32(format.base.SamplesPerSec),
+		sampleCount:   sampleCount}, nil
+}
+
+// SampleRate returns the amount of samples per second.
+func (source *Source) SampleRate() float32 {
+	return source.sampleRate
+}
+
+// SampleCount returns the amount of samples of the file.
+func (source *Source) SampleCount() int {
+	return source.sampleCount
+}
+
+// sourceReader provides random access to the mixed samples of a file.
+type sourceReader struct {
+	source     io.ReadSeeker
+	dataOffset int64
+	decoder    sampleDecoder
+	channels   int
+	sampleSize int
+}
+
+// ReadAt reads the samples of the given range. The range is limited by the section reader of the source.
+func (reader *sourceReader) ReadAt(buffer []byte, offset int64) (read int, err error) {
+	frameSize := reader.channels * reader.sampleSize
+	data := make([]byte, len(buffer)*frameSize)
+
+	_, err = reader.source.Seek(reader.dataOffset+offset*int64(frameSize), io.SeekStart)
+	if err == nil {
+		_, err = io.ReadFull(reader.source, data)
+	}
+	if err != nil {
+		return 0, err
+	}
+	mixed := make([]float32, len(buffer))
+	for index := range mixed {
+		sum := float32(0.0)
+		for channel := 0; channel < reader.channels; channel++ {
+			start := index*frameSize + channel*reader.sampleSize
+			sum += reader.decoder(data[start : start+reader.sampleSize])
+		}
+		mixed[index] = sum / float32(reader.channels)
+	}
+	copy(buffer, pcm.ToL8From(mixed, int(offset)))
+	return len(buffer), nil
+}
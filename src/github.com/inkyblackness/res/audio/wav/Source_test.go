@@ -0,0 +1,70 @@
+package wav
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+
+	check "gopkg.in/check.v1"
+)
+
+type SourceSuite struct {
+	files LoadSignalSuite
+}
+
+var _ = check.Suite(&SourceSuite{})
+
+func (suite *SourceSuite) TestReturnsErrorOnNil(c *check.C) {
+	_, err := NewSource(nil)
+
+	c.Check(err, check.NotNil)
+}
+
+func (suite *SourceSuite) TestProvidesSameSamplesAsLoad(c *check.C) {
+	data := suite.files.wave(suite.files.format(waveFormatTypePcm, 1, 16), []byte{0x00, 0x40, 0x00, 0xC0, 0x00, 0x00})
+	loaded, _ := Load(bytes.NewReader(data))
+
+	source, err := NewSource(bytes.NewReader(data))
+	c.Assert(err, check.IsNil)
+	samples, readErr := ioutil.ReadAll(source)
+
+	c.Assert(readErr, check.IsNil)
+	c.Check(source.SampleRate(), check.Equals, loaded.SampleRate())
+	c.Check(source.SampleCount(), check.Equals, loaded.SampleCount())
+	c.Check(samples, check.DeepEquals, loaded.Samples(0, loaded.SampleCount()))
+}
+
+func (suite *SourceSuite) TestMixesChannels(c *check.C) {
+	data := suite.files.wave(suite.files.format(waveFormatTypePcm, 2, 8), []byte{0x80, 0xC0, 0x00, 0x40, 0xFF, 0xFF})
+
+	source, err := NewSource(bytes.NewReader(data))
+	c.Assert(err, check.IsNil)
+	samples, _ := ioutil.ReadAll(source)
+
+	c.Check(samples, check.DeepEquals, []byte{0xA0, 0x20, 0xFF})
+}
+
+func (suite *SourceSuite) TestReadsFromSeekPosition(c *check.C) {
+	data := suite.files.wave(suite.files.format(waveFormatTypePcm, 1, 8), []byte{0x10, 0x20, 0x30, 0x40, 0x50})
+	source, _ := NewSource(bytes.NewReader(data))
+	buffer := make([]byte, 2)
+
+	source.Seek(-3, io.SeekEnd)
+	read, err := source.Read(buffer)
+	c.Check(read, check.Equals, 2)
+	c.Check(err, check.IsNil)
+	c.Check(buffer, check.DeepEquals, []byte{0x30, 0x40})
+
+	read, _ = source.Read(buffer)
+	c.Check(buffer[:read], check.DeepEquals, []byte{0x50})
+	_, err = source.Read(buffer)
+	c.Check(err, check.Equals, io.EOF)
+}
+
+func (suite *SourceSuite) TestReturnsErrorForIncompleteData(c *check.C) {
+	data := suite.files.wave(suite.files.format(waveFormatTypePcm, 1, 8), []byte{0x10, 0x20, 0x30, 0x40})
+
+	_, err := NewSource(bytes.NewReader(data[:len(data)-1]))
+
+	c.Check(err, check.NotNil)
+}
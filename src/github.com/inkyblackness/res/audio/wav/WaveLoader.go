@@ -3,7 +3,6 @@ package wav
 import (
 	"bytes"
 	"encoding/binary"
-	"fmt"
 	"io"
 )
 
@@ -25,21 +24,30 @@ func l8FromL16(input []byte) []byte {
 type waveLoader struct {
 	dataRead   bool
 	formatRead bool
+	format     formatHeader
+	subFormat  waveFormatType
 	samples    []byte
-	sampleRate float32
+
+	// dataOffset and dataSize locate the samples within the source if they are indexed only.
+	dataOffset int64
+	dataSize   uint32
 
 	reader io.Reader
+	seeker io.Seeker
 	err    error
-
-	dataConverter func([]byte) []byte
 }
 
 func (loader *waveLoader) load(reader io.Reader) {
 	loader.reader = reader
-	loader.dataConverter = l8FromL8
 	loader.loadRiff()
 }
 
+// index loads the format from given source, yet only determines the location of the samples.
+func (loader *waveLoader) index(source io.ReadSeeker) {
+	loader.seeker = source
+	loader.load(source)
+}
+
 func (loader *waveLoader) read(data interface{}) bool {
 	if loader.err == nil {
 		loader.err = binary.Read(loader.reader, binary.LittleEndian, data)
@@ -49,7 +57,9 @@ func (loader *waveLoader) read(data interface{}) bool {
 
 func (loader *waveLoader) readBytes(size uint32) (data []byte) {
 	data = make([]byte, int(size))
-	_, loader.err = loader.reader.Read(data)
+	if loader.err == nil {
+		_, loader.err = io.ReadFull(loader.reader, data)
+	}
 	return
 }
 
@@ -82,7 +92,6 @@ func (loader *waveLoader) loadWave() {
 	for !loader.isDone() {
 		loader.loadFormatOrData()
 	}
-	loader.samples = loader.dataConverter(loader.samples)
 }
 
 func (loader *waveLoader) loadFormatOrData() {
@@ -94,33 +103,41 @@ func (loader *waveLoader) handleFormatOrData(chunkType riffChunkType, size uint3
 		loader.loadFormat(size)
 	} else if chunkType == riffChunkTypeData {
 		loader.loadData(size)
+	} else {
+		// Other chunks, such as lists of meta data, are padded to an even size.
+		loader.readBytes(size + size%2)
 	}
 }
 
 func (loader *waveLoader) loadFormat(size uint32) {
 	headerData := loader.readBytes(size)
 	headerReader := bytes.NewReader(headerData)
-	var header formatHeader
+	var validBitsPerSample uint16
+	var channelMask uint32
 
 	loader.formatRead = true
-	binary.Read(headerReader, binary.LittleEndian, &header.base)
-	binary.Read(headerReader, binary.LittleEndian, &header.extension.BitsPerSample)
-	loader.sampleRate = float32(header.base.SamplesPerSec)
-
-	if header.extension.BitsPerSample == 16 {
-		loader.dataConverter = l8FromL16
-	}
-
-	if (header.base.FormatType != waveFormatTypePcm) ||
-		(header.base.Channels != 1) ||
-		((header.extension.BitsPerSample != 8) && (header.extension.BitsPerSample != 16)) {
-		loader.err = fmt.Errorf("Unsupported WAVE format")
+	binary.Read(headerReader, binary.LittleEndian, &loader.format.base)
+	binary.Read(headerReader, binary.LittleEndian, &loader.format.extension.BitsPerSample)
+	binary.Read(headerReader, binary.LittleEndian, &loader.format.extension.ExtensionSize)
+	loader.subFormat = loader.format.base.FormatType
+	if loader.format.base.FormatType == waveFormatTypeExtensible {
+		binary.Read(headerReader, binary.LittleEndian, &validBitsPerSample)
+		binary.Read(headerReader, binary.LittleEndian, &channelMask)
+		binary.Read(headerReader, binary.LittleEndian, &loader.subFormat)
 	}
 }
 
 func (loader *waveLoader) loadData(size uint32) {
 	loader.dataRead = true
-	loader.samples = loader.readBytes(size)
+	if loader.seeker != nil {
+		loader.dataSize = size
+		loader.dataOffset, loader.err = loader.seeker.Seek(0, io.SeekCurrent)
+		if loader.err == nil {
+			_, loader.err = loader.seeker.Seek(int64(size+size%2), io.SeekCurrent)
+		}
+	} else {
+		loader.samples = loader.readBytes(size)
+	}
 }
 
 func (loader *waveLoader) isDone() bool {
@@ -7,7 +7,9 @@ import (
 type waveFormatType uint16
 
 const (
-	waveFormatTypePcm = 1
+	waveFormatTypePcm        = 1
+	waveFormatTypeIeeeFloat  = 3
+	waveFormatTypeExtensible = 0xFFFE
 )
 
 type waveFormat struct {
@@ -75,6 +75,11 @@ func (chunk *Chunk) SetBlock(index int, data []byte) {
 	}
 }
 
+// HasModifiedBlocks returns true if any block was set via SetBlock().
+func (chunk Chunk) HasModifiedBlocks() bool {
+	return len(chunk.blocks) > 0
+}
+
 func (chunk *Chunk) ensureBlockMap() {
 	if chunk.blocks == nil {
 		chunk.blocks = make(map[int][]byte)
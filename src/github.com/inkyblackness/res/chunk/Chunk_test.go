@@ -74,3 +74,11 @@ func TestChunkPanicsForNegativeBlockIndex(t *testing.T) {
 
 	assert.Panics(t, func() { chunk.SetBlock(-1, []byte{}) }, "Panic expected")
 }
+
+func TestChunkHasModifiedBlocksAfterSetBlock(t *testing.T) {
+	chunk := &Chunk{BlockProvider: MemoryBlockProvider([][]byte{{0x01}})}
+
+	assert.False(t, chunk.HasModifiedBlocks(), "no modification expected initially")
+	chunk.SetBlock(0, []byte{0x02})
+	assert.True(t, chunk.HasModifiedBlocks(), "modification expected")
+}
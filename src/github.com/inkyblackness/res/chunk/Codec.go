@@ -0,0 +1,10 @@
+package chunk
+
+// Codec converts the data of blocks of one content type into typed values
+// and back.
+type Codec interface {
+	// Decode returns the typed value of given block data.
+	Decode(data []byte) (interface{}, error)
+	// Encode returns the block data of given typed value.
+	Encode(value interface{}) ([]byte, error)
+}
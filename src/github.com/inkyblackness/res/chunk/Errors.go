@@ -7,3 +7,9 @@ import "fmt"
 func ErrChunkDoesNotExist(id Identifier) error {
 	return fmt.Errorf("chunk with ID %v does not exist", id)
 }
+
+// ErrCodecNotRegistered returns an error specifying that no codec is
+// registered for the given content type.
+func ErrCodecNotRegistered(contentType ContentType) error {
+	return fmt.Errorf("no codec registered for content type 0x%02X", byte(contentType))
+}
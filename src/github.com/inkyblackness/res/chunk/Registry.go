@@ -0,0 +1,81 @@
+package chunk
+
+import (
+	"io/ioutil"
+	"sync"
+)
+
+// Registry maps content types to the codecs of their blocks.
+// A Registry is safe for concurrent use.
+type Registry struct {
+	lock   sync.RWMutex
+	codecs map[ContentType]Codec
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{codecs: make(map[ContentType]Codec)}
+}
+
+// Register (re-)assigns the codec for given content type.
+func (registry *Registry) Register(contentType ContentType, codec Codec) {
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+	registry.codecs[contentType] = codec
+}
+
+// Codec returns the codec registered for given content type.
+func (registry *Registry) Codec(contentType ContentType) (codec Codec, registered bool) {
+	registry.lock.RLock()
+	defer registry.lock.RUnlock()
+	codec, registered = registry.codecs[contentType]
+	return
+}
+
+// Decode returns the typed value of the identified block of given chunk,
+// using the codec registered for the content type of the chunk.
+func (registry *Registry) Decode(chunk *Chunk, blockIndex int) (interface{}, error) {
+	if _, registered := registry.Codec(chunk.ContentType); !registered {
+		return nil, ErrCodecNotRegistered(chunk.ContentType)
+	}
+	reader, err := chunk.Block(blockIndex)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	return registry.DecodeData(chunk.ContentType, data)
+}
+
+// DecodeData returns the typed value of given block data, using the codec
+// registered for given content type.
+func (registry *Registry) DecodeData(contentType ContentType, data []byte) (interface{}, error) {
+	codec, registered := registry.Codec(contentType)
+	if !registered {
+		return nil, ErrCodecNotRegistered(contentType)
+	}
+	return codec.Decode(data)
+}
+
+// Encode sets the identified block of given chunk to the encoded form of
+// given value, using the codec registered for the content type of the chunk.
+func (registry *Registry) Encode(chunk *Chunk, blockIndex int, value interface{}) error {
+	data, err := registry.EncodeData(chunk.ContentType, value)
+	if err != nil {
+		return err
+	}
+	chunk.SetBlock(blockIndex, data)
+	return nil
+}
+
+// EncodeData returns the block data of given value, using the codec registered
+// for given content type.
+func (registry *Registry) EncodeData(contentType ContentType, value interface{}) ([]byte, error) {
+	codec, registered := registry.Codec(contentType)
+	if !registered {
+		return nil, ErrCodecNotRegistered(contentType)
+	}
+	return codec.Encode(value)
+}
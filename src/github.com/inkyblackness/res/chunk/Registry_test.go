@@ -0,0 +1,77 @@
+package chunk
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type upperCaseCodec struct{}
+
+func (codec upperCaseCodec) Decode(data []byte) (interface{}, error) {
+	return string(bytes.ToUpper(data)), nil
+}
+
+func (codec upperCaseCodec) Encode(value interface{}) ([]byte, error) {
+	text, isText := value.(string)
+	if !isText {
+		return nil, errors.New("not a text")
+	}
+	return bytes.ToLower([]byte(text)), nil
+}
+
+func TestRegistryDecodeUsesCodecOfContentType(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(Text, upperCaseCodec{})
+	chunk := &Chunk{ContentType: Text, BlockProvider: MemoryBlockProvider([][]byte{[]byte("a"), []byte("bc")})}
+
+	value, err := registry.Decode(chunk, 1)
+
+	require.Nil(t, err, "no error expected")
+	assert.Equal(t, "BC", value)
+}
+
+func TestRegistryEncodeSetsBlock(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(Text, upperCaseCodec{})
+	chunk := &Chunk{ContentType: Text, BlockProvider: MemoryBlockProvider([][]byte{[]byte("a")})}
+
+	err := registry.Encode(chunk, 0, "XY")
+
+	require.Nil(t, err, "no error expected")
+	verifyBlockContent(t, chunk, 0, []byte("xy"))
+}
+
+func TestRegistryReturnsErrorForUnregisteredContentType(t *testing.T) {
+	registry := NewRegistry()
+	chunk := &Chunk{ContentType: ContentType(0x21), BlockProvider: MemoryBlockProvider([][]byte{{0x01}})}
+
+	_, decodeErr := registry.Decode(chunk, 0)
+	encodeErr := registry.Encode(chunk, 0, "")
+
+	assert.Equal(t, ErrCodecNotRegistered(ContentType(0x21)), decodeErr)
+	assert.Equal(t, ErrCodecNotRegistered(ContentType(0x21)), encodeErr)
+}
+
+func TestRegistryDecodeDataUsesCodecOfContentType(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(Text, upperCaseCodec{})
+
+	value, err := registry.DecodeData(Text, []byte("de"))
+
+	require.Nil(t, err, "no error expected")
+	assert.Equal(t, "DE", value)
+}
+
+func TestRegistryEncodeDataUsesCodecOfContentType(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(Text, upperCaseCodec{})
+
+	data, err := registry.EncodeData(Text, "FG")
+
+	require.Nil(t, err, "no error expected")
+	assert.Equal(t, []byte("fg"), data)
+}
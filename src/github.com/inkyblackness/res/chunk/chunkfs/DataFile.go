@@ -0,0 +1,21 @@
+package chunkfs
+
+import (
+	"bytes"
+	"io/fs"
+)
+
+// dataFile is an opened file with its data in memory.
+// Next to reading, it supports seeking, as required by http.FileServer.
+type dataFile struct {
+	*bytes.Reader
+	info fileInfo
+}
+
+func (file *dataFile) Stat() (fs.FileInfo, error) {
+	return file.info, nil
+}
+
+func (file *dataFile) Close() error {
+	return nil
+}
@@ -0,0 +1,27 @@
+package chunkfs
+
+import "io/fs"
+
+// dirEntry describes an entry of a directory. The information about the entry
+// is determined only when requested, as this may require to convert data.
+type dirEntry struct {
+	name string
+	mode fs.FileMode
+	info func() (fs.FileInfo, error)
+}
+
+func (entry dirEntry) Name() string {
+	return entry.name
+}
+
+func (entry dirEntry) IsDir() bool {
+	return entry.mode.IsDir()
+}
+
+func (entry dirEntry) Type() fs.FileMode {
+	return entry.mode.Type()
+}
+
+func (entry dirEntry) Info() (fs.FileInfo, error) {
+	return entry.info()
+}
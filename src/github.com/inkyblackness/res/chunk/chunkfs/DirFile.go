@@ -0,0 +1,39 @@
+package chunkfs
+
+import (
+	"io"
+	"io/fs"
+)
+
+// dirFile is an opened directory.
+type dirFile struct {
+	info    fileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (dir *dirFile) Stat() (fs.FileInfo, error) {
+	return dir.info, nil
+}
+
+func (dir *dirFile) Read(p []byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: dir.info.name, Err: fs.ErrInvalid}
+}
+
+func (dir *dirFile) Close() error {
+	return nil
+}
+
+// ReadDir follows the fs.ReadDirFile interface.
+func (dir *dirFile) ReadDir(count int) ([]fs.DirEntry, error) {
+	remaining := len(dir.entries) - dir.offset
+	if (count > 0) && (remaining == 0) {
+		return nil, io.EOF
+	}
+	if (count > 0) && (count < remaining) {
+		remaining = count
+	}
+	entries := dir.entries[dir.offset : dir.offset+remaining]
+	dir.offset += remaining
+	return entries, nil
+}
@@ -0,0 +1,173 @@
+package chunkfs
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/inkyblackness/res/chunk"
+)
+
+// rawExtension is the file name extension of the raw block data.
+const rawExtension = "bin"
+
+// FS presents the chunks of a provider as a read-only file system.
+//
+// The root directory contains one directory per chunk, named after the
+// hexadecimal chunk identifier, such as "0A1F". Each chunk directory contains
+// one file per block, named after the decimal block index, such as "002.bin".
+// These files contain the uncompressed block data. Views add further files,
+// such as "002.png", that contain the converted form of the block.
+//
+// Files are read completely when they are opened.
+type FS struct {
+	provider chunk.Provider
+	views    []View
+}
+
+// New returns a file system for given provider, with given views.
+func New(provider chunk.Provider, views ...View) *FS {
+	return &FS{provider: provider, views: views}
+}
+
+// Open opens the named file or directory, following the fs.FS interface.
+func (fsys *FS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	file, err := fsys.open(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	return file, nil
+}
+
+func (fsys *FS) open(name string) (fs.File, error) {
+	if name == "." {
+		return fsys.rootDir(), nil
+	}
+	parts := strings.Split(name, "/")
+	id, existing := fsys.chunkID(parts[0])
+	if !existing || (len(parts) > 2) {
+		return nil, fs.ErrNotExist
+	}
+	data, err := fsys.provider.Chunk(id)
+	if err != nil {
+		return nil, err
+	}
+	if len(parts) == 1 {
+		return fsys.chunkDir(parts[0], data), nil
+	}
+	return fsys.openBlockFile(data, parts[1])
+}
+
+func (fsys *FS) rootDir() fs.File {
+	dir := &dirFile{info: fileInfo{name: ".", mode: fs.ModeDir | 0555}}
+	for _, id := range fsys.provider.IDs() {
+		name := chunkDirName(id)
+		dir.entries = append(dir.entries, dirEntry{
+			name: name,
+			mode: fs.ModeDir | 0555,
+			info: func() (fs.FileInfo, error) { return fileInfo{name: name, mode: fs.ModeDir | 0555}, nil }})
+	}
+	return dir
+}
+
+func (fsys *FS) chunkDir(name string, data *chunk.Chunk) fs.File {
+	dir := &dirFile{info: fileInfo{name: name, mode: fs.ModeDir | 0555}}
+	extensions := []string{rawExtension}
+	for _, view := range fsys.views {
+		if view.Applies(data) {
+			extensions = append(extensions, view.Extension())
+		}
+	}
+	for blockIndex := 0; blockIndex < data.BlockCount(); blockIndex++ {
+		for _, extension := range extensions {
+			fileName := blockFileName(blockIndex, extension)
+			dir.entries = append(dir.entries, dirEntry{
+				name: fileName,
+				mode: 0444,
+				info: func() (fs.FileInfo, error) {
+					file, err := fsys.openBlockFile(data, fileName)
+					if err != nil {
+						return nil, err
+					}
+					return file.Stat()
+				}})
+		}
+	}
+	return dir
+}
+
+func (fsys *FS) openBlockFile(data *chunk.Chunk, name string) (fs.File, error) {
+	blockIndex, extension, valid := parseBlockFileName(name)
+	if !valid || (blockIndex >= data.BlockCount()) {
+		return nil, fs.ErrNotExist
+	}
+	blockData, err := blockDataOf(data, blockIndex)
+	if err != nil {
+		return nil, err
+	}
+	if extension != rawExtension {
+		view := fsys.view(data, extension)
+		if view == nil {
+			return nil, fs.ErrNotExist
+		}
+		blockData, err = view.Convert(data, blockIndex, blockData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert block %d: %v", blockIndex, err)
+		}
+	}
+	return &dataFile{
+		Reader: bytes.NewReader(blockData),
+		info:   fileInfo{name: name, size: int64(len(blockData)), mode: 0444}}, nil
+}
+
+func (fsys *FS) view(data *chunk.Chunk, extension string) View {
+	for _, view := range fsys.views {
+		if (view.Extension() == extension) && view.Applies(data) {
+			return view
+		}
+	}
+	return nil
+}
+
+func (fsys *FS) chunkID(name string) (chunk.Identifier, bool) {
+	value, err := strconv.ParseUint(name, 16, 16)
+	if (err != nil) || (name != chunkDirName(chunk.ID(uint16(value)))) {
+		return nil, false
+	}
+	for _, id := range fsys.provider.IDs() {
+		if id.Value() == uint16(value) {
+			return id, true
+		}
+	}
+	return nil, false
+}
+
+func chunkDirName(id chunk.Identifier) string {
+	return fmt.Sprintf("%04X", id.Value())
+}
+
+func blockFileName(blockIndex int, extension string) string {
+	return fmt.Sprintf("%03d.%v", blockIndex, extension)
+}
+
+func parseBlockFileName(name string) (blockIndex int, extension string, valid bool) {
+	extension = strings.TrimPrefix(path.Ext(name), ".")
+	blockIndex, err := strconv.Atoi(strings.TrimSuffix(name, path.Ext(name)))
+	valid = (err == nil) && (blockIndex >= 0) && (name == blockFileName(blockIndex, extension))
+	return
+}
+
+func blockDataOf(data *chunk.Chunk, blockIndex int) ([]byte, error) {
+	reader, err := data.Block(blockIndex)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(reader)
+}
@@ -0,0 +1,76 @@
+package chunkfs
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/inkyblackness/res/chunk"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type upperCaseView struct{}
+
+func (view upperCaseView) Extension() string {
+	return "txt"
+}
+
+func (view upperCaseView) Applies(data *chunk.Chunk) bool {
+	return data.ContentType == chunk.Text
+}
+
+func (view upperCaseView) Convert(data *chunk.Chunk, blockIndex int, blockData []byte) ([]byte, error) {
+	if len(blockData) == 0 {
+		return nil, errors.New("no text")
+	}
+	return bytes.ToUpper(blockData), nil
+}
+
+func exampleFS() *FS {
+	store := chunk.NewProviderBackedStore(chunk.NullProvider())
+	store.Put(chunk.ID(0x0A1F), &chunk.Chunk{
+		ContentType:   chunk.Text,
+		Fragmented:    true,
+		BlockProvider: chunk.MemoryBlockProvider([][]byte{[]byte("abc"), []byte("de")})})
+	store.Put(chunk.ID(0x0100), &chunk.Chunk{
+		ContentType:   chunk.Bitmap,
+		BlockProvider: chunk.MemoryBlockProvider([][]byte{{0x01, 0x02, 0x03}})})
+	return New(store, upperCaseView{})
+}
+
+func TestFSFollowsFileSystemConventions(t *testing.T) {
+	err := fstest.TestFS(exampleFS(), "0A1F/000.bin", "0A1F/000.txt", "0A1F/001.txt", "0100/000.bin")
+
+	assert.Nil(t, err, "no error expected")
+}
+
+func TestFSProvidesRawAndConvertedBlocks(t *testing.T) {
+	fsys := exampleFS()
+
+	raw, err := fs.ReadFile(fsys, "0A1F/001.bin")
+	require.Nil(t, err, "no error expected reading raw data")
+	assert.Equal(t, []byte("de"), raw)
+	converted, err := fs.ReadFile(fsys, "0A1F/000.txt")
+	require.Nil(t, err, "no error expected reading converted data")
+	assert.Equal(t, []byte("ABC"), converted)
+}
+
+func TestFSListsViewsOnlyForApplicableChunks(t *testing.T) {
+	entries, err := fs.ReadDir(exampleFS(), "0100")
+	require.Nil(t, err, "no error expected")
+
+	require.Equal(t, 1, len(entries))
+	assert.Equal(t, "000.bin", entries[0].Name())
+}
+
+func TestFSReturnsErrorForUnknownEntries(t *testing.T) {
+	fsys := exampleFS()
+	for _, name := range []string{"0200", "0a1f", "A1F", "0A1F/002.bin", "0A1F/1.bin", "0100/000.txt", "0A1F/000.bin/x"} {
+		_, err := fsys.Open(name)
+		assert.True(t, errors.Is(err, fs.ErrNotExist), "not-exist expected for %v, got %v", name, err)
+	}
+}
@@ -0,0 +1,36 @@
+package chunkfs
+
+import (
+	"io/fs"
+	"time"
+)
+
+type fileInfo struct {
+	name string
+	size int64
+	mode fs.FileMode
+}
+
+func (info fileInfo) Name() string {
+	return info.name
+}
+
+func (info fileInfo) Size() int64 {
+	return info.size
+}
+
+func (info fileInfo) Mode() fs.FileMode {
+	return info.mode
+}
+
+func (info fileInfo) ModTime() time.Time {
+	return time.Time{}
+}
+
+func (info fileInfo) IsDir() bool {
+	return info.mode.IsDir()
+}
+
+func (info fileInfo) Sys() interface{} {
+	return nil
+}
@@ -0,0 +1,14 @@
+package chunkfs
+
+import "github.com/inkyblackness/res/chunk"
+
+// View provides a converted form of blocks, which is presented as a file next
+// to the file with the raw block data.
+type View interface {
+	// Extension returns the file name extension of the converted form, without dot.
+	Extension() string
+	// Applies returns true if the view can convert the blocks of given chunk.
+	Applies(data *chunk.Chunk) bool
+	// Convert returns the converted form of the given block of the chunk.
+	Convert(data *chunk.Chunk, blockIndex int, blockData []byte) ([]byte, error)
+}
@@ -0,0 +1,34 @@
+package content
+
+import (
+	"bytes"
+
+	"github.com/inkyblackness/res/image"
+)
+
+type bitmapCodec struct{}
+
+func (codec bitmapCodec) Decode(data []byte) (interface{}, error) {
+	return image.Read(bytes.NewReader(data))
+}
+
+// Encode writes the bitmap uncompressed and without transparency, unless
+// the bitmap is an image.StorableBitmap that reports otherwise.
+func (codec bitmapCodec) Encode(value interface{}) ([]byte, error) {
+	bitmap, isBitmap := value.(image.Bitmap)
+	if !isBitmap {
+		return nil, errWrongType(value, "a bitmap")
+	}
+	bitmapType := image.UncompressedBitmap
+	transparent := false
+	if stored, isStorable := bitmap.(image.StorableBitmap); isStorable {
+		bitmapType = stored.Type()
+		transparent = stored.Transparent()
+	}
+	buffer := bytes.NewBuffer(nil)
+	err := image.Write(buffer, bitmap, bitmapType, transparent, 0)
+	if err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
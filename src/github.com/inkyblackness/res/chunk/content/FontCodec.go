@@ -0,0 +1,21 @@
+package content
+
+import (
+	"bytes"
+
+	"github.com/inkyblackness/res/font"
+)
+
+type fontCodec struct{}
+
+func (codec fontCodec) Decode(data []byte) (interface{}, error) {
+	return font.Load(bytes.NewReader(data))
+}
+
+func (codec fontCodec) Encode(value interface{}) ([]byte, error) {
+	fontValue, isFont := value.(font.Font)
+	if !isFont {
+		return nil, errWrongType(value, "a font")
+	}
+	return font.Save(fontValue), nil
+}
@@ -0,0 +1,22 @@
+package content
+
+import (
+	"bytes"
+
+	"github.com/inkyblackness/res/geometry"
+	"github.com/inkyblackness/res/geometry/command"
+)
+
+type geometryCodec struct{}
+
+func (codec geometryCodec) Decode(data []byte) (interface{}, error) {
+	return command.LoadModel(bytes.NewReader(data))
+}
+
+func (codec geometryCodec) Encode(value interface{}) ([]byte, error) {
+	model, isModel := value.(geometry.Model)
+	if !isModel {
+		return nil, errWrongType(value, "a model")
+	}
+	return command.SaveModel(model), nil
+}
@@ -0,0 +1,17 @@
+package content
+
+// mapCodec handles archive data. As the structure of these blocks depends on
+// the identifier of their chunk, the codec provides the raw data.
+type mapCodec struct{}
+
+func (codec mapCodec) Decode(data []byte) (interface{}, error) {
+	return append([]byte{}, data...), nil
+}
+
+func (codec mapCodec) Encode(value interface{}) ([]byte, error) {
+	data, isData := value.([]byte)
+	if !isData {
+		return nil, errWrongType(value, "raw data")
+	}
+	return append([]byte{}, data...), nil
+}
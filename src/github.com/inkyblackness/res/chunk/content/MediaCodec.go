@@ -0,0 +1,29 @@
+package content
+
+import (
+	"bytes"
+
+	"github.com/inkyblackness/res/audio"
+	"github.com/inkyblackness/res/movi"
+)
+
+type mediaCodec struct{}
+
+func (codec mediaCodec) Decode(data []byte) (interface{}, error) {
+	return movi.Read(bytes.NewReader(data))
+}
+
+// Encode writes a media container. Sound data is accepted as well; It is
+// contained on its own, as the audio logs and trap messages are.
+func (codec mediaCodec) Encode(value interface{}) ([]byte, error) {
+	if soundData, isSound := value.(audio.SoundData); isSound {
+		value = movi.ContainSound(soundData)
+	}
+	container, isContainer := value.(movi.Container)
+	if !isContainer {
+		return nil, errWrongType(value, "a media container")
+	}
+	buffer := bytes.NewBuffer(nil)
+	movi.Write(buffer, container)
+	return buffer.Bytes(), nil
+}
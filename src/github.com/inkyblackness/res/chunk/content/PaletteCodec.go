@@ -0,0 +1,24 @@
+package content
+
+import (
+	"bytes"
+	"image/color"
+
+	"github.com/inkyblackness/res/image"
+)
+
+type paletteCodec struct{}
+
+func (codec paletteCodec) Decode(data []byte) (interface{}, error) {
+	return image.LoadPalette(bytes.NewReader(data))
+}
+
+func (codec paletteCodec) Encode(value interface{}) ([]byte, error) {
+	palette, isPalette := value.(color.Palette)
+	if !isPalette {
+		return nil, errWrongType(value, "a palette")
+	}
+	buffer := bytes.NewBuffer(nil)
+	err := image.SavePalette(buffer, palette)
+	return buffer.Bytes(), err
+}
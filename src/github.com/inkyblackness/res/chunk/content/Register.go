@@ -0,0 +1,38 @@
+package content
+
+import (
+	"fmt"
+
+	"github.com/inkyblackness/res/chunk"
+)
+
+// Register adds the codecs for all known content types to given registry.
+//
+// The typed values are:
+// color.Palette for palettes, string for texts, image.Bitmap for bitmaps,
+// font.Font for fonts, *data.VideoClipSequence for video clips,
+// audio.SoundData for sounds, geometry.Model for geometry,
+// movi.Container for media, and []byte for map (archive) data.
+// Media can also be encoded from audio.SoundData, which is contained on its own.
+func Register(registry *chunk.Registry) {
+	registry.Register(chunk.Palette, paletteCodec{})
+	registry.Register(chunk.Text, textCodec{})
+	registry.Register(chunk.Bitmap, bitmapCodec{})
+	registry.Register(chunk.Font, fontCodec{})
+	registry.Register(chunk.VideoClip, videoClipCodec{})
+	registry.Register(chunk.Sound, soundCodec{})
+	registry.Register(chunk.Geometry, geometryCodec{})
+	registry.Register(chunk.Media, mediaCodec{})
+	registry.Register(chunk.Map, mapCodec{})
+}
+
+// NewRegistry returns a registry with the codecs for all known content types.
+func NewRegistry() *chunk.Registry {
+	registry := chunk.NewRegistry()
+	Register(registry)
+	return registry
+}
+
+func errWrongType(value interface{}, expected string) error {
+	return fmt.Errorf("value of type %T is not %v", value, expected)
+}
@@ -0,0 +1,118 @@
+package content
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/inkyblackness/res/audio"
+	"github.com/inkyblackness/res/audio/mem"
+	"github.com/inkyblackness/res/chunk"
+	"github.com/inkyblackness/res/data"
+	"github.com/inkyblackness/res/image"
+	"github.com/inkyblackness/res/movi"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func roundTrip(t *testing.T, contentType chunk.ContentType, value interface{}) interface{} {
+	registry := NewRegistry()
+	holder := &chunk.Chunk{ContentType: contentType, BlockProvider: chunk.MemoryBlockProvider(nil)}
+
+	err := registry.Encode(holder, 0, value)
+	require.Nil(t, err, "no error expected encoding")
+	decoded, err := registry.Decode(holder, 0)
+	require.Nil(t, err, "no error expected decoding")
+	return decoded
+}
+
+func TestRegistryHasCodecsForKnownContentTypes(t *testing.T) {
+	registry := NewRegistry()
+	for _, contentType := range []chunk.ContentType{chunk.Palette, chunk.Text, chunk.Bitmap, chunk.Font,
+		chunk.VideoClip, chunk.Sound, chunk.Geometry, chunk.Media, chunk.Map} {
+		_, registered := registry.Codec(contentType)
+		assert.True(t, registered, "codec expected for 0x%02X", byte(contentType))
+	}
+}
+
+func TestPaletteRoundTrip(t *testing.T) {
+	palette := make(color.Palette, 256)
+	for index := range palette {
+		palette[index] = color.NRGBA{R: byte(index), G: 0x10, B: byte(255 - index), A: 0xFF}
+	}
+
+	decoded := roundTrip(t, chunk.Palette, palette)
+
+	decodedPalette, isPalette := decoded.(color.Palette)
+	require.True(t, isPalette, "palette expected")
+	require.Equal(t, len(palette), len(decodedPalette))
+	assert.Equal(t, palette[0x80], decodedPalette[0x80])
+	assert.Equal(t, palette[0xFF], decodedPalette[0xFF])
+}
+
+func TestTextRoundTrip(t *testing.T) {
+	decoded := roundTrip(t, chunk.Text, "Citadel Station")
+
+	assert.Equal(t, "Citadel Station", decoded)
+}
+
+func TestBitmapRoundTrip(t *testing.T) {
+	header := &image.BitmapHeader{Type: image.CompressedBitmap, TransparencyFlag: 1, Width: 2, Height: 2, Stride: 2}
+	bitmap := image.NewMemoryBitmap(header, []byte{0x01, 0x02, 0x03, 0x04}, nil)
+
+	decoded := roundTrip(t, chunk.Bitmap, bitmap)
+
+	decodedBitmap, isBitmap := decoded.(*image.MemoryBitmap)
+	require.True(t, isBitmap, "memory bitmap expected")
+	assert.True(t, decodedBitmap.Compressed(), "compressed bitmap expected")
+	assert.True(t, decodedBitmap.Transparent(), "transparent bitmap expected")
+	assert.Equal(t, []byte{0x03, 0x04}, decodedBitmap.Row(1))
+}
+
+func TestVideoClipRoundTrip(t *testing.T) {
+	sequence := data.DefaultVideoClipSequence(2)
+	sequence.Width = 100
+	sequence.FramesID = 0x0A00
+	sequence.Entries[1].FrameTime = 50
+
+	decoded := roundTrip(t, chunk.VideoClip, sequence)
+
+	assert.Equal(t, sequence, decoded)
+}
+
+func TestSoundRoundTrip(t *testing.T) {
+	decoded := roundTrip(t, chunk.Sound, mem.NewL8SoundData(10000.0, []byte{0x80, 0x81, 0x7F}))
+
+	soundData, isSound := decoded.(audio.SoundData)
+	require.True(t, isSound, "sound data expected")
+	assert.Equal(t, float32(10000.0), soundData.SampleRate())
+	assert.Equal(t, []byte{0x80, 0x81, 0x7F}, soundData.Samples(0, soundData.SampleCount()))
+}
+
+func TestMediaEncodesSoundDataAsContainer(t *testing.T) {
+	decoded := roundTrip(t, chunk.Media, mem.NewL8SoundData(22050.0, []byte{0x80, 0x90, 0x70}))
+
+	container, isContainer := decoded.(movi.Container)
+	require.True(t, isContainer, "container expected")
+	assert.Equal(t, uint16(22050), container.AudioSampleRate())
+	require.Equal(t, 1, container.EntryCount())
+	assert.Equal(t, []byte{0x80, 0x90, 0x70}, container.Entry(0).Data())
+}
+
+func TestMapRoundTrip(t *testing.T) {
+	decoded := roundTrip(t, chunk.Map, []byte{0x01, 0x02})
+
+	assert.Equal(t, []byte{0x01, 0x02}, decoded)
+}
+
+func TestEncodeReturnsErrorForWrongType(t *testing.T) {
+	registry := NewRegistry()
+	for _, contentType := range []chunk.ContentType{chunk.Palette, chunk.Text, chunk.Bitmap, chunk.Font,
+		chunk.VideoClip, chunk.Sound, chunk.Geometry, chunk.Media, chunk.Map} {
+		holder := &chunk.Chunk{ContentType: contentType, BlockProvider: chunk.MemoryBlockProvider(nil)}
+
+		err := registry.Encode(holder, 0, 1234)
+
+		assert.NotNil(t, err, "error expected for 0x%02X", byte(contentType))
+	}
+}
@@ -0,0 +1,19 @@
+package content
+
+import (
+	"github.com/inkyblackness/res/audio"
+)
+
+type soundCodec struct{}
+
+func (codec soundCodec) Decode(data []byte) (interface{}, error) {
+	return audio.DecodeSoundChunk(data)
+}
+
+func (codec soundCodec) Encode(value interface{}) ([]byte, error) {
+	soundData, isSound := value.(audio.SoundData)
+	if !isSound {
+		return nil, errWrongType(value, "sound data")
+	}
+	return audio.EncodeSoundChunk(soundData)
+}
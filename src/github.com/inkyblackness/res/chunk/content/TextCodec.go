@@ -0,0 +1,19 @@
+package content
+
+import (
+	"github.com/inkyblackness/res/text"
+)
+
+type textCodec struct{}
+
+func (codec textCodec) Decode(data []byte) (interface{}, error) {
+	return text.DefaultCodepage().Decode(data), nil
+}
+
+func (codec textCodec) Encode(value interface{}) ([]byte, error) {
+	textValue, isText := value.(string)
+	if !isText {
+		return nil, errWrongType(value, "a text")
+	}
+	return text.DefaultCodepage().Encode(textValue), nil
+}
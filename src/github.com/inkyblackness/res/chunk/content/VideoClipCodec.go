@@ -0,0 +1,32 @@
+package content
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/inkyblackness/res/data"
+	"github.com/inkyblackness/res/serial"
+)
+
+type videoClipCodec struct{}
+
+func (codec videoClipCodec) Decode(blockData []byte) (interface{}, error) {
+	if len(blockData) < data.VideoClipSequenceBaseSize {
+		return nil, fmt.Errorf("video clip sequence too short: %v bytes", len(blockData))
+	}
+	sequence := data.DefaultVideoClipSequence((len(blockData) - data.VideoClipSequenceBaseSize) / data.VideoClipSequenceEntrySize)
+	decoder := serial.NewDecoder(bytes.NewReader(blockData))
+	sequence.Code(decoder)
+	return sequence, decoder.FirstError()
+}
+
+func (codec videoClipCodec) Encode(value interface{}) ([]byte, error) {
+	sequence, isSequence := value.(*data.VideoClipSequence)
+	if !isSequence {
+		return nil, errWrongType(value, "a video clip sequence")
+	}
+	buffer := bytes.NewBuffer(nil)
+	encoder := serial.NewEncoder(buffer)
+	sequence.Code(encoder)
+	return buffer.Bytes(), encoder.FirstError()
+}
@@ -0,0 +1,20 @@
+package folder
+
+// ManifestFileName is the name of the manifest file within a folder.
+const ManifestFileName = "manifest.json"
+
+// Manifest describes the chunks stored in a folder, in the order of the
+// resource file.
+type Manifest struct {
+	Chunks []ManifestChunk `json:"chunks"`
+}
+
+// ManifestChunk describes one chunk and refers to the files of its blocks.
+type ManifestChunk struct {
+	// ID is the hexadecimal chunk identifier, without prefix.
+	ID          string   `json:"id"`
+	ContentType byte     `json:"contentType"`
+	Compressed  bool     `json:"compressed"`
+	Fragmented  bool     `json:"fragmented"`
+	Blocks      []string `json:"blocks"`
+}
@@ -0,0 +1,61 @@
+package folder
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+
+	"github.com/inkyblackness/res/chunk"
+)
+
+// Pack reads the chunks described by the manifest in given folder and returns
+// them as a store, in the order of the manifest. The block files are read
+// completely before the store is returned.
+func Pack(path string) (*chunk.ProviderBackedStore, error) {
+	manifestData, err := ioutil.ReadFile(filepath.Join(path, ManifestFileName))
+	if err != nil {
+		return nil, err
+	}
+	var manifest Manifest
+	err = json.Unmarshal(manifestData, &manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %v", err)
+	}
+
+	store := chunk.NewProviderBackedStore(chunk.NullProvider())
+	known := make(map[uint16]bool)
+	for _, manifestChunk := range manifest.Chunks {
+		idValue, idErr := strconv.ParseUint(manifestChunk.ID, 16, 16)
+		if idErr != nil {
+			return nil, fmt.Errorf("invalid chunk ID %q: %v", manifestChunk.ID, idErr)
+		}
+		id := chunk.ID(uint16(idValue))
+		if known[id.Value()] {
+			return nil, fmt.Errorf("chunk %v is listed more than once", id)
+		}
+		known[id.Value()] = true
+		if !manifestChunk.Fragmented && (len(manifestChunk.Blocks) != 1) {
+			return nil, fmt.Errorf("unfragmented chunk %v must have exactly one block, has %d",
+				id, len(manifestChunk.Blocks))
+		}
+
+		blocks := make([][]byte, len(manifestChunk.Blocks))
+		for blockIndex, fileName := range manifestChunk.Blocks {
+			if filepath.Base(fileName) != fileName {
+				return nil, fmt.Errorf("block file %q of chunk %v is not within folder", fileName, id)
+			}
+			blocks[blockIndex], err = ioutil.ReadFile(filepath.Join(path, fileName))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read block %d of chunk %v: %v", blockIndex, id, err)
+			}
+		}
+		store.Put(id, &chunk.Chunk{
+			ContentType:   chunk.ContentType(manifestChunk.ContentType),
+			Compressed:    manifestChunk.Compressed,
+			Fragmented:    manifestChunk.Fragmented,
+			BlockProvider: chunk.MemoryBlockProvider(blocks)})
+	}
+	return store, nil
+}
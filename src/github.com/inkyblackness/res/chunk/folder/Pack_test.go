@@ -0,0 +1,90 @@
+package folder
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/inkyblackness/res/chunk"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func withTempFolder(t *testing.T, test func(path string)) {
+	path, err := ioutil.TempDir("", "folder_test")
+	require.Nil(t, err, "no error expected creating temporary folder")
+	defer os.RemoveAll(path)
+	test(path)
+}
+
+func exampleStore() *chunk.ProviderBackedStore {
+	store := chunk.NewProviderBackedStore(chunk.NullProvider())
+	store.Put(chunk.ID(0x0200), &chunk.Chunk{
+		ContentType:   chunk.Bitmap,
+		Compressed:    true,
+		BlockProvider: chunk.MemoryBlockProvider([][]byte{{0x01, 0x02}})})
+	store.Put(chunk.ID(0x0100), &chunk.Chunk{
+		ContentType:   chunk.Text,
+		Fragmented:    true,
+		BlockProvider: chunk.MemoryBlockProvider([][]byte{{0x10}, {}, {0x12, 0x12}})})
+	return store
+}
+
+func TestPackRestoresUnpackedChunks(t *testing.T) {
+	withTempFolder(t, func(path string) {
+		err := Unpack(exampleStore(), path)
+		require.Nil(t, err, "no error expected unpacking")
+
+		store, err := Pack(path)
+		require.Nil(t, err, "no error expected packing")
+
+		assert.Equal(t, []chunk.Identifier{chunk.ID(0x0200), chunk.ID(0x0100)}, store.IDs())
+		bitmap, _ := store.Chunk(chunk.ID(0x0200))
+		assert.Equal(t, chunk.Bitmap, bitmap.ContentType)
+		assert.True(t, bitmap.Compressed, "compressed expected")
+		assert.False(t, bitmap.Fragmented, "unfragmented expected")
+		text, _ := store.Chunk(chunk.ID(0x0100))
+		assert.True(t, text.Fragmented, "fragmented expected")
+		require.Equal(t, 3, text.BlockCount())
+		block, _ := text.Block(2)
+		data, _ := ioutil.ReadAll(block)
+		assert.Equal(t, []byte{0x12, 0x12}, data)
+	})
+}
+
+func TestUnpackWritesBlockFilesAndManifest(t *testing.T) {
+	withTempFolder(t, func(path string) {
+		Unpack(exampleStore(), path) // nolint: errcheck
+
+		data, err := ioutil.ReadFile(filepath.Join(path, "0100_001.bin"))
+		assert.Nil(t, err, "no error expected reading block file")
+		assert.Equal(t, []byte{}, data)
+		_, err = os.Stat(filepath.Join(path, ManifestFileName))
+		assert.Nil(t, err, "manifest expected")
+	})
+}
+
+func TestPackReturnsErrorForBlockFileOutsideOfFolder(t *testing.T) {
+	withTempFolder(t, func(path string) {
+		manifest := `{"chunks":[{"id":"0100","contentType":1,"blocks":["../secret.bin"]}]}`
+		ioutil.WriteFile(filepath.Join(path, ManifestFileName), []byte(manifest), os.FileMode(0644)) // nolint: errcheck
+
+		_, err := Pack(path)
+
+		assert.NotNil(t, err, "error expected")
+	})
+}
+
+func TestPackReturnsErrorForDuplicateChunks(t *testing.T) {
+	withTempFolder(t, func(path string) {
+		manifest := `{"chunks":[{"id":"0100","blocks":["a.bin"]},{"id":"0100","blocks":["a.bin"]}]}`
+		ioutil.WriteFile(filepath.Join(path, ManifestFileName), []byte(manifest), os.FileMode(0644)) // nolint: errcheck
+		ioutil.WriteFile(filepath.Join(path, "a.bin"), []byte{0x01}, os.FileMode(0644))              // nolint: errcheck
+
+		_, err := Pack(path)
+
+		assert.NotNil(t, err, "error expected")
+	})
+}
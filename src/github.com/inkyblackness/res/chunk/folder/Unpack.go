@@ -0,0 +1,63 @@
+package folder
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/inkyblackness/res/chunk"
+)
+
+// Unpack writes all chunks of given provider into the given folder. Each block
+// is stored in its own file, named after the chunk and block, and a manifest
+// describes the chunks. The folder is created if it does not exist.
+func Unpack(provider chunk.Provider, path string) error {
+	err := os.MkdirAll(path, os.FileMode(0755))
+	if err != nil {
+		return err
+	}
+
+	var manifest Manifest
+	for _, id := range provider.IDs() {
+		entry, chunkErr := provider.Chunk(id)
+		if chunkErr != nil {
+			return fmt.Errorf("failed to retrieve chunk %v: %v", id, chunkErr)
+		}
+		manifestChunk := ManifestChunk{
+			ID:          fmt.Sprintf("%04X", id.Value()),
+			ContentType: byte(entry.ContentType),
+			Compressed:  entry.Compressed,
+			Fragmented:  entry.Fragmented,
+			Blocks:      []string{}}
+		for blockIndex := 0; blockIndex < entry.BlockCount(); blockIndex++ {
+			fileName := fmt.Sprintf("%04X_%03d.bin", id.Value(), blockIndex)
+			blockErr := unpackBlock(entry, blockIndex, filepath.Join(path, fileName))
+			if blockErr != nil {
+				return fmt.Errorf("failed to unpack block %d of chunk %v: %v", blockIndex, id, blockErr)
+			}
+			manifestChunk.Blocks = append(manifestChunk.Blocks, fileName)
+		}
+		manifest.Chunks = append(manifest.Chunks, manifestChunk)
+	}
+
+	manifestData, err := json.MarshalIndent(&manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	manifestData = append(manifestData, '\n')
+	return ioutil.WriteFile(filepath.Join(path, ManifestFileName), manifestData, os.FileMode(0644))
+}
+
+func unpackBlock(entry *chunk.Chunk, blockIndex int, fileName string) error {
+	reader, err := entry.Block(blockIndex)
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fileName, data, os.FileMode(0644))
+}
@@ -0,0 +1,7 @@
+package patch
+
+// BlockChange contains the new data of one block.
+type BlockChange struct {
+	Index int
+	Data  []byte
+}
@@ -0,0 +1,29 @@
+package patch
+
+import "fmt"
+
+// ChangeKind describes how a chunk differs between the base and the
+// modified resources.
+type ChangeKind byte
+
+const (
+	// ChunkAdded marks chunks that exist only in the modified resources.
+	ChunkAdded = ChangeKind(0x01)
+	// ChunkRemoved marks chunks that exist only in the base resources.
+	ChunkRemoved = ChangeKind(0x02)
+	// ChunkChanged marks chunks that exist in both resources with different content.
+	ChunkChanged = ChangeKind(0x03)
+)
+
+func (kind ChangeKind) String() string {
+	switch kind {
+	case ChunkAdded:
+		return "added"
+	case ChunkRemoved:
+		return "removed"
+	case ChunkChanged:
+		return "changed"
+	default:
+		return fmt.Sprintf("unknown(0x%02X)", byte(kind))
+	}
+}
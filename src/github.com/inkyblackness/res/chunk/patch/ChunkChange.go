@@ -0,0 +1,56 @@
+package patch
+
+import (
+	"github.com/inkyblackness/res/chunk"
+)
+
+const (
+	propertyFragmented = byte(0x01)
+	propertyCompressed = byte(0x02)
+)
+
+// ChunkChange describes the difference of one chunk.
+type ChunkChange struct {
+	ID   chunk.Identifier
+	Kind ChangeKind
+
+	// BaseHash is the hash of the chunk in the base resources.
+	// It is not set for added chunks.
+	BaseHash Hash
+
+	// The properties of the chunk in the modified resources.
+	// They are not set for removed chunks.
+	Fragmented  bool
+	Compressed  bool
+	ContentType chunk.ContentType
+	BlockCount  int
+
+	// Blocks lists the data of all blocks that differ from the base.
+	// For added chunks, this contains all blocks.
+	Blocks []BlockChange
+}
+
+func propertiesOf(data *chunk.Chunk) (properties byte) {
+	if data.Fragmented {
+		properties |= propertyFragmented
+	}
+	if data.Compressed {
+		properties |= propertyCompressed
+	}
+	return
+}
+
+func (change *ChunkChange) properties() (properties byte) {
+	if change.Fragmented {
+		properties |= propertyFragmented
+	}
+	if change.Compressed {
+		properties |= propertyCompressed
+	}
+	return
+}
+
+func (change *ChunkChange) setProperties(properties byte) {
+	change.Fragmented = (properties & propertyFragmented) != 0
+	change.Compressed = (properties & propertyCompressed) != 0
+}
@@ -0,0 +1,126 @@
+package patch
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/inkyblackness/res/chunk"
+	"github.com/inkyblackness/res/serial"
+)
+
+// The serialized form of a patch starts with a header, followed by the changes:
+//
+//	header:       magic [4]byte, version uint16, change count uint32
+//	change:       ID uint16, kind byte, base hash [32]byte, properties byte,
+//	              content type byte, block count uint32, changed block count uint32
+//	block change: index uint32, length uint32, data [length]byte
+const (
+	formatVersion = uint16(1)
+
+	// maxBlockCount and maxBlockLength are the limits given by the resource file format.
+	maxBlockCount  = uint32(0xFFFF)
+	maxBlockLength = uint32(0x00FFFFFF)
+)
+
+var formatMagic = [4]byte{'R', 'P', 'A', 'T'}
+
+var errFormatMismatch = errors.New("format mismatch")
+
+// Encode writes the patch in serialized form to given target.
+func (patch *Patch) Encode(target io.Writer) error {
+	encoder := serial.NewEncoder(target)
+	encoder.Code(formatMagic)
+	encoder.Code(formatVersion)
+	encoder.Code(uint32(len(patch.Changes)))
+	for index := range patch.Changes {
+		change := &patch.Changes[index]
+		encoder.Code(change.ID.Value())
+		encoder.Code(byte(change.Kind))
+		encoder.Code(change.BaseHash)
+		encoder.Code(change.properties())
+		encoder.Code(byte(change.ContentType))
+		encoder.Code(uint32(change.BlockCount))
+		encoder.Code(uint32(len(change.Blocks)))
+		for _, block := range change.Blocks {
+			encoder.Code(uint32(block.Index))
+			encoder.Code(uint32(len(block.Data)))
+			encoder.Code(block.Data)
+		}
+	}
+	return encoder.FirstError()
+}
+
+// Decode reads a patch in serialized form from given source.
+func Decode(source io.Reader) (*Patch, error) {
+	decoder := serial.NewDecoder(source)
+	var magic [4]byte
+	var version uint16
+	var changeCount uint32
+	decoder.Code(&magic)
+	decoder.Code(&version)
+	if decoder.FirstError() != nil {
+		return nil, decoder.FirstError()
+	}
+	if magic != formatMagic {
+		return nil, errFormatMismatch
+	}
+	if version != formatVersion {
+		return nil, fmt.Errorf("unsupported version %v", version)
+	}
+	decoder.Code(&changeCount)
+
+	patch := &Patch{}
+	for changeIndex := uint32(0); (changeIndex < changeCount) && (decoder.FirstError() == nil); changeIndex++ {
+		change, err := decodeChange(decoder)
+		if err != nil {
+			return nil, err
+		}
+		patch.Changes = append(patch.Changes, change)
+	}
+	if decoder.FirstError() != nil {
+		return nil, decoder.FirstError()
+	}
+	return patch, nil
+}
+
+func decodeChange(decoder *serial.Decoder) (change ChunkChange, err error) {
+	var id uint16
+	var kind byte
+	var properties byte
+	var contentType byte
+	var blockCount uint32
+	var blockChangeCount uint32
+	decoder.Code(&id)
+	decoder.Code(&kind)
+	decoder.Code(&change.BaseHash)
+	decoder.Code(&properties)
+	decoder.Code(&contentType)
+	decoder.Code(&blockCount)
+	decoder.Code(&blockChangeCount)
+	change.ID = chunk.ID(id)
+	change.Kind = ChangeKind(kind)
+	change.setProperties(properties)
+	change.ContentType = chunk.ContentType(contentType)
+	change.BlockCount = int(blockCount)
+	if blockCount > maxBlockCount {
+		return change, fmt.Errorf("chunk %v has too many blocks: %v", change.ID, blockCount)
+	}
+	if blockChangeCount > blockCount {
+		return change, fmt.Errorf("chunk %v has %v changed blocks of %v blocks", change.ID, blockChangeCount, blockCount)
+	}
+
+	for blockIndex := uint32(0); (blockIndex < blockChangeCount) && (decoder.FirstError() == nil); blockIndex++ {
+		var index uint32
+		var length uint32
+		decoder.Code(&index)
+		decoder.Code(&length)
+		if length > maxBlockLength {
+			return change, fmt.Errorf("block %v of chunk %v is too long: %v bytes", index, change.ID, length)
+		}
+		data := make([]byte, length)
+		decoder.Code(data)
+		change.Blocks = append(change.Blocks, BlockChange{Index: int(index), Data: data})
+	}
+	return change, nil
+}
@@ -0,0 +1,37 @@
+package patch
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodedPatchCanBeDecoded(t *testing.T) {
+	patch, _ := Create(baseStore(), modifiedStore())
+	buffer := bytes.NewBuffer(nil)
+
+	err := patch.Encode(buffer)
+	require.Nil(t, err, "no error expected encoding")
+	decoded, err := Decode(bytes.NewReader(buffer.Bytes()))
+	require.Nil(t, err, "no error expected decoding")
+
+	assert.Equal(t, patch, decoded)
+}
+
+func TestDecodeReturnsErrorForWrongFormat(t *testing.T) {
+	_, err := Decode(bytes.NewReader([]byte{'L', 'G', ' ', 'R', 0x01, 0x00, 0x00, 0x00, 0x00, 0x00}))
+
+	assert.Equal(t, errFormatMismatch, err)
+}
+
+func TestDecodeReturnsErrorForTruncatedData(t *testing.T) {
+	patch, _ := Create(baseStore(), modifiedStore())
+	buffer := bytes.NewBuffer(nil)
+	patch.Encode(buffer) // nolint: errcheck
+
+	_, err := Decode(bytes.NewReader(buffer.Bytes()[:buffer.Len()-1]))
+
+	assert.NotNil(t, err, "error expected")
+}
@@ -0,0 +1,50 @@
+package patch
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/inkyblackness/res/chunk"
+	"github.com/inkyblackness/res/serial"
+)
+
+// Hash is the SHA-256 checksum of a chunk.
+type Hash [sha256.Size]byte
+
+// String returns the hexadecimal representation of the hash.
+func (hash Hash) String() string {
+	return fmt.Sprintf("%x", hash[:])
+}
+
+// HashOf calculates the hash of given chunk. The hash covers the properties
+// of the chunk, as well as the uncompressed data of all its blocks.
+func HashOf(data *chunk.Chunk) (hash Hash, err error) {
+	hasher := sha256.New()
+	encoder := serial.NewEncoder(hasher)
+	encoder.Code(propertiesOf(data))
+	encoder.Code(byte(data.ContentType))
+	encoder.Code(uint32(data.BlockCount()))
+	for blockIndex := 0; blockIndex < data.BlockCount(); blockIndex++ {
+		blockData, blockErr := blockDataOf(data, blockIndex)
+		if blockErr != nil {
+			return hash, blockErr
+		}
+		encoder.Code(uint32(len(blockData)))
+		encoder.Code(blockData)
+	}
+	copy(hash[:], hasher.Sum(nil))
+	return hash, encoder.FirstError()
+}
+
+func blockDataOf(data *chunk.Chunk, blockIndex int) ([]byte, error) {
+	reader, err := data.Block(blockIndex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve block %d: %v", blockIndex, err)
+	}
+	buffer, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read block %d: %v", blockIndex, err)
+	}
+	return buffer, nil
+}
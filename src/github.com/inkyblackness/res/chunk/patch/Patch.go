@@ -0,0 +1,254 @@
+package patch
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/inkyblackness/res/chunk"
+)
+
+// Patch describes the differences between two sets of chunks, the base and
+// the modified resources. Applying a patch to the base produces the modified resources.
+type Patch struct {
+	Changes []ChunkChange
+}
+
+// Create compares the given providers and returns a patch that turns the
+// base into the modified resources. Only chunks and blocks that differ are
+// contained in the patch.
+func Create(base, modified chunk.Provider) (*Patch, error) {
+	patch := &Patch{}
+	baseIDs := idSet(base)
+	modifiedIDs := idSet(modified)
+
+	for _, id := range modified.IDs() {
+		modifiedChunk, err := modified.Chunk(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve modified chunk %v: %v", id, err)
+		}
+		var change *ChunkChange
+		if baseIDs[id.Value()] {
+			change, err = changeOf(id, base, modifiedChunk)
+		} else {
+			change, err = additionOf(id, modifiedChunk)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if change != nil {
+			patch.Changes = append(patch.Changes, *change)
+		}
+	}
+	for _, id := range base.IDs() {
+		if !modifiedIDs[id.Value()] {
+			baseHash, err := hashOfChunk(base, id)
+			if err != nil {
+				return nil, err
+			}
+			patch.Changes = append(patch.Changes, ChunkChange{ID: id, Kind: ChunkRemoved, BaseHash: baseHash})
+		}
+	}
+
+	return patch, nil
+}
+
+// Apply modifies the given store according to the patch.
+// Before any modification is done, the store is verified to contain the base
+// of the patch: Chunks that are changed or removed must match their base hash,
+// and chunks that are added must not exist. Furthermore, all patched chunks are
+// prepared before the first one is put. Should either fail, an error is returned
+// and the store is not modified.
+func (patch *Patch) Apply(store chunk.Store) error {
+	err := patch.Verify(store)
+	if err != nil {
+		return err
+	}
+	patchedChunks := make([]*chunk.Chunk, len(patch.Changes))
+	for index, change := range patch.Changes {
+		switch change.Kind {
+		case ChunkAdded:
+			patchedChunks[index] = change.newChunk(nil)
+		case ChunkChanged:
+			baseChunk, err := store.Chunk(change.ID)
+			if err != nil {
+				return fmt.Errorf("failed to retrieve base chunk %v: %v", change.ID, err)
+			}
+			patchedChunks[index], err = change.patchedChunk(baseChunk)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	for index, change := range patch.Changes {
+		if change.Kind == ChunkRemoved {
+			store.Del(change.ID)
+		} else {
+			store.Put(change.ID, patchedChunks[index])
+		}
+	}
+	return nil
+}
+
+// Verify checks whether the given provider contains the base of the patch.
+func (patch *Patch) Verify(base chunk.Provider) error {
+	baseIDs := idSet(base)
+	for _, change := range patch.Changes {
+		existing := baseIDs[change.ID.Value()]
+		switch change.Kind {
+		case ChunkAdded:
+			if existing {
+				return fmt.Errorf("chunk %v to be added already exists", change.ID)
+			}
+			if err := change.verifyBlocks(); err != nil {
+				return err
+			}
+		case ChunkRemoved, ChunkChanged:
+			if !existing {
+				return fmt.Errorf("chunk %v to be %v does not exist", change.ID, change.Kind)
+			}
+			baseHash, err := hashOfChunk(base, change.ID)
+			if err != nil {
+				return err
+			}
+			if baseHash != change.BaseHash {
+				return fmt.Errorf("chunk %v differs from base of patch: hash is %v, expected %v",
+					change.ID, baseHash, change.BaseHash)
+			}
+			if err := change.verifyBlocks(); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("chunk %v has unknown change kind %v", change.ID, change.Kind)
+		}
+	}
+	return nil
+}
+
+func (change *ChunkChange) verifyBlocks() error {
+	for _, block := range change.Blocks {
+		if (block.Index < 0) || (block.Index >= change.BlockCount) {
+			return fmt.Errorf("chunk %v has changed block %d outside of %d blocks", change.ID, block.Index, change.BlockCount)
+		}
+	}
+	return nil
+}
+
+func idSet(provider chunk.Provider) map[uint16]bool {
+	ids := make(map[uint16]bool)
+	for _, id := range provider.IDs() {
+		ids[id.Value()] = true
+	}
+	return ids
+}
+
+func hashOfChunk(provider chunk.Provider, id chunk.Identifier) (Hash, error) {
+	data, err := provider.Chunk(id)
+	if err != nil {
+		return Hash{}, fmt.Errorf("failed to retrieve base chunk %v: %v", id, err)
+	}
+	hash, err := HashOf(data)
+	if err != nil {
+		return Hash{}, fmt.Errorf("failed to hash chunk %v: %v", id, err)
+	}
+	return hash, nil
+}
+
+func newChange(id chunk.Identifier, kind ChangeKind, modified *chunk.Chunk) *ChunkChange {
+	return &ChunkChange{
+		ID:          id,
+		Kind:        kind,
+		Fragmented:  modified.Fragmented,
+		Compressed:  modified.Compressed,
+		ContentType: modified.ContentType,
+		BlockCount:  modified.BlockCount()}
+}
+
+func additionOf(id chunk.Identifier, modified *chunk.Chunk) (*ChunkChange, error) {
+	change := newChange(id, ChunkAdded, modified)
+	for blockIndex := 0; blockIndex < change.BlockCount; blockIndex++ {
+		data, err := blockDataOf(modified, blockIndex)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add chunk %v: %v", id, err)
+		}
+		change.Blocks = append(change.Blocks, BlockChange{Index: blockIndex, Data: data})
+	}
+	return change, nil
+}
+
+// changeOf returns the change of a chunk that exists in both resources.
+// If the chunks are equal, nil is returned.
+func changeOf(id chunk.Identifier, base chunk.Provider, modified *chunk.Chunk) (*ChunkChange, error) {
+	baseChunk, err := base.Chunk(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve base chunk %v: %v", id, err)
+	}
+	baseHash, err := HashOf(baseChunk)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash base chunk %v: %v", id, err)
+	}
+	modifiedHash, err := HashOf(modified)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash modified chunk %v: %v", id, err)
+	}
+	if baseHash == modifiedHash {
+		return nil, nil
+	}
+
+	change := newChange(id, ChunkChanged, modified)
+	change.BaseHash = baseHash
+	for blockIndex := 0; blockIndex < change.BlockCount; blockIndex++ {
+		data, err := blockDataOf(modified, blockIndex)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compare chunk %v: %v", id, err)
+		}
+		if blockIndex < baseChunk.BlockCount() {
+			baseData, err := blockDataOf(baseChunk, blockIndex)
+			if err != nil {
+				return nil, fmt.Errorf("failed to compare chunk %v: %v", id, err)
+			}
+			if bytes.Equal(baseData, data) {
+				continue
+			}
+		}
+		change.Blocks = append(change.Blocks, BlockChange{Index: blockIndex, Data: data})
+	}
+	return change, nil
+}
+
+// newChunk creates a chunk with the properties of the change. Blocks not
+// listed in the change are taken from given base blocks.
+func (change *ChunkChange) newChunk(baseBlocks [][]byte) *chunk.Chunk {
+	blocks := make([][]byte, change.BlockCount)
+	copy(blocks, baseBlocks)
+	for _, block := range change.Blocks {
+		blocks[block.Index] = block.Data
+	}
+	for blockIndex := range blocks {
+		if blocks[blockIndex] == nil {
+			blocks[blockIndex] = []byte{}
+		}
+	}
+	return &chunk.Chunk{
+		Fragmented:    change.Fragmented,
+		Compressed:    change.Compressed,
+		ContentType:   change.ContentType,
+		BlockProvider: chunk.MemoryBlockProvider(blocks)}
+}
+
+func (change *ChunkChange) patchedChunk(base *chunk.Chunk) (*chunk.Chunk, error) {
+	changedBlocks := make(map[int]bool)
+	for _, block := range change.Blocks {
+		changedBlocks[block.Index] = true
+	}
+	baseBlocks := make([][]byte, change.BlockCount)
+	for blockIndex := 0; (blockIndex < change.BlockCount) && (blockIndex < base.BlockCount()); blockIndex++ {
+		if !changedBlocks[blockIndex] {
+			data, err := blockDataOf(base, blockIndex)
+			if err != nil {
+				return nil, fmt.Errorf("failed to patch chunk %v: %v", change.ID, err)
+			}
+			baseBlocks[blockIndex] = data
+		}
+	}
+	return change.newChunk(baseBlocks), nil
+}
@@ -0,0 +1,142 @@
+package patch
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/inkyblackness/res/chunk"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func aChunk(contentType chunk.ContentType, blocks ...[]byte) *chunk.Chunk {
+	return &chunk.Chunk{
+		Fragmented:    len(blocks) > 1,
+		ContentType:   contentType,
+		BlockProvider: chunk.MemoryBlockProvider(blocks)}
+}
+
+func baseStore() *chunk.ProviderBackedStore {
+	store := chunk.NewProviderBackedStore(chunk.NullProvider())
+	store.Put(chunk.ID(0x0100), aChunk(chunk.Text, []byte{0x01}, []byte{0x02, 0x02}, []byte{0x03}))
+	store.Put(chunk.ID(0x0200), aChunk(chunk.Bitmap, []byte{0x10, 0x11}))
+	store.Put(chunk.ID(0x0300), aChunk(chunk.Sound, []byte{0x20}))
+	return store
+}
+
+func modifiedStore() *chunk.ProviderBackedStore {
+	store := baseStore()
+	store.Put(chunk.ID(0x0100), aChunk(chunk.Text, []byte{0x01}, []byte{0xA2}, []byte{0x03}, []byte{0xA4}))
+	store.Del(chunk.ID(0x0300))
+	store.Put(chunk.ID(0x0400), aChunk(chunk.Palette, []byte{0x30, 0x31}))
+	return store
+}
+
+// exhaustingBlockProvider provides its blocks only for a limited number of requests.
+type exhaustingBlockProvider struct {
+	blocks    [][]byte
+	remaining *int
+}
+
+func (provider exhaustingBlockProvider) BlockCount() int {
+	return len(provider.blocks)
+}
+
+func (provider exhaustingBlockProvider) Block(index int) (io.Reader, error) {
+	if *provider.remaining <= 0 {
+		return nil, errors.New("block exhausted")
+	}
+	*provider.remaining--
+	return bytes.NewReader(provider.blocks[index]), nil
+}
+
+func verifyBlocks(t *testing.T, provider chunk.Provider, id chunk.Identifier, expected ...[]byte) {
+	data, err := provider.Chunk(id)
+	require.Nil(t, err, "no error expected for chunk %v", id)
+	require.Equal(t, len(expected), data.BlockCount(), "block count of chunk %v", id)
+	for index, expectedData := range expected {
+		blockData, _ := blockDataOf(data, index)
+		assert.Equal(t, expectedData, blockData, "block %d of chunk %v", index, id)
+	}
+}
+
+func TestCreateContainsOnlyDifferences(t *testing.T) {
+	patch, err := Create(baseStore(), modifiedStore())
+	require.Nil(t, err, "no error expected")
+
+	require.Equal(t, 3, len(patch.Changes))
+	assert.Equal(t, chunk.ID(0x0100), patch.Changes[0].ID)
+	assert.Equal(t, ChunkChanged, patch.Changes[0].Kind)
+	assert.Equal(t, []BlockChange{{Index: 1, Data: []byte{0xA2}}, {Index: 3, Data: []byte{0xA4}}}, patch.Changes[0].Blocks)
+	assert.Equal(t, chunk.ID(0x0400), patch.Changes[1].ID)
+	assert.Equal(t, ChunkAdded, patch.Changes[1].Kind)
+	assert.Equal(t, chunk.ID(0x0300), patch.Changes[2].ID)
+	assert.Equal(t, ChunkRemoved, patch.Changes[2].Kind)
+}
+
+func TestApplyProducesModifiedResources(t *testing.T) {
+	patch, _ := Create(baseStore(), modifiedStore())
+	store := baseStore()
+
+	err := patch.Apply(store)
+	require.Nil(t, err, "no error expected")
+
+	assert.Equal(t, []chunk.Identifier{chunk.ID(0x0100), chunk.ID(0x0200), chunk.ID(0x0400)}, store.IDs())
+	verifyBlocks(t, store, chunk.ID(0x0100), []byte{0x01}, []byte{0xA2}, []byte{0x03}, []byte{0xA4})
+	verifyBlocks(t, store, chunk.ID(0x0200), []byte{0x10, 0x11})
+	verifyBlocks(t, store, chunk.ID(0x0400), []byte{0x30, 0x31})
+	unchangedPatch, _ := Create(store, modifiedStore())
+	assert.Equal(t, 0, len(unchangedPatch.Changes))
+}
+
+func TestApplyReturnsErrorForDifferentBaseWithoutModifyingStore(t *testing.T) {
+	patch, _ := Create(baseStore(), modifiedStore())
+	store := baseStore()
+	store.Put(chunk.ID(0x0300), aChunk(chunk.Sound, []byte{0x21}))
+
+	err := patch.Apply(store)
+
+	assert.NotNil(t, err, "error expected")
+	assert.Equal(t, []chunk.Identifier{chunk.ID(0x0100), chunk.ID(0x0200), chunk.ID(0x0300)}, store.IDs())
+	verifyBlocks(t, store, chunk.ID(0x0100), []byte{0x01}, []byte{0x02, 0x02}, []byte{0x03})
+}
+
+func TestApplyReturnsErrorForUnreadableBaseWithoutModifyingStore(t *testing.T) {
+	modified := modifiedStore()
+	modified.Put(chunk.ID(0x0200), aChunk(chunk.Bitmap, []byte{0x10, 0x11}, []byte{0x12}))
+	patch, _ := Create(baseStore(), modified)
+	store := baseStore()
+	remaining := 1
+	store.Put(chunk.ID(0x0200), &chunk.Chunk{ContentType: chunk.Bitmap,
+		BlockProvider: exhaustingBlockProvider{blocks: [][]byte{{0x10, 0x11}}, remaining: &remaining}})
+
+	err := patch.Apply(store)
+
+	assert.NotNil(t, err, "error expected")
+	assert.Equal(t, []chunk.Identifier{chunk.ID(0x0100), chunk.ID(0x0200), chunk.ID(0x0300)}, store.IDs())
+	verifyBlocks(t, store, chunk.ID(0x0100), []byte{0x01}, []byte{0x02, 0x02}, []byte{0x03})
+}
+
+func TestApplyReturnsErrorForExistingAddedChunk(t *testing.T) {
+	patch, _ := Create(baseStore(), modifiedStore())
+	store := baseStore()
+	store.Put(chunk.ID(0x0400), aChunk(chunk.Palette, []byte{0x30, 0x31}))
+
+	err := patch.Apply(store)
+
+	assert.NotNil(t, err, "error expected")
+}
+
+func TestHashOfConsidersProperties(t *testing.T) {
+	plain := aChunk(chunk.Text, []byte{0x01})
+	compressed := aChunk(chunk.Text, []byte{0x01})
+	compressed.Compressed = true
+
+	plainHash, _ := HashOf(plain)
+	compressedHash, _ := HashOf(compressed)
+
+	assert.NotEqual(t, plainHash, compressedHash)
+}
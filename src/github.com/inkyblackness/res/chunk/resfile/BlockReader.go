@@ -7,6 +7,8 @@ type blockFunc func(index int) (io.Reader, error)
 type blockReader struct {
 	blockCount int
 	blockFunc  blockFunc
+
+	raw *rawChunk
 }
 
 // BlockCount returns the number of available blocks.
@@ -0,0 +1,15 @@
+package resfile
+
+// CacheStatistics describe the use of a cache for decompressed chunk data.
+type CacheStatistics struct {
+	// Hits counts the requests that could be served from the cache.
+	Hits int64
+	// Misses counts the requests that required a decompression.
+	Misses int64
+	// Evictions counts the entries that were dropped to keep the cache within its limit.
+	Evictions int64
+	// BytesDecompressed is the total amount of data that was decompressed.
+	BytesDecompressed int64
+	// BytesCached is the amount of data currently held in the cache.
+	BytesCached int64
+}
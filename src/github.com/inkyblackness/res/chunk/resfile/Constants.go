@@ -6,6 +6,17 @@ const (
 	chunkDirectoryFileOffsetPos = 0x7C
 	boundarySize                = 4
 
+	// unusedChunkID marks directory entries that only cover unused space in the file.
+	//
+	// This is an extension of the original format, which has no notion of unused space.
+	// The start of a chunk is only determined by the lengths of all preceding directory
+	// entries, so any gap in the file needs an entry of its own. The extension assumes
+	// that the original files do not use the chunk ID 0 for any chunk. A file that does
+	// is still readable, yet its chunk 0 is considered unused space.
+	unusedChunkID = uint16(0x0000)
+	// maxChunkLength is the largest length a directory entry can describe.
+	maxChunkLength = uint32(0x00FFFFFF)
+
 	chunkTypeFlagCompressed = byte(0x01)
 	chunkTypeFlagFragmented = byte(0x02)
 )
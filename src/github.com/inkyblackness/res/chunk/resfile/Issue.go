@@ -0,0 +1,88 @@
+package resfile
+
+import (
+	"fmt"
+
+	"github.com/inkyblackness/res/chunk"
+)
+
+// IssueKind categorizes problems found in resource files.
+type IssueKind int
+
+const (
+	// HeaderMismatch reports that the file does not start with the expected header comment.
+	HeaderMismatch = IssueKind(iota)
+	// DirectoryOutOfBounds reports that the chunk directory is not within the file.
+	DirectoryOutOfBounds
+	// FirstChunkOffsetInvalid reports that chunks would overlap the header.
+	FirstChunkOffsetInvalid
+	// ChunkOutOfBounds reports that the data of a chunk is not within the file.
+	ChunkOutOfBounds
+	// PaddingNotZero reports that the padding after a chunk contains data.
+	PaddingNotZero
+	// BlockListInvalid reports that the block list of a fragmented chunk can not be read,
+	// or refers to data outside of the chunk.
+	BlockListInvalid
+	// BlockListNotMonotonic reports that a block of a fragmented chunk ends before it starts.
+	BlockListNotMonotonic
+	// CompressedStreamInvalid reports that compressed data could not be decompressed.
+	CompressedStreamInvalid
+	// CompressedStreamUnterminated reports that compressed data ends without an end-of-stream marker.
+	CompressedStreamUnterminated
+	// UnpackedLengthMismatch reports that the unpacked length in the directory differs from the actual data.
+	UnpackedLengthMismatch
+)
+
+var issueKindNames = map[IssueKind]string{
+	HeaderMismatch:               "HeaderMismatch",
+	DirectoryOutOfBounds:         "DirectoryOutOfBounds",
+	FirstChunkOffsetInvalid:      "FirstChunkOffsetInvalid",
+	ChunkOutOfBounds:             "ChunkOutOfBounds",
+	PaddingNotZero:               "PaddingNotZero",
+	BlockListInvalid:             "BlockListInvalid",
+	BlockListNotMonotonic:        "BlockListNotMonotonic",
+	CompressedStreamInvalid:      "CompressedStreamInvalid",
+	CompressedStreamUnterminated: "CompressedStreamUnterminated",
+	UnpackedLengthMismatch:       "UnpackedLengthMismatch",
+}
+
+// String returns the name of the kind.
+func (kind IssueKind) String() string {
+	name, known := issueKindNames[kind]
+	if !known {
+		name = fmt.Sprintf("IssueKind(%d)", int(kind))
+	}
+	return name
+}
+
+// PreventsReading returns true for issues that make the affected data unreadable.
+// Other issues describe deviations from the format that readers tolerate.
+func (kind IssueKind) PreventsReading() bool {
+	return (kind != HeaderMismatch) && (kind != PaddingNotZero) && (kind != UnpackedLengthMismatch)
+}
+
+// Issue describes a single problem found in a resource file.
+type Issue struct {
+	// Kind categorizes the problem.
+	Kind IssueKind
+	// ChunkID identifies the affected chunk. It is nil for issues of the file as a whole.
+	ChunkID chunk.Identifier
+	// BlockIndex identifies the affected block. It is -1 if the issue is not specific to a block.
+	BlockIndex int
+	// Offset is the position in the file the issue refers to.
+	Offset int64
+	// Description explains the problem in detail.
+	Description string
+}
+
+// String returns a readable presentation of the issue.
+func (issue Issue) String() string {
+	location := "file"
+	if issue.ChunkID != nil {
+		location = fmt.Sprintf("chunk %v", issue.ChunkID)
+		if issue.BlockIndex >= 0 {
+			location += fmt.Sprintf(" block %d", issue.BlockIndex)
+		}
+	}
+	return fmt.Sprintf("%v at 0x%08X (%s): %s", issue.Kind, issue.Offset, location, issue.Description)
+}
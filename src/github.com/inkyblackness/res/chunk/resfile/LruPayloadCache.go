@@ -0,0 +1,81 @@
+package resfile
+
+import (
+	"container/list"
+	"sync"
+)
+
+type lruPayloadEntry struct {
+	id   uint16
+	data []byte
+}
+
+// lruPayloadCache keeps decompressed data up to a given amount of bytes.
+// Should the limit be exceeded, the least recently used entries are dropped.
+// The most recently used entry is always kept, even if it exceeds the limit.
+type lruPayloadCache struct {
+	lock  sync.Mutex
+	limit int64
+
+	order   *list.List
+	entries map[uint16]*list.Element
+	stats   CacheStatistics
+}
+
+func newLruPayloadCache(limit int64) *lruPayloadCache {
+	return &lruPayloadCache{
+		limit:   limit,
+		order:   list.New(),
+		entries: make(map[uint16]*list.Element)}
+}
+
+func (cache *lruPayloadCache) payload(id uint16, decompress func() ([]byte, error)) ([]byte, error) {
+	if data, cached := cache.cached(id); cached {
+		return data, nil
+	}
+	data, err := decompress()
+	if err != nil {
+		return nil, err
+	}
+	return cache.store(id, data), nil
+}
+
+func (cache *lruPayloadCache) statistics() CacheStatistics {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	return cache.stats
+}
+
+func (cache *lruPayloadCache) cached(id uint16) ([]byte, bool) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	element, existing := cache.entries[id]
+	if !existing {
+		cache.stats.Misses++
+		return nil, false
+	}
+	cache.stats.Hits++
+	cache.order.MoveToFront(element)
+	return element.Value.(*lruPayloadEntry).data, true
+}
+
+// store adds the given data to the cache. If the entry was added concurrently,
+// the already stored data is kept and returned.
+func (cache *lruPayloadCache) store(id uint16, data []byte) []byte {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	cache.stats.BytesDecompressed += int64(len(data))
+	if element, existing := cache.entries[id]; existing {
+		cache.order.MoveToFront(element)
+		return element.Value.(*lruPayloadEntry).data
+	}
+	cache.entries[id] = cache.order.PushFront(&lruPayloadEntry{id: id, data: data})
+	cache.stats.BytesCached += int64(len(data))
+	for (cache.stats.BytesCached > cache.limit) && (cache.order.Len() > 1) {
+		oldest := cache.order.Remove(cache.order.Back()).(*lruPayloadEntry)
+		delete(cache.entries, oldest.id)
+		cache.stats.BytesCached -= int64(len(oldest.data))
+		cache.stats.Evictions++
+	}
+	return data
+}
@@ -0,0 +1,71 @@
+package resfile
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func payloadOf(size int) func() ([]byte, error) {
+	return func() ([]byte, error) { return make([]byte, size), nil }
+}
+
+func TestLruPayloadCacheReturnsDecompressedData(t *testing.T) {
+	cache := newLruPayloadCache(100)
+
+	data, err := cache.payload(1, func() ([]byte, error) { return []byte{0x01, 0x02}, nil })
+
+	assert.Nil(t, err, "no error expected")
+	assert.Equal(t, []byte{0x01, 0x02}, data)
+}
+
+func TestLruPayloadCacheReturnsErrorOfDecompression(t *testing.T) {
+	cache := newLruPayloadCache(100)
+	expectedErr := errors.New("broken")
+
+	_, err := cache.payload(1, func() ([]byte, error) { return nil, expectedErr })
+
+	assert.Equal(t, expectedErr, err)
+	assert.Equal(t, int64(0), cache.statistics().BytesCached)
+}
+
+func TestLruPayloadCacheCountsHitsAndMisses(t *testing.T) {
+	cache := newLruPayloadCache(100)
+
+	cache.payload(1, payloadOf(10)) // nolint: errcheck
+	cache.payload(1, payloadOf(10)) // nolint: errcheck
+	cache.payload(2, payloadOf(20)) // nolint: errcheck
+
+	assert.Equal(t, CacheStatistics{Hits: 1, Misses: 2, BytesDecompressed: 30, BytesCached: 30}, cache.statistics())
+}
+
+func TestLruPayloadCacheEvictsLeastRecentlyUsedEntries(t *testing.T) {
+	cache := newLruPayloadCache(50)
+
+	cache.payload(1, payloadOf(20)) // nolint: errcheck
+	cache.payload(2, payloadOf(20)) // nolint: errcheck
+	cache.payload(1, payloadOf(20)) // nolint: errcheck
+	cache.payload(3, payloadOf(20)) // nolint: errcheck
+	cache.payload(1, payloadOf(20)) // nolint: errcheck
+	cache.payload(2, payloadOf(20)) // nolint: errcheck
+
+	stats := cache.statistics()
+	assert.Equal(t, int64(2), stats.Hits)
+	assert.Equal(t, int64(4), stats.Misses)
+	assert.Equal(t, int64(2), stats.Evictions)
+	assert.Equal(t, int64(40), stats.BytesCached)
+}
+
+func TestLruPayloadCacheKeepsMostRecentEntryEvenIfTooLarge(t *testing.T) {
+	cache := newLruPayloadCache(10)
+
+	cache.payload(1, payloadOf(5))  // nolint: errcheck
+	cache.payload(2, payloadOf(30)) // nolint: errcheck
+	cache.payload(2, payloadOf(30)) // nolint: errcheck
+
+	stats := cache.statistics()
+	assert.Equal(t, int64(1), stats.Hits)
+	assert.Equal(t, int64(1), stats.Evictions)
+	assert.Equal(t, int64(30), stats.BytesCached)
+}
@@ -0,0 +1,79 @@
+package resfile
+
+import (
+	"bytes"
+	"os"
+
+	"github.com/inkyblackness/res/chunk"
+)
+
+// MappedProvider is a chunk provider for resource files that are mapped into memory.
+// Compressed fragmented chunks are decompressed on demand and their data is kept
+// in a cache of limited size. Blocks of other chunks are read from the mapped file.
+//
+// A MappedProvider is safe for concurrent use. Chunks retrieved from it must no
+// longer be used once the provider is closed.
+type MappedProvider struct {
+	data     []byte
+	reader   *Reader
+	payloads *lruPayloadCache
+}
+
+// OpenMappedProvider maps the given file into memory and returns a provider for it.
+// The cacheLimit specifies the amount of bytes of decompressed data that are kept.
+// The data of the most recently used chunk is always kept, even if it exceeds the limit.
+func OpenMappedProvider(filename string, cacheLimit int64) (provider *MappedProvider, err error) {
+	file, fileErr := os.Open(filename)
+	if fileErr != nil {
+		return nil, fileErr
+	}
+	defer file.Close() // nolint: errcheck
+
+	info, infoErr := file.Stat()
+	if infoErr != nil {
+		return nil, infoErr
+	}
+	var data []byte
+	if info.Size() > 0 {
+		data, err = memoryMap(file, info.Size())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	payloads := newLruPayloadCache(cacheLimit)
+	reader, readerErr := readerFrom(bytes.NewReader(data), payloads)
+	if readerErr != nil {
+		if data != nil {
+			memoryUnmap(data) // nolint: errcheck
+		}
+		return nil, readerErr
+	}
+
+	return &MappedProvider{data: data, reader: reader, payloads: payloads}, nil
+}
+
+// IDs returns the chunk identifier available via this provider.
+// The order in the slice is the same as in the underlying file.
+func (provider *MappedProvider) IDs() []chunk.Identifier {
+	return provider.reader.IDs()
+}
+
+// Chunk returns the chunk for the given identifier.
+func (provider *MappedProvider) Chunk(id chunk.Identifier) (*chunk.Chunk, error) {
+	return provider.reader.Chunk(id)
+}
+
+// Statistics returns the current usage statistics of the decompression cache.
+func (provider *MappedProvider) Statistics() CacheStatistics {
+	return provider.payloads.statistics()
+}
+
+// Close releases the mapping of the file.
+func (provider *MappedProvider) Close() (err error) {
+	if provider.data != nil {
+		err = memoryUnmap(provider.data)
+		provider.data = nil
+	}
+	return
+}
@@ -0,0 +1,68 @@
+package resfile
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/inkyblackness/res/chunk"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func withTempFile(t *testing.T, data []byte, test func(filename string)) {
+	file, err := ioutil.TempFile("", "resfile")
+	require.Nil(t, err, "no error expected creating temp file")
+	defer os.Remove(file.Name()) // nolint: errcheck
+	_, err = file.Write(data)
+	file.Close() // nolint: errcheck
+	require.Nil(t, err, "no error expected writing temp file")
+	test(file.Name())
+}
+
+func TestOpenMappedProviderReturnsErrorForMissingFile(t *testing.T) {
+	provider, err := OpenMappedProvider("does-not-exist.res", 1024)
+
+	assert.Nil(t, provider)
+	assert.NotNil(t, err, "error expected")
+}
+
+func TestOpenMappedProviderReturnsErrorForInvalidFile(t *testing.T) {
+	withTempFile(t, []byte{0x01, 0x02, 0x03}, func(filename string) {
+		_, err := OpenMappedProvider(filename, 1024)
+		assert.NotNil(t, err, "error expected")
+	})
+}
+
+func TestMappedProviderProvidesChunksOfFile(t *testing.T) {
+	withTempFile(t, exampleResourceFile(), func(filename string) {
+		provider, err := OpenMappedProvider(filename, 1024)
+		require.Nil(t, err, "no error expected")
+		defer provider.Close() // nolint: errcheck
+
+		assert.Equal(t, []chunk.Identifier{exampleChunkIDSingleBlockChunk, exampleChunkIDSingleBlockChunkCompressed,
+			exampleChunkIDFragmentedChunk, exampleChunkIDFragmentedChunkCompressed}, provider.IDs())
+		singleChunk, _ := provider.Chunk(exampleChunkIDSingleBlockChunkCompressed)
+		verifyBlockContent(t, singleChunk, 0, []byte{0x02, 0x02})
+		fragmentedChunk, _ := provider.Chunk(exampleChunkIDFragmentedChunk)
+		verifyBlockContent(t, fragmentedChunk, 1, []byte{0x31, 0x31, 0x31})
+	})
+}
+
+func TestMappedProviderDecompressesFragmentedChunksOnDemand(t *testing.T) {
+	withTempFile(t, exampleResourceFile(), func(filename string) {
+		provider, _ := OpenMappedProvider(filename, 1024)
+		defer provider.Close() // nolint: errcheck
+
+		compressedChunk, _ := provider.Chunk(exampleChunkIDFragmentedChunkCompressed)
+		assert.Equal(t, CacheStatistics{}, provider.Statistics())
+		verifyBlockContent(t, compressedChunk, 2, []byte{0x42})
+		verifyBlockContent(t, compressedChunk, 0, []byte{0x40, 0x40})
+
+		stats := provider.Statistics()
+		assert.Equal(t, int64(1), stats.Misses)
+		assert.Equal(t, int64(1), stats.Hits)
+		assert.Equal(t, int64(7), stats.BytesDecompressed)
+	})
+}
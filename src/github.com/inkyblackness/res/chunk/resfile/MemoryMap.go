@@ -0,0 +1,17 @@
+//go:build !windows
+// +build !windows
+
+package resfile
+
+import (
+	"os"
+	"syscall"
+)
+
+func memoryMap(file *os.File, size int64) ([]byte, error) {
+	return syscall.Mmap(int(file.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+}
+
+func memoryUnmap(data []byte) error {
+	return syscall.Munmap(data)
+}
@@ -0,0 +1,34 @@
+package resfile
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+func memoryMap(file *os.File, size int64) ([]byte, error) {
+	mapping, err := syscall.CreateFileMapping(syscall.Handle(file.Fd()), nil, syscall.PAGE_READONLY,
+		uint32(size>>32), uint32(size), nil)
+	if err != nil {
+		return nil, os.NewSyscallError("CreateFileMapping", err)
+	}
+	defer syscall.CloseHandle(mapping) // nolint: errcheck
+
+	address, err := syscall.MapViewOfFile(mapping, syscall.FILE_MAP_READ, 0, 0, uintptr(size))
+	if err != nil {
+		return nil, os.NewSyscallError("MapViewOfFile", err)
+	}
+	return unsafe.Slice(viewPointer(address), int(size)), nil
+}
+
+// viewPointer returns the address of a mapped view as a pointer. The view is not
+// managed by the Go runtime, so the address stays valid until the view is unmapped.
+// The address is reinterpreted in place rather than converted, as converting a
+// uintptr value to unsafe.Pointer is only valid for addresses of Go memory.
+func viewPointer(address uintptr) *byte {
+	return *(**byte)(unsafe.Pointer(&address))
+}
+
+func memoryUnmap(data []byte) error {
+	return syscall.UnmapViewOfFile(uintptr(unsafe.Pointer(&data[0])))
+}
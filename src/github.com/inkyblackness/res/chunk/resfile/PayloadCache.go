@@ -0,0 +1,35 @@
+package resfile
+
+import "sync"
+
+// payloadCache keeps the decompressed data of fragmented chunks.
+type payloadCache interface {
+	// payload returns the decompressed data of the identified chunk.
+	// The decompress function is called if the data is not available.
+	payload(id uint16, decompress func() ([]byte, error)) ([]byte, error)
+}
+
+// permanentPayloadCache keeps any decompressed data for the lifetime of the cache.
+type permanentPayloadCache struct {
+	lock     sync.Mutex
+	payloads map[uint16][]byte
+}
+
+func newPermanentPayloadCache() *permanentPayloadCache {
+	return &permanentPayloadCache{payloads: make(map[uint16][]byte)}
+}
+
+func (cache *permanentPayloadCache) payload(id uint16, decompress func() ([]byte, error)) ([]byte, error) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	data, existing := cache.payloads[id]
+	if !existing {
+		var err error
+		data, err = decompress()
+		if err != nil {
+			return nil, err
+		}
+		cache.payloads[id] = data
+	}
+	return data, nil
+}
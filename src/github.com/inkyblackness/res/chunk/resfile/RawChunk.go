@@ -0,0 +1,51 @@
+package resfile
+
+import (
+	"io"
+
+	"github.com/inkyblackness/res/chunk"
+)
+
+// rawChunk refers to the serialized form of a chunk in a resource file.
+type rawChunk struct {
+	source      io.ReaderAt
+	entry       chunkDirectoryEntry
+	startOffset uint32
+}
+
+// rawChunkOf returns the serialized form of given chunk, if it was retrieved
+// from a Reader and is unmodified. Otherwise, nil is returned.
+func rawChunkOf(data *chunk.Chunk) *rawChunk {
+	provider, fromReader := data.BlockProvider.(*blockReader)
+	if !fromReader || (provider.raw == nil) || data.HasModifiedBlocks() {
+		return nil
+	}
+	raw := provider.raw
+	chunkType := raw.entry.chunkType()
+	if (((chunkType & chunkTypeFlagCompressed) != 0) != data.Compressed) ||
+		(((chunkType & chunkTypeFlagFragmented) != 0) != data.Fragmented) ||
+		(chunk.ContentType(raw.entry.contentType()) != data.ContentType) {
+		return nil
+	}
+	return raw
+}
+
+// data returns a reader for the packed chunk data.
+func (raw *rawChunk) data() io.Reader {
+	return io.NewSectionReader(raw.source, int64(raw.startOffset), int64(raw.entry.packedLength()))
+}
+
+// padding returns the bytes between the end of the chunk and the next boundary.
+func (raw *rawChunk) padding() []byte {
+	endOffset := raw.startOffset + raw.entry.packedLength()
+	padding := make([]byte, alignedOffset(endOffset)-endOffset)
+	raw.source.ReadAt(padding, int64(endOffset)) // nolint: errcheck
+	return padding
+}
+
+// headerComment returns the header comment of the file the chunk is from.
+func (raw *rawChunk) headerComment() []byte {
+	comment := make([]byte, chunkDirectoryFileOffsetPos)
+	raw.source.ReadAt(comment, 0) // nolint: errcheck
+	return comment
+}
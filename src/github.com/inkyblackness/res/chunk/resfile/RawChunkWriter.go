@@ -0,0 +1,11 @@
+package resfile
+
+// rawChunkWriter is used for chunks that are copied in their serialized form.
+type rawChunkWriter struct {
+	unpackedLength uint32
+	padding        []byte
+}
+
+func (writer *rawChunkWriter) finish() uint32 {
+	return writer.unpackedLength
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"sync"
 
 	"github.com/inkyblackness/res/chunk"
 	"github.com/inkyblackness/res/chunk/resfile/compression"
@@ -15,12 +16,21 @@ import (
 
 // Reader provides methods to extract resource data from a serialized form.
 // Chunks may be accessed out of sequence due to the nature of the underlying io.ReaderAt.
+// A Reader is safe for concurrent use, as long as the source is as well.
 type Reader struct {
 	source           io.ReaderAt
 	firstChunkOffset uint32
 	directory        []chunkDirectoryEntry
+	index            map[uint16]indexedEntry
 
-	cache map[uint16]*chunk.Chunk
+	cacheLock sync.RWMutex
+	cache     map[uint16]*chunk.Chunk
+	payloads  payloadCache
+}
+
+type indexedEntry struct {
+	startOffset uint32
+	entry       *chunkDirectoryEntry
 }
 
 var errSourceNil = errors.New("source is nil")
@@ -31,6 +41,10 @@ var errFormatMismatch = errors.New("format mismatch")
 // Should the provided decoder not follow the resource file format, an error
 // is returned.
 func ReaderFrom(source io.ReaderAt) (reader *Reader, err error) {
+	return readerFrom(source, newPermanentPayloadCache())
+}
+
+func readerFrom(source io.ReaderAt, payloads payloadCache) (reader *Reader, err error) {
 	if source == nil {
 		return nil, errSourceNil
 	}
@@ -45,35 +59,44 @@ func ReaderFrom(source io.ReaderAt) (reader *Reader, err error) {
 		return nil, err
 	}
 
-	reader = &Reader{
+	return newReader(source, firstChunkOffset, directory, payloads), nil
+}
+
+func newReader(source io.ReaderAt, firstChunkOffset uint32, directory []chunkDirectoryEntry, payloads payloadCache) *Reader {
+	return &Reader{
 		source:           source,
 		firstChunkOffset: firstChunkOffset,
 		directory:        directory,
-		cache:            make(map[uint16]*chunk.Chunk)}
-
-	return
+		index:            indexDirectory(firstChunkOffset, directory),
+		cache:            make(map[uint16]*chunk.Chunk),
+		payloads:         payloads}
 }
 
 // IDs returns the chunk identifier available via this reader.
 // The order in the slice is the same as in the underlying serialized form.
+// Entries that describe unused space in the file are not reported.
 func (reader *Reader) IDs() []chunk.Identifier {
-	ids := make([]chunk.Identifier, len(reader.directory))
-	for index, entry := range reader.directory {
-		ids[index] = chunk.ID(entry.ID)
+	ids := make([]chunk.Identifier, 0, len(reader.directory))
+	for _, entry := range reader.directory {
+		if entry.ID != unusedChunkID {
+			ids = append(ids, chunk.ID(entry.ID))
+		}
 	}
 	return ids
 }
 
 // Chunk returns a reader for the specified chunk.
 // An error is returned if either the ID is not known, or the chunk could not be prepared.
+// Chunks are cached: subsequent calls with the same ID return the same instance.
 func (reader *Reader) Chunk(id chunk.Identifier) (retrievedChunk *chunk.Chunk, err error) {
-	if retrievedChunk, existing := reader.cache[id.Value()]; existing {
-		return retrievedChunk, nil
+	if cachedChunk := reader.cachedChunk(id.Value()); cachedChunk != nil {
+		return cachedChunk, nil
 	}
-	chunkStartOffset, entry := reader.findEntry(id.Value())
-	if entry == nil {
+	indexed, existing := reader.index[id.Value()]
+	if !existing {
 		return nil, chunk.ErrChunkDoesNotExist(id)
 	}
+	chunkStartOffset, entry := indexed.startOffset, indexed.entry
 	chunkType := entry.chunkType()
 	compressed := (chunkType & chunkTypeFlagCompressed) != 0
 	fragmented := (chunkType & chunkTypeFlagFragmented) != 0
@@ -85,11 +108,29 @@ func (reader *Reader) Chunk(id chunk.Identifier) (retrievedChunk *chunk.Chunk, e
 		retrievedChunk, err = reader.newSingleBlockChunkReader(entry, contentType, compressed, chunkStartOffset)
 	}
 	if err == nil {
-		reader.cache[id.Value()] = retrievedChunk
+		retrievedChunk = reader.cacheChunk(id.Value(), retrievedChunk)
 	}
 	return
 }
 
+func (reader *Reader) cachedChunk(id uint16) *chunk.Chunk {
+	reader.cacheLock.RLock()
+	defer reader.cacheLock.RUnlock()
+	return reader.cache[id]
+}
+
+// cacheChunk stores the given chunk, unless another one was stored concurrently.
+// The returned chunk is the one that is kept in the cache.
+func (reader *Reader) cacheChunk(id uint16, newChunk *chunk.Chunk) *chunk.Chunk {
+	reader.cacheLock.Lock()
+	defer reader.cacheLock.Unlock()
+	if existingChunk, existing := reader.cache[id]; existing {
+		return existingChunk
+	}
+	reader.cache[id] = newChunk
+	return newChunk
+}
+
 func readAndVerifyHeader(source io.ReadSeeker) (dirOffset uint32, err error) {
 	coder := serial.NewPositioningDecoder(source)
 	data := make([]byte, chunkDirectoryFileOffsetPos)
@@ -102,7 +143,7 @@ func readAndVerifyHeader(source io.ReadSeeker) (dirOffset uint32, err error) {
 	}
 	expected[len(headerString)] = commentTerminator
 	if !bytes.Equal(data[:len(expected)], expected) {
-		return 0, errFormatMismatch
+		return dirOffset, errFormatMismatch
 	}
 
 	return dirOffset, coder.FirstError()
@@ -129,18 +170,24 @@ func readDirectoryAt(dirOffset uint32, source io.ReaderAt) (firstChunkOffset uin
 	return
 }
 
-func (reader *Reader) findEntry(id uint16) (startOffset uint32, entry *chunkDirectoryEntry) {
-	startOffset = reader.firstChunkOffset
-	for index := 0; (index < len(reader.directory)) && (entry == nil); index++ {
-		cur := &reader.directory[index]
-		if cur.ID == id {
-			entry = cur
-		} else {
-			startOffset += cur.packedLength()
-			startOffset += (boundarySize - (startOffset % boundarySize)) % boundarySize
+// alignedOffset returns the given offset moved forward to the next chunk boundary.
+func alignedOffset(offset uint32) uint32 {
+	return offset + (boundarySize-(offset%boundarySize))%boundarySize
+}
+
+// indexDirectory calculates the start offsets of all chunks in the directory.
+// Should an ID be listed more than once, only the first entry is indexed.
+func indexDirectory(firstChunkOffset uint32, directory []chunkDirectoryEntry) map[uint16]indexedEntry {
+	index := make(map[uint16]indexedEntry, len(directory))
+	startOffset := firstChunkOffset
+	for entryIndex := range directory {
+		entry := &directory[entryIndex]
+		if _, existing := index[entry.ID]; !existing && (entry.ID != unusedChunkID) {
+			index[entry.ID] = indexedEntry{startOffset: startOffset, entry: entry}
 		}
+		startOffset = alignedOffset(startOffset + entry.packedLength())
 	}
-	return
+	return index
 }
 
 type blockListEntry struct {
@@ -154,15 +201,25 @@ func (reader *Reader) newFragmentedChunkReader(entry *chunkDirectoryEntry,
 
 	firstBlockOffset, blockList, err := reader.readBlockList(chunkDataReader)
 	if err != nil {
-		fmt.Printf("Fail with size: %v at start offset 0x%08X\n", chunkDataReader.Size(), chunkStartOffset)
-		return nil, err
+		return nil, fmt.Errorf("failed to read block list of chunk %v with size %v at offset 0x%08X: %v",
+			chunk.ID(entry.ID), chunkDataReader.Size(), chunkStartOffset, err)
 	}
 	blockCount := len(blockList)
 
 	rawBlockDataReader := io.NewSectionReader(chunkDataReader, int64(firstBlockOffset), chunkDataReader.Size()-int64(firstBlockOffset))
-	var uncompressedReader io.ReaderAt
-	if !compressed {
-		uncompressedReader = rawBlockDataReader
+	uncompressedData := func() (io.ReaderAt, error) { return rawBlockDataReader, nil }
+	if compressed {
+		decompress := func() ([]byte, error) {
+			return ioutil.ReadAll(compression.NewDecompressor(rawBlockDataReader))
+		}
+		chunkID := entry.ID
+		uncompressedData = func() (io.ReaderAt, error) {
+			data, err := reader.payloads.payload(chunkID, decompress)
+			if err != nil {
+				return nil, err
+			}
+			return bytes.NewReader(data), nil
+		}
 	}
 
 	blockFunc := func(index int) (io.Reader, error) {
@@ -170,13 +227,9 @@ func (reader *Reader) newFragmentedChunkReader(entry *chunkDirectoryEntry,
 			return nil, fmt.Errorf("block index wrong: %v/%v", index, blockCount)
 		}
 
-		if compressed && (uncompressedReader == nil) {
-			decompressor := compression.NewDecompressor(rawBlockDataReader)
-			decompressedData, err := ioutil.ReadAll(decompressor)
-			if err != nil {
-				return nil, err
-			}
-			uncompressedReader = bytes.NewReader(decompressedData)
+		uncompressedReader, err := uncompressedData()
+		if err != nil {
+			return nil, err
 		}
 
 		entry := blockList[index]
@@ -184,11 +237,12 @@ func (reader *Reader) newFragmentedChunkReader(entry *chunkDirectoryEntry,
 		return reader, nil
 	}
 
+	raw := &rawChunk{source: reader.source, entry: *entry, startOffset: chunkStartOffset}
 	return &chunk.Chunk{
 		Fragmented:    true,
 		ContentType:   contentType,
 		Compressed:    compressed,
-		BlockProvider: &blockReader{len(blockList), blockFunc}}, nil
+		BlockProvider: &blockReader{blockCount: len(blockList), blockFunc: blockFunc, raw: raw}}, nil
 }
 
 func (reader *Reader) readBlockList(source io.Reader) (uint32, []blockListEntry, error) {
@@ -207,11 +261,6 @@ func (reader *Reader) readBlockList(source io.Reader) (uint32, []blockListEntry,
 		lastBlockEndOffset = endOffset
 	}
 
-	if listDecoder.FirstError() != nil {
-		fmt.Printf("reading of block list failed. count: %v, first offset: %v\n",
-			blockCount, firstBlockOffset)
-	}
-
 	return firstBlockOffset, blockList, listDecoder.FirstError()
 }
 
@@ -230,9 +279,10 @@ func (reader *Reader) newSingleBlockChunkReader(entry *chunkDirectoryEntry,
 		return io.LimitReader(chunkSource, int64(chunkSize)), nil
 	}
 
+	raw := &rawChunk{source: reader.source, entry: *entry, startOffset: chunkStartOffset}
 	return &chunk.Chunk{
 		Fragmented:    false,
 		ContentType:   contentType,
 		Compressed:    compressed,
-		BlockProvider: &blockReader{1, blockFunc}}, nil
+		BlockProvider: &blockReader{blockCount: 1, blockFunc: blockFunc, raw: raw}}, nil
 }
@@ -3,10 +3,13 @@ package resfile
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"sync"
 	"testing"
 
 	"github.com/inkyblackness/res/chunk"
+	"github.com/inkyblackness/res/serial"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -67,6 +70,37 @@ func TestReaderIDsReturnsTheStoredChunkIDsInOrderFromFile(t *testing.T) {
 		exampleChunkIDFragmentedChunk, exampleChunkIDFragmentedChunkCompressed}, reader.IDs())
 }
 
+func TestReaderCanDecodeFileInOriginalLayout(t *testing.T) {
+	data := rawResourceFile(
+		rawChunkEntry{id: 0x0100, contentType: 0x01, data: []byte{0x10, 0x11, 0x12}},
+		rawChunkEntry{id: 0x0200, contentType: 0x02, data: []byte{0x20, 0x21, 0x22, 0x23, 0x24}})
+	reader, err := ReaderFrom(bytes.NewReader(data))
+	require.Nil(t, err, "no error expected")
+
+	assert.Equal(t, []chunk.Identifier{chunk.ID(0x0100), chunk.ID(0x0200)}, reader.IDs())
+	chunkReader, err := reader.Chunk(chunk.ID(0x0200))
+	require.Nil(t, err, "no error expected")
+	assert.Equal(t, chunk.ContentType(0x02), chunkReader.ContentType)
+	verifyBlockContent(t, chunkReader, 0, []byte{0x20, 0x21, 0x22, 0x23, 0x24})
+}
+
+func TestReaderSkipsEntriesOfUnusedSpace(t *testing.T) {
+	data := rawResourceFile(
+		rawChunkEntry{id: 0x0100, contentType: 0x01, data: []byte{0x10, 0x11, 0x12}},
+		rawChunkEntry{id: unusedChunkID, data: []byte{0xEE, 0xEE, 0xEE, 0xEE, 0xEE, 0xEE, 0xEE, 0xEE}},
+		rawChunkEntry{id: 0x0200, contentType: 0x02, data: []byte{0x20, 0x21}})
+	reader, err := ReaderFrom(bytes.NewReader(data))
+	require.Nil(t, err, "no error expected")
+
+	assert.Equal(t, []chunk.Identifier{chunk.ID(0x0100), chunk.ID(0x0200)}, reader.IDs())
+	_, err = reader.Chunk(chunk.ID(unusedChunkID))
+	assert.NotNil(t, err, "error expected for unused space")
+	chunkReader, err := reader.Chunk(chunk.ID(0x0200))
+	require.Nil(t, err, "no error expected")
+	verifyBlockContent(t, chunkReader, 0, []byte{0x20, 0x21})
+	assert.True(t, Validate(bytes.NewReader(data), int64(len(data))).Valid(), "file should be valid")
+}
+
 func TestReaderChunkReturnsErrorForUnknownID(t *testing.T) {
 	reader, _ := ReaderFrom(bytes.NewReader(emptyResourceFile()))
 	chunkReader, err := reader.Chunk(chunk.ID(0x1111))
@@ -123,6 +157,19 @@ func TestReaderChunkWithCompressedSingleBlockContent(t *testing.T) {
 	verifyBlockContent(t, chunkReader, 0, []byte{0x02, 0x02})
 }
 
+func TestReaderChunkWithTruncatedCompressedContentReturnsError(t *testing.T) {
+	data := exampleResourceFile()
+	data[directoryEntryOffset(data, 1)+6] -= 4
+	reader, _ := ReaderFrom(bytes.NewReader(data))
+	chunkReader, _ := reader.Chunk(exampleChunkIDSingleBlockChunkCompressed)
+
+	blockReader, err := chunkReader.Block(0)
+	require.Nil(t, err, "no error expected")
+	_, err = ioutil.ReadAll(blockReader)
+
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+}
+
 func TestReaderChunkWithUncompressedFragmentedContent(t *testing.T) {
 	reader, _ := ReaderFrom(bytes.NewReader(exampleResourceFile()))
 	chunkReader, _ := reader.Chunk(exampleChunkIDFragmentedChunk)
@@ -142,6 +189,52 @@ func TestReaderChunkWithCompressedFragmentedContent(t *testing.T) {
 	verifyBlockContent(t, chunkReader, 2, []byte{0x42})
 }
 
+func TestReaderChunkReturnsFirstEntryForDuplicateIDs(t *testing.T) {
+	store := serial.NewByteStore()
+	writer, _ := NewWriter(store)
+	chunk1, _ := writer.CreateChunk(chunk.ID(0x0100), chunk.ContentType(0x01), false)
+	chunk1.Write([]byte{0x01})
+	chunk2, _ := writer.CreateChunk(chunk.ID(0x0200), chunk.ContentType(0x01), false)
+	chunk2.Write([]byte{0x02, 0x02})
+	chunk3, _ := writer.CreateChunk(chunk.ID(0x0100), chunk.ContentType(0x01), false)
+	chunk3.Write([]byte{0x03, 0x03, 0x03})
+	writer.Finish()
+
+	reader, _ := ReaderFrom(bytes.NewReader(store.Data()))
+	chunkReader, err := reader.Chunk(chunk.ID(0x0100))
+	require.Nil(t, err, "no error expected")
+	verifyBlockContent(t, chunkReader, 0, []byte{0x01})
+	chunkReader, err = reader.Chunk(chunk.ID(0x0200))
+	require.Nil(t, err, "no error expected")
+	verifyBlockContent(t, chunkReader, 0, []byte{0x02, 0x02})
+}
+
+func TestReaderCanBeUsedConcurrently(t *testing.T) {
+	reader, _ := ReaderFrom(bytes.NewReader(exampleResourceFile()))
+	var wg sync.WaitGroup
+	chunks := make([]*chunk.Chunk, 8)
+
+	for worker := 0; worker < len(chunks); worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for _, id := range reader.IDs() {
+				retrievedChunk, _ := reader.Chunk(id)
+				if id.Value() == exampleChunkIDFragmentedChunkCompressed.Value() {
+					chunks[worker] = retrievedChunk
+					retrievedChunk.Block(worker % 3) // nolint: errcheck
+				}
+			}
+		}(worker)
+	}
+	wg.Wait()
+
+	for _, retrievedChunk := range chunks {
+		assert.True(t, chunks[0] == retrievedChunk, "Chunks should be the same instance")
+	}
+	verifyBlockContent(t, chunks[0], 1, []byte{0x41, 0x41, 0x41, 0x41})
+}
+
 func verifyBlockContent(t *testing.T, blockProvider chunk.BlockProvider, blockIndex int, expected []byte) {
 	blockReader, readerErr := blockProvider.Block(blockIndex)
 	assert.Nil(t, readerErr, "error retrieving reader")
@@ -0,0 +1,51 @@
+package resfile
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/inkyblackness/res/chunk"
+)
+
+var errDirectoryUnreadable = errors.New("directory is not readable")
+
+// Salvage writes all readable chunks of the resource file in given source to
+// the target, and returns the identifiers of the written chunks.
+// Chunks with issues that prevent reading them are skipped, see Validate.
+// An error is returned if the directory of the source can not be read, or
+// if writing to the target fails.
+func Salvage(source io.ReaderAt, size int64, target io.WriteSeeker) (salvaged []chunk.Identifier, err error) {
+	report := Validate(source, size)
+	if !report.directoryReadable {
+		return nil, errDirectoryUnreadable
+	}
+	reader := newReader(source, report.firstChunkOffset, report.directory, newPermanentPayloadCache())
+	writer, err := NewWriter(target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create writer: %v", err)
+	}
+
+	written := make(map[uint16]bool)
+	for _, id := range reader.IDs() {
+		if written[id.Value()] || !report.Readable(id) {
+			continue
+		}
+		entry, chunkErr := reader.Chunk(id)
+		if chunkErr != nil {
+			continue
+		}
+		err = writeChunk(writer, id, entry)
+		if err != nil {
+			return nil, err
+		}
+		written[id.Value()] = true
+		salvaged = append(salvaged, id)
+	}
+
+	err = writer.Finish()
+	if err != nil {
+		return nil, fmt.Errorf("failed to finish writer: %v", err)
+	}
+	return salvaged, nil
+}
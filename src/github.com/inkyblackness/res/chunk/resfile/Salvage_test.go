@@ -0,0 +1,38 @@
+package resfile
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/inkyblackness/res/chunk"
+	"github.com/inkyblackness/res/serial"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSalvageWritesReadableChunks(t *testing.T) {
+	data := exampleResourceFile()
+	data[chunkStartOffset(data, exampleChunkIDFragmentedChunk)+6] = 0x00
+	target := serial.NewByteStore()
+
+	salvaged, err := Salvage(bytes.NewReader(data), int64(len(data)), target)
+	require.Nil(t, err, "no error expected")
+
+	expectedIDs := []chunk.Identifier{exampleChunkIDSingleBlockChunk, exampleChunkIDSingleBlockChunkCompressed,
+		exampleChunkIDFragmentedChunkCompressed}
+	assert.Equal(t, expectedIDs, salvaged)
+	reader, err := ReaderFrom(bytes.NewReader(target.Data()))
+	require.Nil(t, err, "no error expected reading salvaged file")
+	assert.Equal(t, expectedIDs, reader.IDs())
+	compressedChunk, _ := reader.Chunk(exampleChunkIDFragmentedChunkCompressed)
+	verifyBlockContent(t, compressedChunk, 1, []byte{0x41, 0x41, 0x41, 0x41})
+}
+
+func TestSalvageReturnsErrorForUnreadableDirectory(t *testing.T) {
+	data := []byte{0x00}
+
+	_, err := Salvage(bytes.NewReader(data), int64(len(data)), serial.NewByteStore())
+
+	assert.Equal(t, errDirectoryUnreadable, err)
+}
@@ -0,0 +1,315 @@
+package resfile
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/inkyblackness/res/chunk"
+	"github.com/inkyblackness/res/serial"
+)
+
+// UpdateTarget is the storage of a resource file that can be modified in place.
+type UpdateTarget interface {
+	io.ReaderAt
+	io.WriteSeeker
+}
+
+type syncer interface {
+	Sync() error
+}
+
+// Updater modifies an existing resource file in place.
+//
+// Put chunks are appended after the current end of the file. Committing the
+// changes writes a new directory after the appended chunks, and only then
+// modifies the header to refer to the new directory. Should an update be
+// interrupted, the file keeps its previous content.
+//
+// The space of replaced and deleted chunks, as well as any previous directory,
+// remains in the file and is described by directory entries that Reader will
+// not report. Compact creates a new file without this unused space.
+//
+// The Updater does not support concurrent modification.
+type Updater struct {
+	target UpdateTarget
+
+	firstChunkOffset uint32
+	layout           []chunkDirectoryEntry
+	appendOffset     uint32
+
+	staged      *Writer
+	stagedCount int
+	replaced    map[uint16]bool
+	current     map[uint16]int
+}
+
+var errTooManyDirectoryEntries = errors.New("too many directory entries")
+var errFileTruncated = errors.New("file is shorter than its directory describes")
+
+// NewUpdater returns an Updater for the resource file in given target.
+// An error is returned if the target does not contain a valid resource file.
+func NewUpdater(target UpdateTarget) (*Updater, error) {
+	if target == nil {
+		return nil, errTargetNil
+	}
+	dirOffset, err := readAndVerifyHeader(io.NewSectionReader(target, 0, chunkDirectoryFileOffsetPos+4))
+	if err != nil {
+		return nil, err
+	}
+	firstChunkOffset, directory, err := readDirectoryAt(dirOffset, target)
+	if err != nil {
+		return nil, err
+	}
+	fileSize, err := target.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	if fileSize > math.MaxUint32 {
+		return nil, fmt.Errorf("file too large: %v bytes", fileSize)
+	}
+
+	updater := &Updater{
+		target:           target,
+		firstChunkOffset: firstChunkOffset,
+		layout:           directory,
+		appendOffset:     alignedOffset(uint32(fileSize))}
+	if updater.dataEndOffset() > updater.appendOffset {
+		return nil, errFileTruncated
+	}
+	updater.resetStaging()
+
+	return updater, nil
+}
+
+// Put stages the given chunk for the given identifier. The chunk data is
+// written immediately after the current end of the file, yet it becomes part
+// of the resource file only with the next call to Commit.
+// Should writing the chunk fail, all staged modifications are discarded;
+// The file keeps the content of the last commit.
+func (updater *Updater) Put(id chunk.Identifier, data *chunk.Chunk) error {
+	if id.Value() == unusedChunkID {
+		return fmt.Errorf("chunk ID %v is reserved", id)
+	}
+	if updater.staged == nil {
+		if _, err := updater.target.Seek(int64(updater.appendOffset), io.SeekStart); err != nil {
+			updater.resetStaging()
+			return err
+		}
+		updater.staged = newAppendingWriter(updater.target)
+	}
+	err := writeChunk(updater.staged, id, data)
+	if err != nil {
+		updater.resetStaging()
+		return err
+	}
+	updater.replaced[id.Value()] = true
+	updater.current[id.Value()] = updater.stagedCount
+	updater.stagedCount++
+	return nil
+}
+
+// Del stages the removal of the chunk with given identifier.
+// The chunk is removed from the resource file with the next call to Commit.
+func (updater *Updater) Del(id chunk.Identifier) {
+	updater.replaced[id.Value()] = true
+	delete(updater.current, id.Value())
+}
+
+// Commit writes a new directory that includes all staged modifications and
+// lets the header of the file refer to it. Should the target support a
+// Sync() method, it is called before and after the header is modified.
+// The staged modifications are discarded in any case; Should committing them
+// fail, the file keeps the content of the last commit.
+func (updater *Updater) Commit() error {
+	defer updater.resetStaging()
+	var stagedDirectory []*chunkDirectoryEntry
+	if updater.staged != nil {
+		var err error
+		stagedDirectory, err = updater.staged.finishChunks()
+		if err != nil {
+			return err
+		}
+	}
+
+	layout := updater.committedLayout()
+	layout = appendUnusedSpace(layout, updater.appendOffset-updater.dataEndOffset())
+	for index, entry := range stagedDirectory {
+		if currentIndex, isCurrent := updater.current[entry.ID]; isCurrent && (currentIndex == index) {
+			layout = append(layout, *entry)
+		} else {
+			layout = appendUnusedSpace(layout, alignedOffset(entry.packedLength()))
+		}
+	}
+	layout = trimUnusedSpace(layout)
+	if len(layout) > math.MaxUint16 {
+		return errTooManyDirectoryEntries
+	}
+
+	directoryOffset := updater.appendOffset
+	if updater.staged != nil {
+		directoryOffset = alignedOffset(updater.appendOffset + updater.stagedEndOffset(stagedDirectory))
+	}
+	directoryEnd, err := updater.writeDirectory(directoryOffset, layout)
+	if err != nil {
+		return err
+	}
+	err = updater.writeDirectoryOffset(directoryOffset)
+	if err != nil {
+		return err
+	}
+
+	updater.layout = layout
+	updater.appendOffset = alignedOffset(directoryEnd)
+	return nil
+}
+
+// Compact writes a copy of the resource file, as of the last commit, to the
+// given target. The copy contains no unused space. Chunk data is copied as is,
+// without decompressing it.
+func (updater *Updater) Compact(target io.WriteSeeker) error {
+	writer, err := NewWriter(target)
+	if err != nil {
+		return err
+	}
+	startOffset := updater.firstChunkOffset
+	for _, entry := range updater.layout {
+		if entry.ID != unusedChunkID {
+			source := io.NewSectionReader(updater.target, int64(startOffset), int64(entry.packedLength()))
+			err = writer.copyRawChunk(entry, source, nil)
+			if err != nil {
+				return fmt.Errorf("failed to copy chunk %v: %v", chunk.ID(entry.ID), err)
+			}
+		}
+		startOffset = alignedOffset(startOffset + entry.packedLength())
+	}
+	return writer.Finish()
+}
+
+// UnusedSize returns the amount of bytes in the file that are not used by
+// chunks or the current directory.
+func (updater *Updater) UnusedSize() (size uint32) {
+	for _, entry := range updater.layout {
+		if entry.ID == unusedChunkID {
+			size += entry.packedLength()
+		}
+	}
+	trailingSize := updater.appendOffset - updater.dataEndOffset()
+	if directorySize := alignedOffset(updater.directorySize(len(updater.layout))); trailingSize > directorySize {
+		size += trailingSize - directorySize
+	}
+	return size
+}
+
+func (updater *Updater) resetStaging() {
+	updater.staged = nil
+	updater.stagedCount = 0
+	updater.replaced = make(map[uint16]bool)
+	updater.current = make(map[uint16]int)
+}
+
+func (updater *Updater) dataEndOffset() uint32 {
+	endOffset := updater.firstChunkOffset
+	for _, entry := range updater.layout {
+		endOffset = alignedOffset(endOffset + entry.packedLength())
+	}
+	return endOffset
+}
+
+func (updater *Updater) stagedEndOffset(directory []*chunkDirectoryEntry) uint32 {
+	endOffset := uint32(0)
+	for _, entry := range directory {
+		endOffset = alignedOffset(endOffset + entry.packedLength())
+	}
+	return endOffset
+}
+
+func (updater *Updater) directorySize(entryCount int) uint32 {
+	return uint32(2 + 4 + entryCount*10)
+}
+
+// committedLayout returns the current layout with all replaced entries
+// turned into unused space.
+func (updater *Updater) committedLayout() []chunkDirectoryEntry {
+	var layout []chunkDirectoryEntry
+	startOffset := updater.firstChunkOffset
+	for _, entry := range updater.layout {
+		endOffset := alignedOffset(startOffset + entry.packedLength())
+		if (entry.ID == unusedChunkID) || updater.replaced[entry.ID] {
+			layout = appendUnusedSpace(layout, endOffset-startOffset)
+		} else {
+			layout = append(layout, entry)
+		}
+		startOffset = endOffset
+	}
+	return layout
+}
+
+func (updater *Updater) writeDirectory(offset uint32, layout []chunkDirectoryEntry) (endOffset uint32, err error) {
+	_, err = updater.target.Seek(int64(offset), io.SeekStart)
+	if err != nil {
+		return 0, err
+	}
+	encoder := serial.NewEncoder(updater.target)
+	encoder.Code(uint16(len(layout)))
+	encoder.Code(updater.firstChunkOffset)
+	for index := range layout {
+		encoder.Code(&layout[index])
+	}
+	return offset + updater.directorySize(len(layout)), encoder.FirstError()
+}
+
+func (updater *Updater) writeDirectoryOffset(offset uint32) error {
+	err := updater.sync()
+	if err != nil {
+		return err
+	}
+	_, err = updater.target.Seek(chunkDirectoryFileOffsetPos, io.SeekStart)
+	if err != nil {
+		return err
+	}
+	encoder := serial.NewEncoder(updater.target)
+	encoder.Code(offset)
+	if encoder.FirstError() != nil {
+		return encoder.FirstError()
+	}
+	return updater.sync()
+}
+
+func (updater *Updater) sync() error {
+	if syncingTarget, canSync := updater.target.(syncer); canSync {
+		return syncingTarget.Sync()
+	}
+	return nil
+}
+
+// appendUnusedSpace extends the layout by the given amount of unused space.
+// The size must be a multiple of the boundary size.
+func appendUnusedSpace(layout []chunkDirectoryEntry, size uint32) []chunkDirectoryEntry {
+	maxUnusedLength := maxChunkLength - (maxChunkLength % boundarySize)
+	for size > 0 {
+		lastIndex := len(layout) - 1
+		if (lastIndex >= 0) && (layout[lastIndex].ID == unusedChunkID) && (layout[lastIndex].packedLength() < maxUnusedLength) {
+			last := &layout[lastIndex]
+			extension := maxUnusedLength - last.packedLength()
+			if extension > size {
+				extension = size
+			}
+			last.setPackedLength(last.packedLength() + extension)
+			last.setUnpackedLength(last.packedLength())
+			size -= extension
+		} else {
+			layout = append(layout, chunkDirectoryEntry{ID: unusedChunkID})
+		}
+	}
+	return layout
+}
+
+// trimUnusedSpace removes all unused space entries from the end of the layout.
+func trimUnusedSpace(layout []chunkDirectoryEntry) []chunkDirectoryEntry {
+	for (len(layout) > 0) && (layout[len(layout)-1].ID == unusedChunkID) {
+		layout = layout[:len(layout)-1]
+	}
+	return layout
+}
@@ -0,0 +1,181 @@
+package resfile
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/inkyblackness/res/chunk"
+	"github.com/inkyblackness/res/serial"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type updatableStore struct {
+	*serial.ByteStore
+}
+
+func (store updatableStore) ReadAt(p []byte, off int64) (int, error) {
+	return bytes.NewReader(store.Data()).ReadAt(p, off)
+}
+
+func exampleUpdatableStore() updatableStore {
+	return updatableStore{serial.NewByteStoreFromData(exampleResourceFile(), func([]byte) {})}
+}
+
+func aSingleBlockChunk(data []byte) *chunk.Chunk {
+	return &chunk.Chunk{ContentType: chunk.Text, BlockProvider: chunk.MemoryBlockProvider([][]byte{data})}
+}
+
+func TestNewUpdaterReturnsErrorForNilTarget(t *testing.T) {
+	updater, err := NewUpdater(nil)
+
+	assert.Nil(t, updater, "updater should be nil")
+	assert.Equal(t, errTargetNil, err)
+}
+
+func TestNewUpdaterReturnsErrorForInvalidFile(t *testing.T) {
+	_, err := NewUpdater(updatableStore{serial.NewByteStoreFromData([]byte{0x01, 0x02}, func([]byte) {})})
+
+	assert.NotNil(t, err, "error expected")
+}
+
+func TestUpdaterCommitWithoutChangesKeepsContent(t *testing.T) {
+	store := exampleUpdatableStore()
+	updater, _ := NewUpdater(store)
+
+	err := updater.Commit()
+	require.Nil(t, err, "no error expected")
+
+	reader, err := ReaderFrom(bytes.NewReader(store.Data()))
+	require.Nil(t, err, "no error expected")
+	assert.Equal(t, []chunk.Identifier{exampleChunkIDSingleBlockChunk, exampleChunkIDSingleBlockChunkCompressed,
+		exampleChunkIDFragmentedChunk, exampleChunkIDFragmentedChunkCompressed}, reader.IDs())
+	compressedChunk, _ := reader.Chunk(exampleChunkIDFragmentedChunkCompressed)
+	verifyBlockContent(t, compressedChunk, 1, []byte{0x41, 0x41, 0x41, 0x41})
+}
+
+func TestUpdaterPutReplacesChunksWithoutModifyingExistingData(t *testing.T) {
+	store := exampleUpdatableStore()
+	original := exampleResourceFile()
+	updater, _ := NewUpdater(store)
+
+	err := updater.Put(exampleChunkIDSingleBlockChunkCompressed, aSingleBlockChunk([]byte{0xA0, 0xA1}))
+	require.Nil(t, err, "no error expected putting")
+	err = updater.Commit()
+	require.Nil(t, err, "no error expected committing")
+
+	data := store.Data()
+	assert.Equal(t, original[:chunkDirectoryFileOffsetPos], data[:chunkDirectoryFileOffsetPos])
+	assert.Equal(t, original[chunkDirectoryFileOffsetPos+4:], data[chunkDirectoryFileOffsetPos+4:len(original)])
+	reader, _ := ReaderFrom(bytes.NewReader(data))
+	assert.Equal(t, []chunk.Identifier{exampleChunkIDSingleBlockChunk, exampleChunkIDFragmentedChunk,
+		exampleChunkIDFragmentedChunkCompressed, exampleChunkIDSingleBlockChunkCompressed}, reader.IDs())
+	replacedChunk, _ := reader.Chunk(exampleChunkIDSingleBlockChunkCompressed)
+	verifyBlockContent(t, replacedChunk, 0, []byte{0xA0, 0xA1})
+	fragmentedChunk, _ := reader.Chunk(exampleChunkIDFragmentedChunk)
+	verifyBlockContent(t, fragmentedChunk, 1, []byte{0x31, 0x31, 0x31})
+}
+
+func TestUpdaterDelRemovesChunks(t *testing.T) {
+	store := exampleUpdatableStore()
+	updater, _ := NewUpdater(store)
+
+	updater.Del(exampleChunkIDFragmentedChunk)
+	err := updater.Commit()
+	require.Nil(t, err, "no error expected committing")
+
+	reader, _ := ReaderFrom(bytes.NewReader(store.Data()))
+	assert.Equal(t, []chunk.Identifier{exampleChunkIDSingleBlockChunk, exampleChunkIDSingleBlockChunkCompressed,
+		exampleChunkIDFragmentedChunkCompressed}, reader.IDs())
+	compressedChunk, _ := reader.Chunk(exampleChunkIDFragmentedChunkCompressed)
+	verifyBlockContent(t, compressedChunk, 2, []byte{0x42})
+}
+
+func TestUpdaterKeepsOnlyLastPutChunk(t *testing.T) {
+	store := exampleUpdatableStore()
+	updater, _ := NewUpdater(store)
+
+	updater.Put(chunk.ID(0x0100), aSingleBlockChunk([]byte{0x01}))       // nolint: errcheck
+	updater.Put(chunk.ID(0x0200), aSingleBlockChunk([]byte{0x02}))       // nolint: errcheck
+	updater.Put(chunk.ID(0x0100), aSingleBlockChunk([]byte{0x03, 0x03})) // nolint: errcheck
+	updater.Del(chunk.ID(0x0200))
+	err := updater.Commit()
+	require.Nil(t, err, "no error expected committing")
+
+	reader, _ := ReaderFrom(bytes.NewReader(store.Data()))
+	assert.Equal(t, []chunk.Identifier{exampleChunkIDSingleBlockChunk, exampleChunkIDSingleBlockChunkCompressed,
+		exampleChunkIDFragmentedChunk, exampleChunkIDFragmentedChunkCompressed, chunk.ID(0x0100)}, reader.IDs())
+	newChunk, _ := reader.Chunk(chunk.ID(0x0100))
+	verifyBlockContent(t, newChunk, 0, []byte{0x03, 0x03})
+}
+
+func TestUpdaterUncommittedChangesDoNotAffectFile(t *testing.T) {
+	store := exampleUpdatableStore()
+	updater, _ := NewUpdater(store)
+
+	updater.Put(exampleChunkIDSingleBlockChunk, aSingleBlockChunk([]byte{0x01})) // nolint: errcheck
+	updater.Del(exampleChunkIDFragmentedChunk)
+
+	reader, _ := ReaderFrom(bytes.NewReader(store.Data()))
+	assert.Equal(t, []chunk.Identifier{exampleChunkIDSingleBlockChunk, exampleChunkIDSingleBlockChunkCompressed,
+		exampleChunkIDFragmentedChunk, exampleChunkIDFragmentedChunkCompressed}, reader.IDs())
+	originalChunk, _ := reader.Chunk(exampleChunkIDSingleBlockChunk)
+	verifyBlockContent(t, originalChunk, 0, []byte{0x01, 0x01, 0x01})
+}
+
+func TestUpdaterSupportsRepeatedCommits(t *testing.T) {
+	store := exampleUpdatableStore()
+	updater, _ := NewUpdater(store)
+
+	for i := 0; i < 3; i++ {
+		updater.Put(exampleChunkIDSingleBlockChunk, aSingleBlockChunk([]byte{byte(i)})) // nolint: errcheck
+		err := updater.Commit()
+		require.Nil(t, err, "no error expected committing")
+	}
+
+	reader, _ := ReaderFrom(bytes.NewReader(store.Data()))
+	updatedChunk, _ := reader.Chunk(exampleChunkIDSingleBlockChunk)
+	verifyBlockContent(t, updatedChunk, 0, []byte{0x02})
+	assert.True(t, updater.UnusedSize() > 0, "unused space expected")
+}
+
+func TestUpdaterCanBeUsedAgainAfterFailedPut(t *testing.T) {
+	store := exampleUpdatableStore()
+	updater, _ := NewUpdater(store)
+
+	updater.Del(exampleChunkIDFragmentedChunk)
+	err := updater.Put(chunk.ID(0x0100), &chunk.Chunk{ContentType: chunk.Text, BlockProvider: failingBlockProvider{}})
+	require.NotNil(t, err, "error expected putting")
+	err = updater.Put(chunk.ID(0x0200), aSingleBlockChunk([]byte{0x02}))
+	require.Nil(t, err, "no error expected putting again")
+	err = updater.Commit()
+	require.Nil(t, err, "no error expected committing")
+
+	reader, _ := ReaderFrom(bytes.NewReader(store.Data()))
+	assert.Equal(t, []chunk.Identifier{exampleChunkIDSingleBlockChunk, exampleChunkIDSingleBlockChunkCompressed,
+		exampleChunkIDFragmentedChunk, exampleChunkIDFragmentedChunkCompressed, chunk.ID(0x0200)}, reader.IDs())
+	newChunk, _ := reader.Chunk(chunk.ID(0x0200))
+	verifyBlockContent(t, newChunk, 0, []byte{0x02})
+}
+
+func TestUpdaterCompactCreatesFileWithoutUnusedSpace(t *testing.T) {
+	store := exampleUpdatableStore()
+	updater, _ := NewUpdater(store)
+	updater.Put(exampleChunkIDSingleBlockChunk, aSingleBlockChunk([]byte{0x01, 0x01, 0x01})) // nolint: errcheck
+	updater.Commit()                                                                         // nolint: errcheck
+	updater.Put(exampleChunkIDSingleBlockChunk, aSingleBlockChunk([]byte{0x01, 0x01, 0x01})) // nolint: errcheck
+	updater.Commit()                                                                         // nolint: errcheck
+
+	compacted := serial.NewByteStore()
+	err := updater.Compact(compacted)
+	require.Nil(t, err, "no error expected compacting")
+
+	reference := serial.NewByteStore()
+	referenceReader, _ := ReaderFrom(bytes.NewReader(store.Data()))
+	Write(reference, referenceReader) // nolint: errcheck
+	assert.Equal(t, reference.Data(), compacted.Data())
+
+	compactedUpdater, _ := NewUpdater(updatableStore{compacted})
+	assert.Equal(t, uint32(0), compactedUpdater.UnusedSize())
+}
@@ -0,0 +1,262 @@
+package resfile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/inkyblackness/res/chunk"
+	"github.com/inkyblackness/res/chunk/resfile/compression"
+	"github.com/inkyblackness/res/serial"
+)
+
+// ValidationReport is the result of validating a resource file.
+type ValidationReport struct {
+	// Issues lists all problems in the order they were found.
+	Issues []Issue
+
+	directoryReadable bool
+	firstChunkOffset  uint32
+	directory         []chunkDirectoryEntry
+	unreadable        map[uint16]bool
+}
+
+// Valid returns true if no issues were found.
+func (report *ValidationReport) Valid() bool {
+	return len(report.Issues) == 0
+}
+
+// ChunkIssues returns the issues of the identified chunk.
+func (report *ValidationReport) ChunkIssues(id chunk.Identifier) []Issue {
+	var issues []Issue
+	for _, issue := range report.Issues {
+		if (issue.ChunkID != nil) && (issue.ChunkID.Value() == id.Value()) {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// Readable returns true if the identified chunk is listed in the directory
+// and has no issues that prevent reading it.
+func (report *ValidationReport) Readable(id chunk.Identifier) bool {
+	if !report.directoryReadable || (id.Value() == unusedChunkID) {
+		return false
+	}
+	for _, entry := range report.directory {
+		if entry.ID == id.Value() {
+			return !report.unreadable[id.Value()]
+		}
+	}
+	return false
+}
+
+type validator struct {
+	source io.ReaderAt
+	size   int64
+	report *ValidationReport
+
+	directoryOffset int64
+	directoryEnd    int64
+}
+
+// Validate checks the resource file in given source of given size and reports
+// any found problems. It checks the header, the bounds of the directory and
+// the chunks, the padding between chunks, the block lists of fragmented chunks,
+// the compressed data, as well as the lengths declared in the directory.
+func Validate(source io.ReaderAt, size int64) *ValidationReport {
+	v := &validator{
+		source: source,
+		size:   size,
+		report: &ValidationReport{unreadable: make(map[uint16]bool)}}
+
+	if v.checkHeaderAndDirectory() {
+		v.checkChunks()
+	}
+
+	return v.report
+}
+
+func (v *validator) addIssue(kind IssueKind, entry *chunkDirectoryEntry, blockIndex int, offset int64,
+	format string, args ...interface{}) {
+	issue := Issue{Kind: kind, BlockIndex: blockIndex, Offset: offset, Description: fmt.Sprintf(format, args...)}
+	if entry != nil {
+		issue.ChunkID = chunk.ID(entry.ID)
+		if kind.PreventsReading() {
+			v.report.unreadable[entry.ID] = true
+		}
+	}
+	v.report.Issues = append(v.report.Issues, issue)
+}
+
+func (v *validator) checkHeaderAndDirectory() bool {
+	headerSize := int64(chunkDirectoryFileOffsetPos + 4)
+	if v.size < headerSize {
+		v.addIssue(HeaderMismatch, nil, -1, 0, "file is too short for a header: %v bytes", v.size)
+		return false
+	}
+	dirOffset, headerErr := readAndVerifyHeader(io.NewSectionReader(v.source, 0, headerSize))
+	if headerErr == errFormatMismatch {
+		v.addIssue(HeaderMismatch, nil, -1, 0, "header comment does not start with %q", headerString)
+	} else if headerErr != nil {
+		v.addIssue(HeaderMismatch, nil, -1, 0, "failed to read header: %v", headerErr)
+		return false
+	}
+
+	v.directoryOffset = int64(dirOffset)
+	var header chunkDirectoryHeader
+	headerEnd := v.directoryOffset + int64(binary.Size(&header))
+	if (v.directoryOffset < headerSize) || (headerEnd > v.size) {
+		v.addIssue(DirectoryOutOfBounds, nil, -1, v.directoryOffset,
+			"directory header is not within file of size %v", v.size)
+		return false
+	}
+	serial.NewDecoder(io.NewSectionReader(v.source, v.directoryOffset, headerEnd-v.directoryOffset)).Code(&header)
+	v.directoryEnd = headerEnd + int64(header.ChunkCount)*int64(binary.Size(&chunkDirectoryEntry{}))
+	if v.directoryEnd > v.size {
+		v.addIssue(DirectoryOutOfBounds, nil, -1, v.directoryOffset,
+			"directory with %v entries ends at 0x%08X, beyond file of size %v", header.ChunkCount, v.directoryEnd, v.size)
+		return false
+	}
+	firstChunkOffset, directory, dirErr := readDirectoryAt(dirOffset, v.source)
+	if dirErr != nil {
+		v.addIssue(DirectoryOutOfBounds, nil, -1, v.directoryOffset, "failed to read directory: %v", dirErr)
+		return false
+	}
+	v.report.directoryReadable = true
+	v.report.firstChunkOffset = firstChunkOffset
+	v.report.directory = directory
+	if int64(firstChunkOffset) < headerSize {
+		v.addIssue(FirstChunkOffsetInvalid, nil, -1, v.directoryOffset,
+			"first chunk at 0x%08X overlaps header", firstChunkOffset)
+	}
+	return true
+}
+
+func (v *validator) checkChunks() {
+	startOffset := v.report.firstChunkOffset
+	for index := range v.report.directory {
+		entry := &v.report.directory[index]
+		endOffset := startOffset + entry.packedLength()
+		alignedEndOffset := alignedOffset(endOffset)
+
+		if v.checkChunkBounds(entry, int64(startOffset), int64(endOffset)) {
+			v.checkPadding(entry, int64(endOffset), int64(alignedEndOffset))
+			if entry.ID != unusedChunkID {
+				v.checkChunkContent(entry, int64(startOffset))
+			}
+		}
+		startOffset = alignedEndOffset
+	}
+}
+
+func (v *validator) checkChunkBounds(entry *chunkDirectoryEntry, start, end int64) bool {
+	if (start < chunkDirectoryFileOffsetPos+4) || (end > v.size) {
+		v.addIssue(ChunkOutOfBounds, entry, -1, start, "chunk data 0x%08X-0x%08X is not within file of size %v",
+			start, end, v.size)
+		return false
+	}
+	if (start < v.directoryEnd) && (end > v.directoryOffset) {
+		v.addIssue(ChunkOutOfBounds, entry, -1, start, "chunk data 0x%08X-0x%08X overlaps directory at 0x%08X",
+			start, end, v.directoryOffset)
+		return false
+	}
+	return true
+}
+
+func (v *validator) checkPadding(entry *chunkDirectoryEntry, start, end int64) {
+	if end > v.size {
+		end = v.size
+	}
+	if end <= start {
+		return
+	}
+	padding := make([]byte, end-start)
+	v.source.ReadAt(padding, start) // nolint: errcheck
+	if !bytes.Equal(padding, make([]byte, len(padding))) {
+		v.addIssue(PaddingNotZero, entry, -1, start, "padding contains data: % X", padding)
+	}
+}
+
+func (v *validator) checkChunkContent(entry *chunkDirectoryEntry, start int64) {
+	chunkType := entry.chunkType()
+	compressed := (chunkType & chunkTypeFlagCompressed) != 0
+	fragmented := (chunkType & chunkTypeFlagFragmented) != 0
+	chunkData := io.NewSectionReader(v.source, start, int64(entry.packedLength()))
+
+	if fragmented {
+		v.checkFragmentedChunk(entry, start, chunkData, compressed)
+	} else if compressed {
+		data, ok := v.decompress(entry, start, chunkData)
+		if ok && (uint32(len(data)) != entry.unpackedLength()) {
+			v.addIssue(UnpackedLengthMismatch, entry, -1, start, "declared unpacked length %v, actual %v",
+				entry.unpackedLength(), len(data))
+		}
+	} else if entry.unpackedLength() != entry.packedLength() {
+		v.addIssue(UnpackedLengthMismatch, entry, -1, start, "declared unpacked length %v, actual %v",
+			entry.unpackedLength(), entry.packedLength())
+	}
+}
+
+func (v *validator) checkFragmentedChunk(entry *chunkDirectoryEntry, start int64, chunkData *io.SectionReader, compressed bool) {
+	decoder := serial.NewDecoder(chunkData)
+	var blockCount uint16
+	decoder.Code(&blockCount)
+	offsets := make([]uint32, int(blockCount)+1)
+	decoder.Code(offsets)
+	if decoder.FirstError() != nil {
+		v.addIssue(BlockListInvalid, entry, -1, start, "block list of %v blocks exceeds chunk length %v",
+			blockCount, chunkData.Size())
+		return
+	}
+	listSize := uint32(2 + 4*len(offsets))
+	if offsets[0] < listSize {
+		v.addIssue(BlockListInvalid, entry, 0, start, "first block at %v overlaps block list of size %v",
+			offsets[0], listSize)
+		return
+	}
+	monotonic := true
+	for blockIndex := 0; blockIndex < int(blockCount); blockIndex++ {
+		if offsets[blockIndex+1] < offsets[blockIndex] {
+			v.addIssue(BlockListNotMonotonic, entry, blockIndex, start, "block ends at %v before it starts at %v",
+				offsets[blockIndex+1], offsets[blockIndex])
+			monotonic = false
+		}
+	}
+	if !monotonic {
+		return
+	}
+
+	dataEnd := offsets[blockCount]
+	availableEnd := entry.packedLength()
+	if compressed {
+		compressedData := io.NewSectionReader(chunkData, int64(offsets[0]), chunkData.Size()-int64(offsets[0]))
+		data, ok := v.decompress(entry, start, compressedData)
+		if !ok {
+			return
+		}
+		availableEnd = offsets[0] + uint32(len(data))
+	} else if offsets[0] > availableEnd {
+		availableEnd = offsets[0]
+	}
+	if dataEnd > availableEnd {
+		v.addIssue(BlockListInvalid, entry, -1, start, "blocks end at %v, beyond available data of %v bytes",
+			dataEnd, availableEnd)
+	}
+	if availableEnd != entry.unpackedLength() {
+		v.addIssue(UnpackedLengthMismatch, entry, -1, start, "declared unpacked length %v, actual %v",
+			entry.unpackedLength(), availableEnd)
+	}
+}
+
+func (v *validator) decompress(entry *chunkDirectoryEntry, start int64, source io.Reader) (data []byte, ok bool) {
+	data, err := ioutil.ReadAll(compression.NewDecompressor(source))
+	if err == io.ErrUnexpectedEOF {
+		v.addIssue(CompressedStreamUnterminated, entry, -1, start, "compressed data ends without end-of-stream marker")
+	} else if err != nil {
+		v.addIssue(CompressedStreamInvalid, entry, -1, start, "failed to decompress: %v", err)
+	}
+	return data, err == nil
+}
@@ -0,0 +1,114 @@
+package resfile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/inkyblackness/res/chunk"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func validate(data []byte) *ValidationReport {
+	return Validate(bytes.NewReader(data), int64(len(data)))
+}
+
+func issueKinds(report *ValidationReport) []IssueKind {
+	var kinds []IssueKind
+	for _, issue := range report.Issues {
+		kinds = append(kinds, issue.Kind)
+	}
+	return kinds
+}
+
+func directoryEntryOffset(data []byte, index int) int {
+	return int(binary.LittleEndian.Uint32(data[chunkDirectoryFileOffsetPos:])) + 6 + index*10
+}
+
+func chunkStartOffset(data []byte, id chunk.Identifier) int {
+	reader, _ := ReaderFrom(bytes.NewReader(data))
+	return int(reader.index[id.Value()].startOffset)
+}
+
+func TestValidateReportsNoIssuesForValidFiles(t *testing.T) {
+	assert.True(t, validate(emptyResourceFile()).Valid(), "empty file should be valid")
+	assert.True(t, validate(exampleResourceFile()).Valid(), "example file should be valid")
+}
+
+func TestValidateReportsTooShortFile(t *testing.T) {
+	report := validate([]byte{0x01, 0x02})
+
+	assert.Equal(t, []IssueKind{HeaderMismatch}, issueKinds(report))
+	assert.False(t, report.Readable(exampleChunkIDSingleBlockChunk))
+}
+
+func TestValidateReportsHeaderMismatchButKeepsChunksReadable(t *testing.T) {
+	data := exampleResourceFile()
+	data[3] = 'X'
+	report := validate(data)
+
+	assert.Equal(t, []IssueKind{HeaderMismatch}, issueKinds(report))
+	assert.True(t, report.Readable(exampleChunkIDFragmentedChunkCompressed))
+}
+
+func TestValidateReportsDirectoryOutOfBounds(t *testing.T) {
+	data := exampleResourceFile()
+	binary.LittleEndian.PutUint32(data[chunkDirectoryFileOffsetPos:], uint32(len(data)))
+	report := validate(data)
+
+	assert.Equal(t, []IssueKind{DirectoryOutOfBounds}, issueKinds(report))
+}
+
+func TestValidateReportsChunkOutOfBounds(t *testing.T) {
+	data := exampleResourceFile()
+	data[directoryEntryOffset(data, 3)+8] = 0x7F
+	report := validate(data)
+
+	assert.Equal(t, []IssueKind{ChunkOutOfBounds}, issueKinds(report))
+	assert.Equal(t, exampleChunkIDFragmentedChunkCompressed.Value(), report.Issues[0].ChunkID.Value())
+	assert.False(t, report.Readable(exampleChunkIDFragmentedChunkCompressed))
+	assert.True(t, report.Readable(exampleChunkIDFragmentedChunk))
+}
+
+func TestValidateReportsPaddingWithData(t *testing.T) {
+	data := exampleResourceFile()
+	data[chunkStartOffset(data, exampleChunkIDSingleBlockChunk)+3] = 0xFF
+	report := validate(data)
+
+	assert.Equal(t, []IssueKind{PaddingNotZero}, issueKinds(report))
+	assert.True(t, report.Readable(exampleChunkIDSingleBlockChunk))
+}
+
+func TestValidateReportsUnpackedLengthMismatch(t *testing.T) {
+	data := exampleResourceFile()
+	data[directoryEntryOffset(data, 1)+2] = 0x05
+	report := validate(data)
+
+	assert.Equal(t, []IssueKind{UnpackedLengthMismatch}, issueKinds(report))
+	assert.Equal(t, 1, len(report.ChunkIssues(exampleChunkIDSingleBlockChunkCompressed)))
+}
+
+func TestValidateReportsBlockListNotMonotonic(t *testing.T) {
+	data := exampleResourceFile()
+	data[chunkStartOffset(data, exampleChunkIDFragmentedChunk)+6] = 0x00
+	report := validate(data)
+
+	assert.Equal(t, []IssueKind{BlockListNotMonotonic}, issueKinds(report))
+	assert.Equal(t, 0, report.Issues[0].BlockIndex)
+	assert.False(t, report.Readable(exampleChunkIDFragmentedChunk))
+}
+
+func TestValidateReportsUnterminatedCompressedStream(t *testing.T) {
+	data := exampleResourceFile()
+	data[directoryEntryOffset(data, 3)+6] -= 4
+	report := validate(data)
+
+	assert.Contains(t, issueKinds(report), CompressedStreamUnterminated)
+	assert.False(t, report.Readable(exampleChunkIDFragmentedChunkCompressed))
+}
+
+func TestIssueKindStringReturnsName(t *testing.T) {
+	assert.Equal(t, "PaddingNotZero", PaddingNotZero.String())
+	assert.Equal(t, "IssueKind(100)", IssueKind(100).String())
+}
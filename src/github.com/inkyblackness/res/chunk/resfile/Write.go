@@ -1,52 +1,207 @@
 package resfile
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"runtime"
 
 	"github.com/inkyblackness/res/chunk"
+	"github.com/inkyblackness/res/serial"
 )
 
 // Write serializes the chunks from given provider into the target.
 // It is a convenience function for using Writer.
+//
+// The chunks are encoded concurrently, which speeds up compressing them.
+// The provider, as well as the chunks, are accessed only from the calling goroutine.
 func Write(target io.WriteSeeker, source chunk.Provider) error {
-	writer, writerErr := NewWriter(target)
+	return write(target, source, false)
+}
+
+// WritePreserving serializes the chunks from given provider into the target,
+// like Write does. Chunks that were retrieved from a Reader and that are not
+// modified are copied in their original serialized form, including the padding
+// following them. The header comment is taken from the file of the first such chunk.
+//
+// Writing the chunks of a Reader this way reproduces the original file byte by
+// byte, as long as the chunks in the original file are without gaps.
+//
+// The provider and the chunks are accessed only from the calling goroutine.
+// The serialized form of copied chunks is read from the source of their Reader
+// while writing, which happens in a separate goroutine.
+func WritePreserving(target io.WriteSeeker, source chunk.Provider) error {
+	return write(target, source, true)
+}
+
+// encodedChunk is a chunk in serialized form, ready to be copied by a Writer.
+type encodedChunk struct {
+	entry   chunkDirectoryEntry
+	data    io.Reader
+	padding []byte
+	err     error
+}
+
+func write(target io.WriteSeeker, source chunk.Provider, preserving bool) error {
+	var headerComment []byte
+	if preserving {
+		headerComment = firstHeaderComment(source)
+	}
+	writer, writerErr := newWriterWithHeader(target, headerComment)
 	if writerErr != nil {
 		return fmt.Errorf("failed to create writer: %v", writerErr)
 	}
 
+	pending := make(chan chan encodedChunk, runtime.GOMAXPROCS(0))
+	aborted := make(chan struct{})
+	copyResult := make(chan error, 1)
+	go func() {
+		copyResult <- copyEncodedChunks(writer, pending, aborted)
+	}()
+	encodeErr := encodeChunks(source, preserving, pending, aborted)
+	close(pending)
+	copyErr := <-copyResult
+	if encodeErr != nil {
+		return encodeErr
+	}
+	if copyErr != nil {
+		return copyErr
+	}
+
+	finishErr := writer.Finish()
+	if finishErr != nil {
+		return fmt.Errorf("failed to finish writer: %v", finishErr)
+	}
+	return nil
+}
+
+// encodeChunks queues the chunks of the source, in order, for copying.
+// Chunks that need to be encoded are encoded concurrently.
+func encodeChunks(source chunk.Provider, preserving bool, pending chan<- chan encodedChunk, aborted <-chan struct{}) error {
 	for _, id := range source.IDs() {
 		entry, chunkErr := source.Chunk(id)
 		if chunkErr != nil {
 			return fmt.Errorf("failed to retrieve chunk %v: %v", id, chunkErr)
 		}
 
-		if entry.Fragmented {
-			chunkWriter, chunkWriterErr := writer.CreateFragmentedChunk(id, entry.ContentType, entry.Compressed)
-			if chunkWriterErr != nil {
-				return fmt.Errorf("failed to create chunk %v: %v", id, chunkWriterErr)
-			}
-			copyErr := copyBlocks(entry, func() io.Writer { return chunkWriter.CreateBlock() })
-			if copyErr != nil {
-				return fmt.Errorf("failed to copy chunk %v: %v", id, copyErr)
-			}
-		} else if entry.BlockCount() == 1 {
-			blockWriter, chunkWriterErr := writer.CreateChunk(id, entry.ContentType, entry.Compressed)
-			if chunkWriterErr != nil {
-				return fmt.Errorf("failed to create chunk %v, %v", id, chunkWriterErr)
+		result := make(chan encodedChunk, 1)
+		if raw := rawChunkOf(entry); preserving && (raw != nil) {
+			rawEntry := raw.entry
+			rawEntry.ID = id.Value()
+			result <- encodedChunk{entry: rawEntry, data: raw.data(), padding: raw.padding()}
+		} else {
+			buffered, bufferErr := bufferedChunk(entry)
+			if bufferErr != nil {
+				return fmt.Errorf("failed to copy chunk %v: %v", id, bufferErr)
 			}
-			copyErr := copyBlocks(entry, func() io.Writer { return blockWriter })
-			if copyErr != nil {
-				return fmt.Errorf("failed to copy chunk %v: %v", id, copyErr)
+			go func(id chunk.Identifier) {
+				result <- encodeChunk(id, buffered)
+			}(id)
+		}
+
+		select {
+		case pending <- result:
+		case <-aborted:
+			return nil
+		}
+	}
+	return nil
+}
+
+// copyEncodedChunks writes the pending chunks, in order, with given writer.
+// Should writing fail, aborted is closed and any further chunks are dropped.
+func copyEncodedChunks(writer *Writer, pending <-chan chan encodedChunk, aborted chan<- struct{}) (err error) {
+	for result := range pending {
+		encoded := <-result
+		if err != nil {
+			continue
+		}
+		err = encoded.err
+		if err == nil {
+			err = writer.copyRawChunk(encoded.entry, encoded.data, encoded.padding)
+			if err != nil {
+				err = fmt.Errorf("failed to copy chunk %v: %v", chunk.ID(encoded.entry.ID), err)
 			}
-		} else {
-			return fmt.Errorf("unfragmented chunk %v has wrong number of blocks", id)
+		}
+		if err != nil {
+			close(aborted)
 		}
 	}
+	return
+}
 
-	finishErr := writer.Finish()
-	if finishErr != nil {
-		return fmt.Errorf("failed to finish writer: %v", finishErr)
+// bufferedChunk returns a copy of given chunk that has all its blocks in memory.
+func bufferedChunk(source *chunk.Chunk) (*chunk.Chunk, error) {
+	blocks := make([][]byte, source.BlockCount())
+	for blockIndex := range blocks {
+		blockReader, blockErr := source.Block(blockIndex)
+		if blockErr != nil {
+			return nil, fmt.Errorf("failed to retrieve block %d: %v", blockIndex, blockErr)
+		}
+		data, readErr := ioutil.ReadAll(blockReader)
+		if readErr != nil {
+			return nil, fmt.Errorf("failed to copy data %d: %v", blockIndex, readErr)
+		}
+		blocks[blockIndex] = data
+	}
+	return &chunk.Chunk{
+		Fragmented:    source.Fragmented,
+		ContentType:   source.ContentType,
+		Compressed:    source.Compressed,
+		BlockProvider: chunk.MemoryBlockProvider(blocks)}, nil
+}
+
+// encodeChunk serializes given chunk on its own.
+func encodeChunk(id chunk.Identifier, source *chunk.Chunk) encodedChunk {
+	store := serial.NewByteStore()
+	writer := newAppendingWriter(store)
+	err := writeChunk(writer, id, source)
+	if err != nil {
+		return encodedChunk{err: err}
+	}
+	directory, err := writer.finishChunks()
+	if err != nil {
+		return encodedChunk{err: fmt.Errorf("failed to encode chunk %v: %v", id, err)}
+	}
+	entry := *directory[0]
+	return encodedChunk{entry: entry, data: bytes.NewReader(store.Data()[:entry.packedLength()])}
+}
+
+func firstHeaderComment(source chunk.Provider) []byte {
+	for _, id := range source.IDs() {
+		entry, chunkErr := source.Chunk(id)
+		if chunkErr != nil {
+			continue
+		}
+		if raw := rawChunkOf(entry); raw != nil {
+			return raw.headerComment()
+		}
+	}
+	return nil
+}
+
+func writeChunk(writer *Writer, id chunk.Identifier, entry *chunk.Chunk) error {
+	if entry.Fragmented {
+		chunkWriter, chunkWriterErr := writer.CreateFragmentedChunk(id, entry.ContentType, entry.Compressed)
+		if chunkWriterErr != nil {
+			return fmt.Errorf("failed to create chunk %v: %v", id, chunkWriterErr)
+		}
+		copyErr := copyBlocks(entry, func() io.Writer { return chunkWriter.CreateBlock() })
+		if copyErr != nil {
+			return fmt.Errorf("failed to copy chunk %v: %v", id, copyErr)
+		}
+	} else if entry.BlockCount() == 1 {
+		blockWriter, chunkWriterErr := writer.CreateChunk(id, entry.ContentType, entry.Compressed)
+		if chunkWriterErr != nil {
+			return fmt.Errorf("failed to create chunk %v, %v", id, chunkWriterErr)
+		}
+		copyErr := copyBlocks(entry, func() io.Writer { return blockWriter })
+		if copyErr != nil {
+			return fmt.Errorf("failed to copy chunk %v: %v", id, copyErr)
+		}
+	} else {
+		return fmt.Errorf("unfragmented chunk %v has wrong number of blocks", id)
 	}
 	return nil
 }
@@ -2,11 +2,14 @@ package resfile
 
 import (
 	"bytes"
+	"errors"
+	"io"
 	"testing"
 
 	"github.com/inkyblackness/res/chunk"
 	"github.com/inkyblackness/res/serial"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestWrite(t *testing.T) {
@@ -37,3 +40,97 @@ func TestWrite(t *testing.T) {
 
 	assert.Equal(t, []chunk.Identifier{chunk.ID(1), chunk.ID(3), chunk.ID(2), chunk.ID(4)}, reader.IDs())
 }
+
+type failingBlockProvider struct{}
+
+func (provider failingBlockProvider) BlockCount() int {
+	return 1
+}
+
+func (provider failingBlockProvider) Block(index int) (io.Reader, error) {
+	return nil, errors.New("block failure")
+}
+
+func TestWriteEncodesChunksLikeWriter(t *testing.T) {
+	provider := chunk.NewProviderBackedStore(chunk.NullProvider())
+	expected := serial.NewByteStore()
+	writer, _ := NewWriter(expected)
+	for index := 0; index < 20; index++ {
+		id := chunk.ID(0x08F0 + uint16(index))
+		data := bytes.Repeat([]byte{byte(index), 0x01, 0x02}, 100+index)
+		provider.Put(id, &chunk.Chunk{
+			Compressed:    true,
+			ContentType:   chunk.Geometry,
+			Fragmented:    (index % 2) == 0,
+			BlockProvider: chunk.MemoryBlockProvider([][]byte{data})})
+		if (index % 2) == 0 {
+			chunkWriter, _ := writer.CreateFragmentedChunk(id, chunk.Geometry, true)
+			chunkWriter.CreateBlock().Write(data)
+		} else {
+			blockWriter, _ := writer.CreateChunk(id, chunk.Geometry, true)
+			blockWriter.Write(data)
+		}
+	}
+	writer.Finish()
+	target := serial.NewByteStore()
+
+	err := Write(target, provider)
+	require.Nil(t, err, "no error expected writing")
+
+	assert.Equal(t, expected.Data(), target.Data())
+}
+
+func TestWriteReturnsErrorOfFailingChunk(t *testing.T) {
+	provider := chunk.NewProviderBackedStore(chunk.NullProvider())
+	provider.Put(chunk.ID(1), &chunk.Chunk{BlockProvider: chunk.MemoryBlockProvider([][]byte{{0x01}})})
+	provider.Put(chunk.ID(2), &chunk.Chunk{BlockProvider: failingBlockProvider{}})
+	provider.Put(chunk.ID(3), &chunk.Chunk{BlockProvider: chunk.MemoryBlockProvider([][]byte{{0x03}})})
+
+	err := Write(serial.NewByteStore(), provider)
+
+	assert.NotNil(t, err, "error expected")
+}
+
+func TestWritePreservingReproducesOriginalFile(t *testing.T) {
+	original := exampleResourceFile()
+	original[0x20] = 0xAB
+	original[chunkDirectoryFileOffsetPos-1] = 0xCD
+	original[0x83] = 0xEF
+	reader, _ := ReaderFrom(bytes.NewReader(original))
+	target := serial.NewByteStore()
+
+	err := WritePreserving(target, reader)
+	require.Nil(t, err, "no error expected writing")
+
+	assert.Equal(t, original, target.Data())
+}
+
+func TestWritePreservingEncodesModifiedChunks(t *testing.T) {
+	original := exampleResourceFile()
+	original[0x83] = 0xEF
+	reader, _ := ReaderFrom(bytes.NewReader(original))
+	store := chunk.NewProviderBackedStore(reader)
+	modified, _ := reader.Chunk(exampleChunkIDFragmentedChunkCompressed)
+	modified.SetBlock(1, []byte{0x51, 0x52})
+	store.Put(exampleChunkIDFragmentedChunkCompressed, modified)
+	store.Put(exampleChunkIDSingleBlockChunkCompressed, &chunk.Chunk{
+		ContentType:   chunk.ContentType(0x02),
+		BlockProvider: chunk.MemoryBlockProvider([][]byte{{0x61}})})
+	target := serial.NewByteStore()
+
+	err := WritePreserving(target, store)
+	require.Nil(t, err, "no error expected writing")
+
+	data := target.Data()
+	assert.Equal(t, original[:chunkDirectoryFileOffsetPos], data[:chunkDirectoryFileOffsetPos], "header should be copied")
+	assert.Equal(t, original[0x80:0x84], data[0x80:0x84], "first chunk should be copied")
+	result, _ := ReaderFrom(bytes.NewReader(data))
+	replacedChunk, _ := result.Chunk(exampleChunkIDSingleBlockChunkCompressed)
+	assert.False(t, replacedChunk.Compressed, "replaced chunk should not be compressed")
+	verifyBlockContent(t, replacedChunk, 0, []byte{0x61})
+	copiedChunk, _ := result.Chunk(exampleChunkIDFragmentedChunk)
+	verifyBlockContent(t, copiedChunk, 1, []byte{0x31, 0x31, 0x31})
+	modifiedChunk, _ := result.Chunk(exampleChunkIDFragmentedChunkCompressed)
+	verifyBlockContent(t, modifiedChunk, 0, []byte{0x40, 0x40})
+	verifyBlockContent(t, modifiedChunk, 1, []byte{0x51, 0x52})
+}
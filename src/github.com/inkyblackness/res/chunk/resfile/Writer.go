@@ -32,18 +32,32 @@ var errTargetNil = errors.New("target is nil")
 // an error if the writer did. In such a case, the returned writer instance
 // will produce invalid results and the state of the target is undefined.
 func NewWriter(target io.WriteSeeker) (*Writer, error) {
+	return newWriterWithHeader(target, nil)
+}
+
+// newWriterWithHeader returns a new Writer that writes the given header comment.
+// If the comment is not provided, the default comment is written.
+func newWriterWithHeader(target io.WriteSeeker, headerComment []byte) (*Writer, error) {
 	if target == nil {
 		return nil, errTargetNil
 	}
 
 	encoder := serial.NewPositioningEncoder(target)
 	writer := &Writer{encoder: encoder}
-	writer.writeHeader()
+	writer.writeHeader(headerComment)
 	writer.firstChunkOffset = writer.encoder.CurPos()
 
 	return writer, writer.encoder.FirstError()
 }
 
+// newAppendingWriter returns a Writer that only writes chunk data, starting
+// at the current position of the target. The position must be aligned to the
+// chunk boundary. Such a writer must not be finished with Finish(), but with
+// finishChunks().
+func newAppendingWriter(target io.WriteSeeker) *Writer {
+	return &Writer{encoder: serial.NewPositioningEncoder(target)}
+}
+
 var errWriterFinished = errors.New("writer is finished")
 
 // CreateChunk adds a new single-block chunk to the current resource file.
@@ -125,12 +139,49 @@ func (writer *Writer) Finish() (err error) {
 	return
 }
 
-func (writer *Writer) writeHeader() {
+// finishChunks completes the last chunk and returns the directory entries of
+// all written chunks. After calling this function, the writer becomes unusable.
+func (writer *Writer) finishChunks() (directory []*chunkDirectoryEntry, err error) {
+	if writer.encoder == nil {
+		return nil, errWriterFinished
+	}
+
+	writer.finishLastChunk()
+	err = writer.encoder.FirstError()
+	writer.encoder = nil
+
+	return writer.directory, err
+}
+
+// copyRawChunk adds a chunk with data that is already in serialized form.
+// The chunk is closed by creating another chunk, or by finishing the writer.
+// The given padding is used to align the end of the chunk, instead of zeroes.
+func (writer *Writer) copyRawChunk(entry chunkDirectoryEntry, source io.Reader, padding []byte) error {
+	if writer.encoder == nil {
+		return errWriterFinished
+	}
+
+	writer.finishLastChunk()
+	if writer.encoder.FirstError() != nil {
+		return writer.encoder.FirstError()
+	}
+
+	writer.addNewChunk(chunk.ID(entry.ID), chunk.ContentType(entry.contentType()), entry.chunkType(),
+		&rawChunkWriter{unpackedLength: entry.unpackedLength(), padding: padding})
+	_, err := io.Copy(writer.encoder, source)
+	return err
+}
+
+func (writer *Writer) writeHeader(comment []byte) {
 	header := make([]byte, chunkDirectoryFileOffsetPos)
-	for index, r := range headerString {
-		header[index] = byte(r)
+	if comment != nil {
+		copy(header, comment)
+	} else {
+		for index, r := range headerString {
+			header[index] = byte(r)
+		}
+		header[len(headerString)] = commentTerminator
 	}
-	header[len(headerString)] = commentTerminator
 	writer.encoder.Code(header)
 	writer.encoder.Code(uint32(math.MaxUint32))
 }
@@ -145,21 +196,26 @@ func (writer *Writer) addNewChunk(id chunk.Identifier, contentType chunk.Content
 }
 
 func (writer *Writer) finishLastChunk() {
+	var preferredPadding []byte
 	if writer.currentChunk != nil {
 		currentEntry := writer.directory[len(writer.directory)-1]
 		currentEntry.setUnpackedLength(writer.currentChunk.finish())
 		currentEntry.setPackedLength(writer.encoder.CurPos() - writer.currentChunkStartOffset)
+		if rawChunk, isRaw := writer.currentChunk.(*rawChunkWriter); isRaw {
+			preferredPadding = rawChunk.padding
+		}
 
 		writer.currentChunkStartOffset = 0
 		writer.currentChunk = nil
 	}
-	writer.alignToBoundary()
+	writer.alignToBoundary(preferredPadding)
 }
 
-func (writer *Writer) alignToBoundary() {
+func (writer *Writer) alignToBoundary(preferredPadding []byte) {
 	extraBytes := writer.encoder.CurPos() % boundarySize
 	if extraBytes > 0 {
 		padding := make([]byte, boundarySize-extraBytes)
+		copy(padding, preferredPadding)
 		writer.encoder.Code(padding)
 	}
 }
@@ -0,0 +1,27 @@
+package resfile
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/inkyblackness/res/chunk"
+	"github.com/inkyblackness/res/serial"
+)
+
+func BenchmarkWriteCompressedChunks(b *testing.B) {
+	provider := chunk.NewProviderBackedStore(chunk.NullProvider())
+	for index := 0; index < 32; index++ {
+		data := make([]byte, 64*1024)
+		for dataIndex := range data {
+			data[dataIndex] = byte(rand.Intn(16))
+		}
+		provider.Put(chunk.ID(0x1000+uint16(index)), &chunk.Chunk{
+			Compressed:    true,
+			ContentType:   chunk.Bitmap,
+			BlockProvider: chunk.MemoryBlockProvider([][]byte{data})})
+	}
+	b.ResetTimer()
+	for run := 0; run < b.N; run++ {
+		Write(serial.NewByteStore(), provider) // nolint: errcheck
+	}
+}
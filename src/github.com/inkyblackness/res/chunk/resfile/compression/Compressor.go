@@ -6,42 +6,43 @@ import (
 	"github.com/inkyblackness/res/serial"
 )
 
+// saturationLimit is the number of words written with a full dictionary,
+// after which the dictionary is reset.
+const saturationLimit = 1000
+
 type compressor struct {
 	coder  serial.Coder
 	writer *wordWriter
 
-	dictBuffer     dictEntryBuffer
-	overtime       int
-	dictionary     *dictEntry
+	dictionary     *dictionaryTable
 	dictionarySize int
-	curEntry       *dictEntry
+	overtime       int
+	// current is the key of the sequence matched so far, or reset if none.
+	current word
 }
 
 // NewCompressor creates a new compressor instance over a writer.
+// The compressor is not safe for concurrent use, though several compressors
+// can be used concurrently.
 func NewCompressor(target io.Writer) io.WriteCloser {
 	coder := serial.NewEncoder(target)
 	obj := &compressor{
-		coder:          coder,
-		writer:         newWordWriter(coder),
-		dictionary:     rootDictEntry(),
-		dictionarySize: 0,
-		overtime:       0}
-
-	obj.resetDictionary()
+		coder:      coder,
+		writer:     newWordWriter(coder),
+		dictionary: new(dictionaryTable),
+		current:    reset}
 
 	return obj
 }
 
 func (obj *compressor) resetDictionary() {
+	obj.dictionary.clear()
 	obj.dictionarySize = 0
-	for i := 0; i < 0x100; i++ {
-		obj.dictionary.Add(byte(i), word(i), obj.dictBuffer.entry(word(i)))
-	}
-	obj.curEntry = obj.dictionary
+	obj.current = reset
 }
 
 func (obj *compressor) Close() error {
-	obj.writer.write(obj.curEntry.key)
+	obj.writer.write(obj.current)
 	obj.writer.close()
 
 	return obj.coder.FirstError()
@@ -56,27 +57,29 @@ func (obj *compressor) Write(p []byte) (n int, err error) {
 }
 
 func (obj *compressor) addByte(value byte) {
-	nextEntry := obj.curEntry.next[int(value)]
-	if nextEntry != nil {
-		obj.curEntry = nextEntry
-	} else {
-		obj.writer.write(obj.curEntry.key)
-
-		key := word(int(literalLimit) + obj.dictionarySize)
-		if key < reset {
-			obj.curEntry.Add(value, key, obj.dictBuffer.entry(key))
-			obj.dictionarySize++
-		} else {
-			obj.onKeySaturation()
-		}
+	if obj.current == reset {
+		obj.current = word(value)
+		return
+	}
+	if next, known := obj.dictionary.lookup(obj.current, value); known {
+		obj.current = next
+		return
+	}
 
-		obj.curEntry = obj.dictionary.next[value]
+	obj.writer.write(obj.current)
+	key := word(int(literalLimit) + obj.dictionarySize)
+	if key < reset {
+		obj.dictionary.add(obj.current, value, key)
+		obj.dictionarySize++
+	} else {
+		obj.onKeySaturation()
 	}
+	obj.current = word(value)
 }
 
 func (obj *compressor) onKeySaturation() {
 	obj.overtime++
-	if obj.overtime > 1000 {
+	if obj.overtime > saturationLimit {
 		obj.writer.write(reset)
 		obj.resetDictionary()
 		obj.overtime = 0
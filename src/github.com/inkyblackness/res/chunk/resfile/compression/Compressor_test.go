@@ -2,7 +2,10 @@ package compression
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"fmt"
 	"io"
+	"math/rand"
 	"testing"
 
 	"github.com/inkyblackness/res/serial"
@@ -42,6 +45,12 @@ func (suite *CompressorSuite) TestWriteCompressesTest1() {
 	suite.thenWordsShouldBe(word(0x0000), word(0x0001), word(0x0000), word(0x0002), word(0x0101), word(0x0001))
 }
 
+func (suite *CompressorSuite) TestCloseWithoutDataWritesReset() {
+	suite.compressor.Close()
+
+	suite.thenWordsShouldBe(reset)
+}
+
 func (suite *CompressorSuite) thenWordsShouldBe(expected ...word) {
 	source := bytes.NewReader(suite.store.Data())
 	reader := newWordReader(serial.NewDecoder(source))
@@ -53,3 +62,36 @@ func (suite *CompressorSuite) thenWordsShouldBe(expected ...word) {
 
 	assert.Equal(suite.T(), expected, words)
 }
+
+// TestCompressorProducesReferenceStreams verifies that the compressor creates the
+// same streams as the original implementation did, which are known to be accepted
+// by the original engine. The larger inputs cause the dictionary to be reset.
+func TestCompressorProducesReferenceStreams(t *testing.T) {
+	references := []struct {
+		size      int
+		valueSpan int
+		hash      string
+	}{
+		{0, 256, "107a41436526270884bf9488c2d0173cc0671e057c17731021b794bf8d68aafe"},
+		{1, 256, "67b4140d79d2d90e9beb066c116e5196907b552216310327b0782ea888a39213"},
+		{1000, 4, "ba64830ed686f9b8bcafd0e9115bee2de005421e4675d4ebe29eee0cfca4db47"},
+		{1000, 256, "e8c338327d7227f35e47c1251d394d6be84a5266a2d079ef66be7ec0ac989a58"},
+		{200000, 4, "e3ab0c05c29d7e48097aefddf37bed5d5dcc4d87f540e7c0027bcac10a10c3c5"},
+		{200000, 256, "81e9ee9b384bfdc8dcd737078908b84916f44fd20e736c362361ec8932d5943f"},
+	}
+
+	for _, reference := range references {
+		random := rand.New(rand.NewSource(int64(reference.size)))
+		data := make([]byte, reference.size)
+		for index := range data {
+			data[index] = byte(random.Intn(reference.valueSpan))
+		}
+		store := serial.NewByteStore()
+		compressor := NewCompressor(store)
+		compressor.Write(data)
+		compressor.Close()
+
+		assert.Equal(t, reference.hash, fmt.Sprintf("%x", sha256.Sum256(store.Data())),
+			"Stream differs for size %v, span %v", reference.size, reference.valueSpan)
+	}
+}
@@ -1,11 +1,15 @@
 package compression
 
 import (
+	"errors"
 	"io"
 
 	"github.com/inkyblackness/res/serial"
 )
 
+// ErrInvalidWord is returned for a word that refers to a dictionary entry that does not exist.
+var ErrInvalidWord = errors.New("invalid word in compressed stream")
+
 type decompressor struct {
 	coder  serial.Coder
 	reader *wordReader
@@ -19,9 +23,13 @@ type decompressor struct {
 
 	scratch  []byte
 	leftover []byte
+	err      error
 }
 
 // NewDecompressor creates a new decompressor instance over a reader.
+// Malformed data is reported with ErrInvalidWord. Should the source end before the
+// end-of-stream marker, io.ErrUnexpectedEOF is returned; Only a properly terminated
+// stream ends with io.EOF.
 func NewDecompressor(source io.Reader) io.Reader {
 	coder := serial.NewDecoder(source)
 	obj := &decompressor{
@@ -48,7 +56,7 @@ func (obj *decompressor) resetDictionary() {
 func (obj *decompressor) Read(p []byte) (n int, err error) {
 	requested := len(p)
 
-	for n < requested && !obj.isEndOfStream && obj.coder.FirstError() == nil {
+	for n < requested && !obj.isEndOfStream && obj.err == nil && obj.coder.FirstError() == nil {
 		n += obj.takeFromLeftover(p[n:])
 		if n < requested {
 			obj.readNextWord()
@@ -56,7 +64,12 @@ func (obj *decompressor) Read(p []byte) (n int, err error) {
 		}
 	}
 	err = obj.coder.FirstError()
-	if err == nil && obj.isEndOfStream {
+	if err == nil {
+		err = obj.err
+	}
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	} else if err == nil && obj.isEndOfStream {
 		err = io.EOF
 	}
 
@@ -102,20 +115,28 @@ func (obj *decompressor) readNextWord() {
 				obj.addToDictionary(nextEntry.FirstByte())
 			}
 			obj.lastEntry = nextEntry
-		} else if nextWord >= literalLimit {
+		} else if (nextWord == obj.nextKey()) && (nextWord < reset) && (obj.lastEntry.depth > 0) {
 			nextValue := obj.lastEntry.FirstByte()
 			obj.addToDictionary(nextValue)
 			obj.lastEntry = obj.lastEntry.next[nextValue]
 		} else {
-			nextValue := byte(nextWord)
-			obj.addToDictionary(nextValue)
-			obj.lastEntry = obj.dictionary.next[nextValue]
+			obj.err = ErrInvalidWord
 		}
 	}
 }
 
+// nextKey returns the key the next dictionary entry gets.
+func (obj *decompressor) nextKey() word {
+	return word(int(literalLimit) + obj.dictionarySize)
+}
+
+// addToDictionary adds an entry for the last entry extended by given value.
+// Like the compressor does, a saturated dictionary is kept as it is.
 func (obj *decompressor) addToDictionary(value byte) {
-	key := word(int(literalLimit) + obj.dictionarySize)
+	key := obj.nextKey()
+	if key >= reset {
+		return
+	}
 	nextEntry := obj.lastEntry.Add(value, key, obj.dictBuffer.entry(key))
 	if int(key) >= len(obj.lookup) {
 		newLookup := make([]*dictEntry, len(obj.lookup)+1024)
@@ -3,6 +3,7 @@ package compression
 import (
 	"bytes"
 	"io"
+	"io/ioutil"
 	"math/rand"
 	"testing"
 	"time"
@@ -78,6 +79,36 @@ func (suite *DecompressorSuite) TestDecompressHandlesSelfReferencingWords() {
 	suite.verifyOutput([]byte{0x01, 0x02, 0x02, 0x02})
 }
 
+func (suite *DecompressorSuite) TestDecompressReportsMissingEndOfStream() {
+	suite.writeWords(0x0001, 0x0002, 0x0003)
+
+	output := suite.buffer(10)
+	decompressor := NewDecompressor(bytes.NewReader(suite.store.Data()[:5]))
+	_, err := decompressor.Read(output)
+
+	assert.Equal(suite.T(), io.ErrUnexpectedEOF, err)
+}
+
+func (suite *DecompressorSuite) TestDecompressReportsWordBeyondDictionary() {
+	suite.writeWords(0x0001, 0x0002, 0x0102, endOfStream)
+
+	suite.verifyError(ErrInvalidWord)
+}
+
+func (suite *DecompressorSuite) TestDecompressReportsSelfReferenceWithoutPreviousWord() {
+	suite.writeWords(0x0100, endOfStream)
+
+	suite.verifyError(ErrInvalidWord)
+}
+
+func (suite *DecompressorSuite) TestDecompressHandlesSaturatedDictionary() {
+	r := rand.New(rand.NewSource(0))
+	input := make([]byte, 0x20000)
+	r.Read(input)
+
+	suite.verify(input)
+}
+
 func (suite *DecompressorSuite) writeWords(values ...word) {
 	suite.store = serial.NewByteStore()
 	coder := serial.NewEncoder(suite.store)
@@ -108,6 +139,12 @@ func (suite *DecompressorSuite) verifyOutput(expected []byte) {
 	assert.Equal(suite.T(), expected, output)
 }
 
+func (suite *DecompressorSuite) verifyError(expected error) {
+	_, err := ioutil.ReadAll(NewDecompressor(bytes.NewReader(suite.store.Data())))
+
+	assert.Equal(suite.T(), expected, err)
+}
+
 func (suite *DecompressorSuite) buffer(byteCount int) []byte {
 	result := make([]byte, byteCount)
 	for i := range result {
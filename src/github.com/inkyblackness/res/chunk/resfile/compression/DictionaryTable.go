@@ -0,0 +1,56 @@
+package compression
+
+// dictionaryTableSizeBits governs the size of the hash table. The table has
+// about twice as many slots as the dictionary can hold entries, which keeps
+// the probe sequences short.
+const dictionaryTableSizeBits = 15
+
+const dictionaryTableSize = 1 << dictionaryTableSizeBits
+
+type dictionarySlot struct {
+	// sequence identifies the stored entry, see sequenceOf(). Zero marks an empty slot.
+	sequence uint32
+	key      word
+}
+
+// dictionaryTable maps a known byte sequence, extended by one byte, to the key
+// of the extended sequence. It is an open-addressing hash table with linear probing.
+type dictionaryTable struct {
+	slots [dictionaryTableSize]dictionarySlot
+}
+
+func sequenceOf(prefix word, value byte) uint32 {
+	return ((uint32(prefix) << 8) | uint32(value)) + 1
+}
+
+func slotIndexOf(sequence uint32) uint32 {
+	return (sequence * 2654435761) >> (32 - dictionaryTableSizeBits)
+}
+
+func (table *dictionaryTable) clear() {
+	table.slots = [dictionaryTableSize]dictionarySlot{}
+}
+
+// lookup returns the key of the sequence of prefix, extended by value.
+func (table *dictionaryTable) lookup(prefix word, value byte) (key word, found bool) {
+	sequence := sequenceOf(prefix, value)
+	for index := slotIndexOf(sequence); ; index = (index + 1) & (dictionaryTableSize - 1) {
+		slot := &table.slots[index]
+		if slot.sequence == sequence {
+			return slot.key, true
+		} else if slot.sequence == 0 {
+			return 0, false
+		}
+	}
+}
+
+// add stores the key for the sequence of prefix, extended by value.
+// The sequence must not be stored already.
+func (table *dictionaryTable) add(prefix word, value byte, key word) {
+	sequence := sequenceOf(prefix, value)
+	index := slotIndexOf(sequence)
+	for table.slots[index].sequence != 0 {
+		index = (index + 1) & (dictionaryTableSize - 1)
+	}
+	table.slots[index] = dictionarySlot{sequence: sequence, key: key}
+}
@@ -21,6 +21,14 @@ func rawData(size int) []byte {
 	return data
 }
 
+func repetitiveData(size int) []byte {
+	data := make([]byte, size)
+	for index := range data {
+		data[index] = byte(rand.Intn(8))
+	}
+	return data
+}
+
 func initProfiling(b *testing.B, nameSuffix string) func() {
 	flag.Parse()
 	if *cpuprofile != "" {
@@ -46,9 +54,13 @@ func BenchmarkRawDataStorage(b *testing.B) {
 }
 
 func benchmarkCompression(b *testing.B, size int, nameSuffix string) {
+	benchmarkCompressionOf(b, rawData(size), nameSuffix)
+}
+
+func benchmarkCompressionOf(b *testing.B, data []byte, nameSuffix string) {
 	profileStop := initProfiling(b, nameSuffix)
 	defer profileStop()
-	data := rawData(size)
+	b.SetBytes(int64(len(data)))
 	b.ResetTimer()
 	for run := 0; run < b.N; run++ {
 		compressor := NewCompressor(serial.NewByteStore())
@@ -77,6 +89,27 @@ func BenchmarkCompression1024KB(b *testing.B) {
 	benchmarkCompression(b, 1024*1024, "1024KB")
 }
 
+func BenchmarkCompressionRepetitive128KB(b *testing.B) {
+	benchmarkCompressionOf(b, repetitiveData(1024*128), "Repetitive128KB")
+}
+
+func BenchmarkCompressionRepetitive1024KB(b *testing.B) {
+	benchmarkCompressionOf(b, repetitiveData(1024*1024), "Repetitive1024KB")
+}
+
+func BenchmarkCompressionParallel128KB(b *testing.B) {
+	data := rawData(1024 * 128)
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			compressor := NewCompressor(serial.NewByteStore())
+			compressor.Write(data)
+			compressor.Close()
+		}
+	})
+}
+
 func benchmarkCompressionDecompression(b *testing.B, size int, nameSuffix string) {
 	profileStop := initProfiling(b, "")
 	defer profileStop()
@@ -50,3 +50,37 @@ func exampleResourceFile() []byte {
 
 	return store.Data()
 }
+
+type rawChunkEntry struct {
+	id          uint16
+	chunkType   byte
+	contentType byte
+	data        []byte
+}
+
+// rawResourceFile assembles a resource file byte by byte, in the layout of the original files,
+// without relying on Writer. The chunk data is stored as given, followed by the directory.
+func rawResourceFile(entries ...rawChunkEntry) []byte {
+	data := make([]byte, chunkDirectoryFileOffsetPos+4)
+	copy(data, headerString)
+	data[len(headerString)] = commentTerminator
+	firstChunkOffset := len(data)
+	for _, entry := range entries {
+		data = append(data, entry.data...)
+		for len(data)%boundarySize != 0 {
+			data = append(data, 0x00)
+		}
+	}
+	binary.LittleEndian.PutUint32(data[chunkDirectoryFileOffsetPos:], uint32(len(data)))
+	directory := make([]byte, 6+len(entries)*10)
+	binary.LittleEndian.PutUint16(directory[0:], uint16(len(entries)))
+	binary.LittleEndian.PutUint32(directory[2:], uint32(firstChunkOffset))
+	for index, entry := range entries {
+		raw := directory[6+index*10:]
+		length := uint32(len(entry.data))
+		binary.LittleEndian.PutUint16(raw[0:], entry.id)
+		binary.LittleEndian.PutUint32(raw[2:], length|uint32(entry.chunkType)<<24)
+		binary.LittleEndian.PutUint32(raw[6:], length|uint32(entry.contentType)<<24)
+	}
+	return append(data, directory...)
+}
@@ -1,67 +1,191 @@
 package rle
 
 import (
+	"bytes"
 	"io"
+	"math"
 )
 
-// Compress compresses the given byte array and returns the compressed result.
-func Compress(writer io.Writer, data []byte) {
+const (
+	maxShortSkip = 0x7F
+	maxSkip      = 0x7FFF
+	maxShortFill = 0xFF
+	maxFill      = 0x3FFF
+	maxShortCopy = 0x7F
+	maxCopy      = 0x3FFF
+)
+
+// Compress compresses the given byte array and writes the result, including the
+// end of stream marker, to the given writer.
+//
+// Zero bytes are always encoded as skipped bytes, which keep the content of the
+// output buffer when decompressing. Trailing zero bytes are not encoded at all.
+// All other bytes are split into runs and copied sequences so that the result is
+// as short as possible.
+//
+// The data is written with a single call, the error of which is returned.
+func Compress(writer io.Writer, data []byte) error {
 	end := len(data)
-	start := 0
+	for (end > 0) && (data[end-1] == 0x00) {
+		end--
+	}
+	buffer := bytes.NewBuffer(make([]byte, 0, end+end/0x80+3))
+	encode(buffer, data[:end])
+	writeExtended(buffer, 0x0000)
+	_, err := writer.Write(buffer.Bytes())
+	return err
+}
 
-	countSameBytes := func(from int, value byte) int {
-		index := from
-		for index < end && data[index] == value {
-			index++
+// encode writes the operations of the shortest encoding of given data.
+func encode(writer *bytes.Buffer, data []byte) {
+	for _, step := range shortestSteps(data) {
+		span := data[step.start : step.start+step.count]
+		switch step.kind {
+		case skipBytes:
+			writeZero(writer, step.count)
+		case fillBytes:
+			writeConstant(writer, step.count, span[0])
+		default:
+			writeRaw(writer, span)
 		}
-		return index - from
 	}
+}
 
-	{
-		trailingZeroes := 0
+type encodingStep struct {
+	kind  operationKind
+	start int
+	count int
+}
 
-		for temp := end - 1; temp >= 0 && data[temp] == 0; temp-- {
-			trailingZeroes++
+// shortestSteps determines the sequence of operations with the least encoded size.
+//
+// Each step ends at a position in the data and encodes all data from the end of
+// its preceding step with one operation: Zero runs are skipped as a whole, a fill
+// covers bytes of one run, and a copy covers any bytes between zero runs. Steps may
+// end within runs, as the sizes of fill and copy operations change at their count
+// limits; For example, copying the first byte of a run with the preceding bytes can
+// save a second fill operation for the remainder of the run.
+//
+// steps[p].cost is the minimal size to encode all data before position p. The size
+// of a fill or copy only depends on the size class of its count, so for each size
+// class the start with the least cost within its range is kept in a sliding window.
+// The windows of fills are restarted with each run, those of copies after each zero run.
+func shortestSteps(data []byte) []encodingStep {
+	steps := make([]stepBoundary, len(data)+1)
+	var shortFills, longFills, shortCopies, longCopies slidingMinimum
+	for position := 0; position < len(data); {
+		value := data[position]
+		if value == 0x00 {
+			end := position + 1
+			for (end < len(data)) && (data[end] == 0x00) {
+				end++
+			}
+			steps[end] = stepBoundary{cost: steps[position].cost + int32(skipSize(end-position)), from: int32(position), kind: skipBytes}
+			shortCopies.reset()
+			longCopies.reset()
+			position = end
+			continue
+		}
+		if (position == 0) || (data[position-1] != value) {
+			shortFills.reset()
+			longFills.reset()
 		}
-		end -= (trailingZeroes % 0x7FFF)
+		cost := int(steps[position].cost)
+		shortFills.push(position, cost)
+		longFills.push(position, cost)
+		shortCopies.push(position, cost-position)
+		longCopies.push(position, cost-position)
+
+		end := position + 1
+		current := &steps[end]
+		current.cost = math.MaxInt32
+		if start, found := shortFills.minimum(end - maxShortFill); found {
+			current.relax(steps[start].cost+3, start, fillBytes)
+		}
+		if start, found := longFills.minimum(end - maxFill); found {
+			current.relax(steps[start].cost+4, start, fillBytes)
+		}
+		if start, found := shortCopies.minimum(end - maxShortCopy); found {
+			current.relax(steps[start].cost+1+int32(end-start), start, copyBytes)
+		}
+		if start, found := longCopies.minimum(end - maxCopy); found {
+			current.relax(steps[start].cost+3+int32(end-start), start, copyBytes)
+		}
+		position = end
 	}
-	for start < end {
-		startValue := data[start]
-		sameByteCount := countSameBytes(start, startValue)
-
-		if startValue == 0 {
-			writeZero(writer, sameByteCount)
-			start += sameByteCount
-		} else if sameByteCount > 3 {
-			writeConstant(writer, sameByteCount, startValue)
-			start += sameByteCount
+
+	stepCount := 0
+	for position := len(data); position > 0; position = int(steps[position].from) {
+		stepCount++
+	}
+	result := make([]encodingStep, stepCount)
+	for position := len(data); position > 0; position = int(steps[position].from) {
+		stepCount--
+		start := int(steps[position].from)
+		result[stepCount] = encodingStep{kind: steps[position].kind, start: start, count: position - start}
+	}
+	return result
+}
+
+// stepBoundary is the end of a step, with the shortest way to encode all data up to it.
+// Resources are limited to 32 bit sizes, which keeps the entries small.
+type stepBoundary struct {
+	cost int32
+	from int32
+	kind operationKind
+}
+
+// relax considers encoding the data from given start with one operation of given kind.
+func (boundary *stepBoundary) relax(cost int32, start int, kind operationKind) {
+	if cost < boundary.cost {
+		boundary.cost, boundary.from, boundary.kind = cost, int32(start), kind
+	}
+}
+
+// skipSize returns the amount of bytes writeZero uses for given count.
+func skipSize(count int) (size int) {
+	for remain := count; remain > 0; {
+		if remain <= maxShortSkip {
+			size++
+			remain = 0
+		} else if remain < 0xFF {
+			size++
+			remain -= maxShortSkip
 		} else {
-			diffByteCount := 0
-			abort := false
-
-			for (start+diffByteCount) < end && !abort {
-				startValue = data[start+diffByteCount]
-				temp := countSameBytes(start+diffByteCount, startValue)
-
-				if startValue != 0 && temp < 4 {
-					diffByteCount += temp
-				} else {
-					abort = true
-				}
-			}
-			writeRaw(writer, data[start:start+diffByteCount])
-			start += diffByteCount
+			size += 3
+			remain -= minInt(remain, maxSkip)
 		}
 	}
-	writeExtended(writer, 0x0000)
+	return
+}
+
+// fillSize returns the amount of bytes writeConstant uses for given count.
+func fillSize(count int) (size int) {
+	for remain := count; remain > 0; {
+		if remain <= maxShortFill {
+			size += 3
+			remain = 0
+		} else {
+			size += 4
+			remain -= minInt(remain, maxFill)
+		}
+	}
+	return
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
 }
 
-func writeExtended(writer io.Writer, control uint16, extra ...byte) {
+func writeExtended(writer *bytes.Buffer, control uint16, extra ...byte) {
 	writer.Write([]byte{0x80, byte(control & 0xFF), byte((control >> 8) & 0xFF)})
 	writer.Write(extra)
 }
 
-func writeZero(writer io.Writer, size int) {
+func writeZero(writer *bytes.Buffer, size int) {
 	remain := size
 
 	for remain > 0 {
@@ -83,7 +207,7 @@ func writeZero(writer io.Writer, size int) {
 	}
 }
 
-func writeConstant(writer io.Writer, size int, value byte) {
+func writeConstant(writer *bytes.Buffer, size int, value byte) {
 	start := 0
 
 	for start < size {
@@ -103,7 +227,7 @@ func writeConstant(writer io.Writer, size int, value byte) {
 	}
 }
 
-func writeRaw(writer io.Writer, data []byte) {
+func writeRaw(writer *bytes.Buffer, data []byte) {
 	end := len(data)
 	start := 0
 
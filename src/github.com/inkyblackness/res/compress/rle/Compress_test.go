@@ -2,6 +2,7 @@ package rle
 
 import (
 	"bytes"
+	"errors"
 
 	check "gopkg.in/check.v1"
 )
@@ -9,6 +10,14 @@ import (
 type CompressSuite struct {
 }
 
+var errWriteFailed = errors.New("write failed")
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errWriteFailed
+}
+
 var _ = check.Suite(&CompressSuite{})
 
 func (suite *CompressSuite) TestEmptyArrayResultsInTerminator(c *check.C) {
@@ -58,3 +67,76 @@ func (suite *CompressSuite) TestWriteRawOfLessThan80(c *check.C) {
 
 	c.Check(writer.Bytes(), check.DeepEquals, []byte{0x03, 0x0A, 0x0B, 0x0C})
 }
+
+func (suite *CompressSuite) TestTrailingZeroesAreNotEncoded(c *check.C) {
+	writer := bytes.NewBuffer(nil)
+
+	Compress(writer, append([]byte{0x01}, make([]byte, 0x9000)...))
+
+	c.Check(writer.Bytes(), check.DeepEquals, []byte{0x01, 0x01, 0x80, 0x00, 0x00})
+}
+
+func (suite *CompressSuite) TestShortRunsWithinSequencesAreCopied(c *check.C) {
+	writer := bytes.NewBuffer(nil)
+
+	Compress(writer, []byte{0x01, 0x02, 0x02, 0x02, 0x03})
+
+	c.Check(writer.Bytes(), check.DeepEquals, []byte{0x05, 0x01, 0x02, 0x02, 0x02, 0x03, 0x80, 0x00, 0x00})
+}
+
+func (suite *CompressSuite) TestLongRunsAreFilled(c *check.C) {
+	writer := bytes.NewBuffer(nil)
+
+	Compress(writer, []byte{0x01, 0x02, 0x02, 0x02, 0x02, 0x02, 0x03})
+
+	c.Check(writer.Bytes(), check.DeepEquals, []byte{0x01, 0x01, 0x00, 0x05, 0x02, 0x01, 0x03, 0x80, 0x00, 0x00})
+}
+
+func (suite *CompressSuite) TestZeroesAreAlwaysSkipped(c *check.C) {
+	writer := bytes.NewBuffer(nil)
+
+	Compress(writer, []byte{0x01, 0x00, 0x02})
+
+	c.Check(writer.Bytes(), check.DeepEquals, []byte{0x01, 0x01, 0x81, 0x01, 0x02, 0x80, 0x00, 0x00})
+}
+
+func (suite *CompressSuite) TestLongSequencesAreCopiedExtended(c *check.C) {
+	writer := bytes.NewBuffer(nil)
+	data := make([]byte, 0x200)
+	for index := range data {
+		data[index] = byte(index%0xFF) + 1
+	}
+
+	Compress(writer, data)
+
+	result := writer.Bytes()
+	c.Check(len(result), check.Equals, 3+len(data)+3)
+	c.Check(result[:3], check.DeepEquals, []byte{0x80, 0x00, 0x82})
+}
+
+func (suite *CompressSuite) TestRunsAreSplitAtFillLimits(c *check.C) {
+	writer := bytes.NewBuffer(nil)
+	data := append([]byte{0x01}, bytes.Repeat([]byte{0x02}, 0x4000)...)
+
+	Compress(writer, data)
+
+	c.Check(writer.Bytes(), check.DeepEquals, []byte{0x02, 0x01, 0x02, 0x80, 0xFF, 0xFF, 0x02, 0x80, 0x00, 0x00})
+}
+
+func (suite *CompressSuite) TestResultIsDecompressible(c *check.C) {
+	data := []byte{0x00, 0x00, 0x05, 0x05, 0x05, 0x05, 0x05, 0x01, 0x02, 0x00, 0x03, 0x03, 0x04}
+	writer := bytes.NewBuffer(nil)
+	Compress(writer, data)
+
+	result := make([]byte, len(data))
+	err := Decompress(bytes.NewReader(writer.Bytes()), result)
+
+	c.Assert(err, check.IsNil)
+	c.Check(result, check.DeepEquals, data)
+}
+
+func (suite *CompressSuite) TestErrorOfWriterIsReturned(c *check.C) {
+	err := Compress(failingWriter{}, []byte{0x01})
+
+	c.Check(err, check.Equals, errWriteFailed)
+}
@@ -0,0 +1,51 @@
+package rle
+
+import (
+	"bytes"
+	"io"
+)
+
+// segmentSize is the amount of buffered data after which a compressor encodes
+// what it has received so far.
+const segmentSize = 0x10000
+
+type compressor struct {
+	target  io.Writer
+	err     error
+	pending []byte
+	encoded bytes.Buffer
+}
+
+// NewCompressor returns a writer that compresses all written data into given target.
+// The data is buffered and encoded in segments, as the shortest encoding depends
+// on the following data; A segment ends before the last run of equal bytes.
+// Closing the compressor encodes the remaining data, followed by the end of
+// stream marker. The first error of the target is returned.
+func NewCompressor(target io.Writer) io.WriteCloser {
+	return &compressor{target: target}
+}
+
+func (obj *compressor) Write(p []byte) (int, error) {
+	if obj.err != nil {
+		return 0, obj.err
+	}
+	obj.pending = append(obj.pending, p...)
+	if len(obj.pending) >= segmentSize {
+		runStart := len(obj.pending) - 1
+		for (runStart > 0) && (obj.pending[runStart-1] == obj.pending[runStart]) {
+			runStart--
+		}
+		obj.encoded.Reset()
+		encode(&obj.encoded, obj.pending[:runStart])
+		_, obj.err = obj.target.Write(obj.encoded.Bytes())
+		obj.pending = append(obj.pending[:0], obj.pending[runStart:]...)
+	}
+	return len(p), obj.err
+}
+
+func (obj *compressor) Close() error {
+	if obj.err != nil {
+		return obj.err
+	}
+	return Compress(obj.target, obj.pending)
+}
@@ -0,0 +1,56 @@
+package rle
+
+import (
+	"bytes"
+
+	check "gopkg.in/check.v1"
+)
+
+type CompressorSuite struct {
+}
+
+var _ = check.Suite(&CompressorSuite{})
+
+func (suite *CompressorSuite) TestClosingWritesSameResultAsCompress(c *check.C) {
+	data := []byte{0x01, 0x02, 0x02, 0x02, 0x02, 0x00, 0x00, 0x03, 0x00}
+	expected := bytes.NewBuffer(nil)
+	Compress(expected, data)
+
+	result := bytes.NewBuffer(nil)
+	compressor := NewCompressor(result)
+	compressor.Write(data[:3])
+	compressor.Write(data[3:])
+	err := compressor.Close()
+
+	c.Assert(err, check.IsNil)
+	c.Check(result.Bytes(), check.DeepEquals, expected.Bytes())
+}
+
+func (suite *CompressorSuite) TestLargeDataIsEncodedInSegments(c *check.C) {
+	data := make([]byte, segmentSize*3+0x123)
+	for index := range data {
+		data[index] = byte(index / 7)
+	}
+	result := bytes.NewBuffer(nil)
+	compressor := NewCompressor(result)
+	for start := 0; start < len(data); start += 0x1000 {
+		compressor.Write(data[start:minInt(start+0x1000, len(data))])
+	}
+	c.Check(result.Len() > 0, check.Equals, true)
+	err := compressor.Close()
+	c.Assert(err, check.IsNil)
+
+	decompressed := make([]byte, len(data))
+	err = Decompress(bytes.NewReader(result.Bytes()), decompressed)
+	c.Assert(err, check.IsNil)
+	c.Check(decompressed, check.DeepEquals, data)
+}
+
+func (suite *CompressorSuite) TestErrorOfTargetIsReturned(c *check.C) {
+	compressor := NewCompressor(failingWriter{})
+	compressor.Write(make([]byte, segmentSize+1))
+
+	err := compressor.Close()
+
+	c.Check(err, check.Equals, errWriteFailed)
+}
@@ -6,53 +6,48 @@ import (
 )
 
 // Decompress decompresses from the given reader and writes into the provided output buffer.
-func Decompress(reader io.Reader, output []byte) (err error) {
+// Skipped bytes, as well as the bytes following the end of the stream, keep the
+// content the buffer had before. The reader is consumed up to and including the
+// end of stream marker.
+// Skipping past the end of the buffer is tolerated, as existing data, such as
+// frames of low-resolution videos, may skip the remainder of the buffer with larger counts.
+// An error is returned if the data is malformed, ends without marker, or writes
+// beyond the output buffer.
+func Decompress(reader io.Reader, output []byte) error {
+	source := newOperationSource(reader)
 	outIndex := 0
-	done := false
-	nextByte := func() byte {
-		zz := []byte{0x00}
-		_, err = reader.Read(zz)
-		return zz[0]
-	}
-
-	for !done && (err == nil) {
-		first := nextByte()
-
-		if first == 0x00 {
-			nn := nextByte()
-			zz := nextByte()
 
-			outIndex += writeBytesOfValue(output[outIndex:outIndex+int(nn)], func() byte { return zz })
-		} else if first < 0x80 {
-			outIndex += writeBytesOfValue(output[outIndex:outIndex+int(first)], nextByte)
-		} else if first == 0x80 {
-			control := uint16(nextByte())
-			control += uint16(nextByte()) << 8
-			if control == 0x0000 {
-				done = true
-			} else if control < 0x8000 {
-				outIndex += int(control)
-			} else if control < 0xC000 {
-				outIndex += writeBytesOfValue(output[outIndex:outIndex+int(control&0x3FFF)], nextByte)
-			} else if (control & 0xFF00) == 0xC000 {
-				err = fmt.Errorf("Undefined case 80 nn C0")
-			} else {
-				zz := nextByte()
-
-				outIndex += writeBytesOfValue(output[outIndex:outIndex+int(control&0x3FFF)], func() byte { return zz })
-			}
-		} else {
-			outIndex += int(first & 0x7F)
+	for {
+		op, err := source.next()
+		if err != nil {
+			return err
+		}
+		if op.kind == endOfStream {
+			return nil
 		}
+		if op.kind == skipBytes {
+			outIndex += op.count
+			continue
+		}
+		if op.count > len(output)-outIndex {
+			return fmt.Errorf("operation of %d bytes exceeds output of %d bytes at offset %d", op.count, len(output), outIndex)
+		}
+		target := output[outIndex : outIndex+op.count]
+		switch op.kind {
+		case fillBytes:
+			fill(target, op.value)
+		case copyBytes:
+			err = source.readFull(target)
+		}
+		if err != nil {
+			return err
+		}
+		outIndex += op.count
 	}
-
-	return
 }
 
-func writeBytesOfValue(buffer []byte, producer func() byte) int {
-	count := len(buffer)
-	for i := 0; i < count; i++ {
-		buffer[i] = producer()
+func fill(buffer []byte, value byte) {
+	for index := range buffer {
+		buffer[index] = value
 	}
-	return count
 }
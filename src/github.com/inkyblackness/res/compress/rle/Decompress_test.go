@@ -2,6 +2,7 @@ package rle
 
 import (
 	"bytes"
+	"io"
 
 	check "gopkg.in/check.v1"
 )
@@ -9,6 +10,14 @@ import (
 type DecompressSuite struct {
 }
 
+type onlyReader struct {
+	reader io.Reader
+}
+
+func (wrapper onlyReader) Read(p []byte) (int, error) {
+	return wrapper.reader.Read(p)
+}
+
 var _ = check.Suite(&DecompressSuite{})
 
 func (suite *DecompressSuite) TestEmptyArrayReturnsError(c *check.C) {
@@ -141,3 +150,48 @@ func (suite *DecompressSuite) TestNNMore80WritesZeroes(c *check.C) {
 	c.Assert(err, check.IsNil)
 	c.Check(result, check.DeepEquals, []byte{0, 0, 0})
 }
+
+func (suite *DecompressSuite) TestSkippedBytesKeepOutput(c *check.C) {
+	result := []byte{0xAA, 0xBB, 0xCC}
+	err := Decompress(bytes.NewReader([]byte{0x81, 0x01, 0x11, 0x80, 0x00, 0x00}), result)
+
+	c.Assert(err, check.IsNil)
+	c.Check(result, check.DeepEquals, []byte{0xAA, 0x11, 0xCC})
+}
+
+func (suite *DecompressSuite) TestSkipPastEndOfOutputIsTolerated(c *check.C) {
+	inputs := [][]byte{
+		{0x01, 0x11, 0x84, 0x80, 0x00, 0x00},
+		{0x01, 0x11, 0x80, 0x00, 0x01, 0x81, 0x80, 0x00, 0x00}}
+
+	for _, input := range inputs {
+		result := []byte{0xAA, 0xBB}
+		err := Decompress(bytes.NewReader(input), result)
+
+		c.Check(err, check.IsNil, check.Commentf("input % X", input))
+		c.Check(result, check.DeepEquals, []byte{0x11, 0xBB}, check.Commentf("input % X", input))
+	}
+}
+
+func (suite *DecompressSuite) TestReturnsErrorIfOutputIsTooSmall(c *check.C) {
+	inputs := [][]byte{
+		{0x00, 0x05, 0xCC, 0x80, 0x00, 0x00},
+		{0x03, 0x01, 0x02, 0x03, 0x80, 0x00, 0x00},
+		{0x84, 0x01, 0xAA, 0x80, 0x00, 0x00},
+		{0x80, 0x04, 0x80, 0x01, 0x02, 0x03, 0x04, 0x80, 0x00, 0x00},
+		{0x80, 0x00, 0xC1, 0xCC, 0x80, 0x00, 0x00}}
+
+	for _, input := range inputs {
+		err := Decompress(bytes.NewReader(input), make([]byte, 2))
+
+		c.Check(err, check.NotNil, check.Commentf("input % X", input))
+	}
+}
+
+func (suite *DecompressSuite) TestReadsFromReaderWithoutByteAccess(c *check.C) {
+	result := make([]byte, 4)
+	err := Decompress(onlyReader{bytes.NewReader([]byte{0x02, 0xAA, 0xBB, 0x00, 0x02, 0xCC, 0x80, 0x00, 0x00})}, result)
+
+	c.Assert(err, check.IsNil)
+	c.Check(result, check.DeepEquals, []byte{0xAA, 0xBB, 0xCC, 0xCC})
+}
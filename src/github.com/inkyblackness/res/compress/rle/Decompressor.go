@@ -0,0 +1,92 @@
+package rle
+
+import (
+	"fmt"
+	"io"
+)
+
+type decompressor struct {
+	source *operationSource
+
+	left    int
+	current operation
+	ended   bool
+	err     error
+}
+
+// NewDecompressor returns a reader that decompresses the data from given source.
+// It provides exactly size bytes, with skipped bytes, as well as all bytes
+// after the end of stream marker, read as zero. Once all bytes are read, the end
+// of stream marker is consumed from the source and io.EOF is returned.
+// As with Decompress, skipping past size bytes is tolerated.
+// An error is returned if the data is malformed or writes more than size bytes.
+func NewDecompressor(source io.Reader, size int) io.Reader {
+	return &decompressor{source: newOperationSource(source), left: size}
+}
+
+func (obj *decompressor) Read(p []byte) (read int, err error) {
+	for (read < len(p)) && (obj.err == nil) {
+		if obj.current.count == 0 {
+			obj.nextOperation()
+			continue
+		}
+		count := obj.current.count
+		if count > len(p)-read {
+			count = len(p) - read
+		}
+		target := p[read : read+count]
+		switch obj.current.kind {
+		case copyBytes:
+			obj.err = obj.source.readFull(target)
+		case fillBytes:
+			fill(target, obj.current.value)
+		default:
+			fill(target, 0x00)
+		}
+		if obj.err == nil {
+			obj.current.count -= count
+			read += count
+		}
+	}
+	if (read > 0) && (obj.err == io.EOF) {
+		return read, nil
+	}
+	return read, obj.err
+}
+
+func (obj *decompressor) nextOperation() {
+	if obj.left == 0 {
+		for !obj.ended && (obj.err == nil) {
+			var op operation
+			op, obj.err = obj.source.next()
+			if (obj.err == nil) && (op.kind != endOfStream) && (op.kind != skipBytes) {
+				obj.err = fmt.Errorf("data exceeds size")
+			}
+			obj.ended = op.kind == endOfStream
+		}
+		if obj.err == nil {
+			obj.err = io.EOF
+		}
+		return
+	}
+	if obj.ended {
+		obj.current = operation{kind: skipBytes, count: obj.left}
+	} else {
+		var op operation
+		op, obj.err = obj.source.next()
+		if obj.err != nil {
+			return
+		}
+		if op.kind == endOfStream {
+			obj.ended = true
+			op = operation{kind: skipBytes, count: obj.left}
+		} else if (op.kind == skipBytes) && (op.count > obj.left) {
+			op.count = obj.left
+		} else if op.count > obj.left {
+			obj.err = fmt.Errorf("operation of %d bytes exceeds remaining size of %d bytes", op.count, obj.left)
+			return
+		}
+		obj.current = op
+	}
+	obj.left -= obj.current.count
+}
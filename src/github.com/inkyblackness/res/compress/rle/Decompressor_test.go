@@ -0,0 +1,80 @@
+package rle
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+
+	check "gopkg.in/check.v1"
+)
+
+type DecompressorSuite struct {
+}
+
+var _ = check.Suite(&DecompressorSuite{})
+
+func (suite *DecompressorSuite) TestProvidesSizeBytesWithZeroesForSkipped(c *check.C) {
+	reader := NewDecompressor(bytes.NewReader([]byte{0x81, 0x01, 0x11, 0x00, 0x02, 0x22, 0x80, 0x00, 0x00}), 6)
+
+	result, err := ioutil.ReadAll(reader)
+
+	c.Assert(err, check.IsNil)
+	c.Check(result, check.DeepEquals, []byte{0x00, 0x11, 0x22, 0x22, 0x00, 0x00})
+}
+
+func (suite *DecompressorSuite) TestConsumesEndOfStreamMarker(c *check.C) {
+	source := bytes.NewReader([]byte{0x02, 0x11, 0x22, 0x80, 0x00, 0x00, 0xFF})
+	reader := NewDecompressor(source, 2)
+
+	_, err := ioutil.ReadAll(reader)
+
+	c.Assert(err, check.IsNil)
+	c.Check(source.Len(), check.Equals, 1)
+}
+
+func (suite *DecompressorSuite) TestSupportsSmallReads(c *check.C) {
+	reader := NewDecompressor(bytes.NewReader([]byte{0x03, 0x11, 0x22, 0x33, 0x80, 0x00, 0x00}), 3)
+	buffer := make([]byte, 2)
+
+	count, err := reader.Read(buffer)
+	c.Assert(err, check.IsNil)
+	c.Check(buffer[:count], check.DeepEquals, []byte{0x11, 0x22})
+	count, err = reader.Read(buffer)
+	c.Assert(err, check.IsNil)
+	c.Check(buffer[:count], check.DeepEquals, []byte{0x33})
+	_, err = reader.Read(buffer)
+	c.Check(err, check.Equals, io.EOF)
+}
+
+func (suite *DecompressorSuite) TestReturnsErrorIfDataExceedsSize(c *check.C) {
+	reader := NewDecompressor(bytes.NewReader([]byte{0x00, 0x05, 0xCC, 0x80, 0x00, 0x00}), 4)
+
+	_, err := ioutil.ReadAll(reader)
+
+	c.Check(err, check.NotNil)
+}
+
+func (suite *DecompressorSuite) TestToleratesSkipPastSize(c *check.C) {
+	reader := NewDecompressor(bytes.NewReader([]byte{0x01, 0x11, 0x84, 0x81, 0x80, 0x00, 0x00}), 2)
+
+	result, err := ioutil.ReadAll(reader)
+
+	c.Assert(err, check.IsNil)
+	c.Check(result, check.DeepEquals, []byte{0x11, 0x00})
+}
+
+func (suite *DecompressorSuite) TestReturnsErrorIfDataFollowsSkipPastSize(c *check.C) {
+	reader := NewDecompressor(bytes.NewReader([]byte{0x01, 0x11, 0x84, 0x01, 0x22, 0x80, 0x00, 0x00}), 2)
+
+	_, err := ioutil.ReadAll(reader)
+
+	c.Check(err, check.NotNil)
+}
+
+func (suite *DecompressorSuite) TestReturnsErrorIfMarkerIsMissing(c *check.C) {
+	reader := NewDecompressor(bytes.NewReader([]byte{0x01, 0x11}), 1)
+
+	_, err := ioutil.ReadAll(reader)
+
+	c.Check(err, check.Equals, io.ErrUnexpectedEOF)
+}
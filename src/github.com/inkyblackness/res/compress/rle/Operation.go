@@ -0,0 +1,110 @@
+package rle
+
+import (
+	"errors"
+	"io"
+)
+
+type operationKind byte
+
+const (
+	skipBytes operationKind = iota
+	fillBytes
+	copyBytes
+	endOfStream
+)
+
+// operation is one decoded control code. For copyBytes, the bytes to copy
+// follow in the source.
+type operation struct {
+	kind  operationKind
+	count int
+	value byte
+}
+
+var errUndefinedOperation = errors.New("undefined case 80 nn C0")
+
+// operationSource reads operations from a reader. Readers that implement
+// io.ByteReader are read byte by byte directly; Any other reader with
+// single-byte reads. No data beyond the end of stream marker is consumed.
+type operationSource struct {
+	reader     io.Reader
+	byteReader io.ByteReader
+	scratch    [1]byte
+}
+
+func newOperationSource(reader io.Reader) *operationSource {
+	source := &operationSource{reader: reader}
+	source.byteReader, _ = reader.(io.ByteReader)
+	return source
+}
+
+func (source *operationSource) readByte() (value byte, err error) {
+	if source.byteReader != nil {
+		value, err = source.byteReader.ReadByte()
+	} else {
+		_, err = io.ReadFull(source.reader, source.scratch[:])
+		value = source.scratch[0]
+	}
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return
+}
+
+func (source *operationSource) readFull(buffer []byte) error {
+	_, err := io.ReadFull(source.reader, buffer)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+func (source *operationSource) next() (op operation, err error) {
+	first, err := source.readByte()
+	if err != nil {
+		return
+	}
+	switch {
+	case first == 0x00:
+		var count byte
+		count, err = source.readByte()
+		if err == nil {
+			op.value, err = source.readByte()
+		}
+		op.kind, op.count = fillBytes, int(count)
+	case first < 0x80:
+		op.kind, op.count = copyBytes, int(first)
+	case first > 0x80:
+		op.kind, op.count = skipBytes, int(first&0x7F)
+	default:
+		op, err = source.nextExtended()
+	}
+	return
+}
+
+func (source *operationSource) nextExtended() (op operation, err error) {
+	var low, high byte
+	low, err = source.readByte()
+	if err == nil {
+		high, err = source.readByte()
+	}
+	if err != nil {
+		return
+	}
+	control := uint16(high)<<8 | uint16(low)
+	switch {
+	case control == 0x0000:
+		op.kind = endOfStream
+	case control < 0x8000:
+		op.kind, op.count = skipBytes, int(control)
+	case control < 0xC000:
+		op.kind, op.count = copyBytes, int(control&0x3FFF)
+	case (control & 0xFF00) == 0xC000:
+		err = errUndefinedOperation
+	default:
+		op.kind, op.count = fillBytes, int(control&0x3FFF)
+		op.value, err = source.readByte()
+	}
+	return
+}
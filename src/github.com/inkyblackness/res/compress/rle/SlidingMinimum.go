@@ -0,0 +1,41 @@
+package rle
+
+type slidingEntry struct {
+	index int
+	value int
+}
+
+// slidingMinimum keeps the index with the least value within a window that
+// moves towards higher indices. Indices must be pushed in increasing order, and
+// the window start must not decrease between queries.
+type slidingMinimum struct {
+	entries []slidingEntry
+	head    int
+}
+
+// reset removes all entries, keeping the allocated memory.
+func (window *slidingMinimum) reset() {
+	window.entries = window.entries[:0]
+	window.head = 0
+}
+
+func (window *slidingMinimum) push(index, value int) {
+	for (len(window.entries) > window.head) && (window.entries[len(window.entries)-1].value >= value) {
+		window.entries = window.entries[:len(window.entries)-1]
+	}
+	if (window.head > 0) && (window.head == len(window.entries)) {
+		window.reset()
+	}
+	window.entries = append(window.entries, slidingEntry{index: index, value: value})
+}
+
+// minimum returns the index with the least value that is not below from.
+func (window *slidingMinimum) minimum(from int) (index int, found bool) {
+	for (window.head < len(window.entries)) && (window.entries[window.head].index < from) {
+		window.head++
+	}
+	if window.head < len(window.entries) {
+		return window.entries[window.head].index, true
+	}
+	return 0, false
+}
@@ -0,0 +1,41 @@
+package rle
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+func bitmapData(size int) []byte {
+	data := make([]byte, size)
+	for index := 0; index < size; {
+		runLength := rand.Intn(16) + 1
+		value := byte(rand.Intn(4))
+		for ; (runLength > 0) && (index < size); runLength-- {
+			data[index] = value
+			index++
+		}
+	}
+	return data
+}
+
+func BenchmarkCompress64KB(b *testing.B) {
+	data := bitmapData(64 * 1024)
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for run := 0; run < b.N; run++ {
+		Compress(bytes.NewBuffer(nil), data) // nolint: errcheck
+	}
+}
+
+func BenchmarkDecompress64KB(b *testing.B) {
+	data := bitmapData(64 * 1024)
+	compressed := bytes.NewBuffer(nil)
+	Compress(compressed, data) // nolint: errcheck
+	output := make([]byte, len(data))
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for run := 0; run < b.N; run++ {
+		Decompress(bytes.NewReader(compressed.Bytes()), output) // nolint: errcheck
+	}
+}
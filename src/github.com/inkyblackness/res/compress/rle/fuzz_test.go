@@ -0,0 +1,80 @@
+package rle
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+)
+
+// to be run with
+// go test -fuzz FuzzRoundTrip
+func FuzzRoundTrip(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{0x00, 0x00, 0x01})
+	f.Add([]byte{0x01, 0x02, 0x02, 0x02, 0x02, 0x02, 0x03, 0x00})
+	f.Add(bytes.Repeat([]byte{0x05}, 0x4100))
+	f.Add(append(bytes.Repeat([]byte{0x00}, 0x8100), 0x01))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		compressed := bytes.NewBuffer(nil)
+		if err := Compress(compressed, data); err != nil {
+			t.Fatalf("failed to compress: %v", err)
+		}
+
+		result := make([]byte, len(data))
+		if err := Decompress(bytes.NewReader(compressed.Bytes()), result); err != nil {
+			t.Fatalf("failed to decompress: %v", err)
+		}
+		if !bytes.Equal(result, data) {
+			t.Fatalf("decompressed data differs")
+		}
+		streamed, err := ioutil.ReadAll(NewDecompressor(bytes.NewReader(compressed.Bytes()), len(data)))
+		if err != nil {
+			t.Fatalf("failed to read decompressor: %v", err)
+		}
+		if !bytes.Equal(streamed, data) {
+			t.Fatalf("streamed data differs")
+		}
+		if naive := naiveSize(data); compressed.Len() > naive {
+			t.Fatalf("compressed size %d exceeds naive size %d", compressed.Len(), naive)
+		}
+	})
+}
+
+// to be run with
+// go test -fuzz FuzzDecompress
+func FuzzDecompress(f *testing.F) {
+	f.Add([]byte{0x80, 0x00, 0x00}, 0)
+	f.Add([]byte{0x00, 0x05, 0xCC, 0x80, 0x00, 0x00}, 5)
+	f.Add([]byte{0x80, 0xFF, 0xFF, 0xCD}, 16)
+	f.Add([]byte{0x80, 0x04, 0x80, 0x01}, 4)
+	f.Fuzz(func(t *testing.T, data []byte, size int) {
+		if (size < 0) || (size > 0x10000) {
+			return
+		}
+		Decompress(bytes.NewReader(data), make([]byte, size))        // nolint: errcheck
+		ioutil.ReadAll(NewDecompressor(bytes.NewReader(data), size)) // nolint: errcheck
+	})
+}
+
+// naiveSize returns the size of an encoding that copies all non-zero bytes
+// and skips all zero bytes, with short operations only.
+func naiveSize(data []byte) int {
+	end := len(data)
+	for (end > 0) && (data[end-1] == 0x00) {
+		end--
+	}
+	size := 3
+	for start := 0; start < end; {
+		count := 1
+		for (start+count < end) && (count < 0x7F) && ((data[start+count] == 0x00) == (data[start] == 0x00)) {
+			count++
+		}
+		if data[start] == 0x00 {
+			size++
+		} else {
+			size += 1 + count
+		}
+		start += count
+	}
+	return size
+}
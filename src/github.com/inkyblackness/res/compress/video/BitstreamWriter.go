@@ -0,0 +1,39 @@
+package video
+
+// BitstreamWriter is a utility to write big-endian integer values of arbitrary bit size into a bitstream.
+// It is the counterpart of BitstreamReader.
+type BitstreamWriter struct {
+	data []byte
+
+	buffer       uint64
+	bitsBuffered uint64
+}
+
+// NewBitstreamWriter returns a new instance of a bitstream writer with an empty stream.
+func NewBitstreamWriter() *BitstreamWriter {
+	return &BitstreamWriter{}
+}
+
+// Write appends the lowest bits of given value, most significant bit first.
+//
+// The function panics when writing more than 32 bits.
+func (writer *BitstreamWriter) Write(bits int, value uint32) {
+	if bits > 32 {
+		panic("Limit of bit count: 32")
+	}
+	writer.buffer = (writer.buffer << uint64(bits)) | (uint64(value) & ^(^uint64(0) << uint64(bits)))
+	writer.bitsBuffered += uint64(bits)
+	for writer.bitsBuffered >= 8 {
+		writer.data = append(writer.data, byte(writer.buffer>>(writer.bitsBuffered-8)))
+		writer.bitsBuffered -= 8
+	}
+}
+
+// Data returns the stream written so far. A partially written last byte is filled up with zero bits.
+func (writer *BitstreamWriter) Data() []byte {
+	result := writer.data[:len(writer.data):len(writer.data)]
+	if writer.bitsBuffered > 0 {
+		result = append(result, byte(writer.buffer<<(8-writer.bitsBuffered)))
+	}
+	return result
+}
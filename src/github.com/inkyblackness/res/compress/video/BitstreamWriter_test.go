@@ -0,0 +1,75 @@
+package video
+
+import (
+	check "gopkg.in/check.v1"
+)
+
+type BitstreamWriterSuite struct {
+}
+
+var _ = check.Suite(&BitstreamWriterSuite{})
+
+func (suite *BitstreamWriterSuite) TestWritePanicsForMoreThan32Bits(c *check.C) {
+	writer := NewBitstreamWriter()
+
+	c.Check(func() { writer.Write(33, 0) }, check.Panics, "Limit of bit count: 32")
+}
+
+func (suite *BitstreamWriterSuite) TestDataIsEmptyForNewWriter(c *check.C) {
+	writer := NewBitstreamWriter()
+
+	c.Check(len(writer.Data()), check.Equals, 0)
+}
+
+func (suite *BitstreamWriterSuite) TestWriteStoresMostSignificantBitFirst(c *check.C) {
+	writer := NewBitstreamWriter()
+
+	writer.Write(3, 5)
+	writer.Write(5, 0x0F)
+
+	c.Check(writer.Data(), check.DeepEquals, []byte{0xAF})
+}
+
+func (suite *BitstreamWriterSuite) TestWriteIgnoresHigherBitsOfValue(c *check.C) {
+	writer := NewBitstreamWriter()
+
+	writer.Write(4, 0xFFFFFFF5)
+	writer.Write(4, 0xA)
+
+	c.Check(writer.Data(), check.DeepEquals, []byte{0x5A})
+}
+
+func (suite *BitstreamWriterSuite) TestDataFillsLastByteWithZeroes(c *check.C) {
+	writer := NewBitstreamWriter()
+
+	writer.Write(9, 0x15E)
+
+	c.Check(writer.Data(), check.DeepEquals, []byte{0xAF, 0x00})
+}
+
+func (suite *BitstreamWriterSuite) TestWriteCanContinueAfterData(c *check.C) {
+	writer := NewBitstreamWriter()
+
+	writer.Write(4, 0xA)
+	writer.Data()
+	writer.Write(4, 0xB)
+
+	c.Check(writer.Data(), check.DeepEquals, []byte{0xAB})
+}
+
+func (suite *BitstreamWriterSuite) TestWrittenValuesCanBeReadBack(c *check.C) {
+	writer := NewBitstreamWriter()
+	values := []struct {
+		bits  int
+		value uint32
+	}{{1, 1}, {12, 0xABC}, {32, 0xFFFFFFFF}, {0, 0}, {5, 0x11}, {32, 0x12345678}, {3, 2}}
+
+	for _, entry := range values {
+		writer.Write(entry.bits, entry.value)
+	}
+	reader := NewBitstreamReader(writer.Data())
+	for _, entry := range values {
+		c.Check(reader.Read(entry.bits), check.Equals, entry.value)
+		reader.Advance(entry.bits)
+	}
+}
@@ -0,0 +1,234 @@
+package video
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+)
+
+const (
+	// primaryCodeBits is the amount of bits a FrameDecoder uses to look up a control word.
+	primaryCodeBits = 12
+	// extensionCodeBits is the amount of bits used for each further look up of long offsets.
+	extensionCodeBits = 4
+	// maxCodeBits limits the length of codes so that they fit into one write of a bitstream.
+	maxCodeBits = 32
+	// maxLongOffset is the highest index a long offset control word can refer to.
+	maxLongOffset = 0xFFFFF
+)
+
+// controlCode is the bit sequence that selects a control word in a bitstream.
+type controlCode struct {
+	bits  int
+	value uint32
+}
+
+// ControlDictionary is a set of control words, together with the codes that select them
+// within a bitstream.
+//
+// The codes are prefix free and the more frequent a control word is, the shorter its code.
+// The words of the dictionary form the lookup table a FrameDecoder uses: The first 4096 words
+// are indexed by the next 12 bits of the stream, and codes longer than that are continued
+// in further tables of 16 words, referenced by long offsets.
+type ControlDictionary struct {
+	words []ControlWord
+	codes map[ControlWord]controlCode
+}
+
+// NewControlDictionary returns a dictionary for the given control words, which are mapped to
+// the amount of their occurrences. The count bits of the given words are ignored.
+// An error is returned if no words are given or the dictionary would become too big.
+func NewControlDictionary(frequencies map[ControlWord]int) (*ControlDictionary, error) {
+	if len(frequencies) == 0 {
+		return nil, fmt.Errorf("no control words given")
+	}
+	symbols := make(map[ControlWord]int)
+	for word, frequency := range frequencies {
+		symbols[word.withCount(0)] += frequency
+	}
+	if len(symbols) == 1 {
+		// A single word would have an empty code; Pair it with an unused one.
+		if _, known := symbols[skipControl]; known {
+			symbols[repeatControl] = 0
+		} else {
+			symbols[skipControl] = 0
+		}
+	}
+
+	dictionary := &ControlDictionary{
+		words: make([]ControlWord, 1<<primaryCodeBits),
+		codes: canonicalCodes(codeLengths(symbols))}
+	var entries []codedWord
+	for word, code := range dictionary.codes {
+		entries = append(entries, codedWord{word: word, code: code})
+	}
+	sort.Slice(entries, func(a, b int) bool { return entries[a].word < entries[b].word })
+	err := dictionary.place(0, primaryCodeBits, 0, entries)
+	if err != nil {
+		return nil, err
+	}
+	return dictionary, nil
+}
+
+// Words returns the lookup table of the dictionary, as used by a FrameDecoder.
+func (dictionary *ControlDictionary) Words() []ControlWord {
+	return dictionary.words
+}
+
+// Encode writes the code of given control word to the bitstream. The count bits of the word are ignored.
+// The function panics if the word is not part of the dictionary.
+func (dictionary *ControlDictionary) Encode(writer *BitstreamWriter, word ControlWord) {
+	code, existing := dictionary.codes[word.withCount(0)]
+	if !existing {
+		panic(fmt.Sprintf("Control word %06X not in dictionary", uint32(word)))
+	}
+	writer.Write(code.bits, code.value)
+}
+
+type codedWord struct {
+	word ControlWord
+	code controlCode
+}
+
+// place stores the given words in the table starting at base, which is indexed by the
+// tableBits following the consumed bits of their code. Words with longer codes are
+// placed in extension tables, appended to the dictionary.
+func (dictionary *ControlDictionary) place(base int, tableBits int, consumed int, entries []codedWord) error {
+	extensions := make(map[int][]codedWord)
+	var extensionIndices []int
+	for _, entry := range entries {
+		remaining := entry.code.bits - consumed
+		if remaining <= tableBits {
+			index := int(entry.code.value&^(^uint32(0)<<uint(remaining))) << uint(tableBits-remaining)
+			for offset := 0; offset < 1<<uint(tableBits-remaining); offset++ {
+				dictionary.words[base+index+offset] = entry.word.withCount(remaining)
+			}
+		} else {
+			index := int(entry.code.value>>uint(remaining-tableBits)) & ^(^0 << uint(tableBits))
+			if _, known := extensions[index]; !known {
+				extensionIndices = append(extensionIndices, index)
+			}
+			extensions[index] = append(extensions[index], entry)
+		}
+	}
+	for _, index := range extensionIndices {
+		extensionBase := len(dictionary.words)
+		if extensionBase > maxLongOffset {
+			return fmt.Errorf("dictionary exceeds limit of %d words", maxLongOffset)
+		}
+		dictionary.words = append(dictionary.words, make([]ControlWord, 1<<extensionCodeBits)...)
+		dictionary.words[base+index] = ControlWord(extensionBase)
+		err := dictionary.place(extensionBase, extensionCodeBits, consumed+tableBits, extensions[index])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// codeLengths determines the lengths of a Huffman code for given symbols.
+// Should the longest code exceed maxCodeBits, the frequencies are flattened until it fits.
+func codeLengths(frequencies map[ControlWord]int) map[ControlWord]int {
+	for {
+		lengths := huffmanLengths(frequencies)
+		longest := 0
+		for _, length := range lengths {
+			if length > longest {
+				longest = length
+			}
+		}
+		if longest <= maxCodeBits {
+			return lengths
+		}
+		flattened := make(map[ControlWord]int)
+		for word, frequency := range frequencies {
+			flattened[word] = (frequency + 1) / 2
+		}
+		frequencies = flattened
+	}
+}
+
+type huffmanNode struct {
+	weight   int
+	order    ControlWord
+	children []*huffmanNode
+	word     ControlWord
+}
+
+type huffmanQueue []*huffmanNode
+
+func (queue huffmanQueue) Len() int { return len(queue) }
+
+func (queue huffmanQueue) Less(a, b int) bool {
+	if queue[a].weight != queue[b].weight {
+		return queue[a].weight < queue[b].weight
+	}
+	return queue[a].order < queue[b].order
+}
+
+func (queue huffmanQueue) Swap(a, b int) { queue[a], queue[b] = queue[b], queue[a] }
+
+func (queue *huffmanQueue) Push(node interface{}) { *queue = append(*queue, node.(*huffmanNode)) }
+
+func (queue *huffmanQueue) Pop() interface{} {
+	old := *queue
+	node := old[len(old)-1]
+	*queue = old[:len(old)-1]
+	return node
+}
+
+func huffmanLengths(frequencies map[ControlWord]int) map[ControlWord]int {
+	queue := &huffmanQueue{}
+	for word, frequency := range frequencies {
+		*queue = append(*queue, &huffmanNode{weight: frequency, order: word, word: word})
+	}
+	heap.Init(queue)
+	for queue.Len() > 1 {
+		first := heap.Pop(queue).(*huffmanNode)
+		second := heap.Pop(queue).(*huffmanNode)
+		order := first.order
+		if second.order < order {
+			order = second.order
+		}
+		heap.Push(queue, &huffmanNode{weight: first.weight + second.weight, order: order, children: []*huffmanNode{first, second}})
+	}
+
+	lengths := make(map[ControlWord]int)
+	var walk func(node *huffmanNode, depth int)
+	walk = func(node *huffmanNode, depth int) {
+		if len(node.children) == 0 {
+			lengths[node.word] = depth
+		}
+		for _, child := range node.children {
+			walk(child, depth+1)
+		}
+	}
+	walk(heap.Pop(queue).(*huffmanNode), 0)
+	return lengths
+}
+
+// canonicalCodes assigns the codes of given lengths in ascending order of length and word.
+func canonicalCodes(lengths map[ControlWord]int) map[ControlWord]controlCode {
+	words := make([]ControlWord, 0, len(lengths))
+	for word := range lengths {
+		words = append(words, word)
+	}
+	sort.Slice(words, func(a, b int) bool {
+		if lengths[words[a]] != lengths[words[b]] {
+			return lengths[words[a]] < lengths[words[b]]
+		}
+		return words[a] < words[b]
+	})
+
+	codes := make(map[ControlWord]controlCode)
+	value := uint32(0)
+	lastLength := 0
+	for _, word := range words {
+		length := lengths[word]
+		value <<= uint(length - lastLength)
+		codes[word] = controlCode{bits: length, value: value}
+		value++
+		lastLength = length
+	}
+	return codes
+}
@@ -0,0 +1,87 @@
+package video
+
+import (
+	check "gopkg.in/check.v1"
+)
+
+type ControlDictionarySuite struct {
+}
+
+var _ = check.Suite(&ControlDictionarySuite{})
+
+func (suite *ControlDictionarySuite) TestNewControlDictionaryReturnsErrorForNoWords(c *check.C) {
+	_, err := NewControlDictionary(nil)
+
+	c.Check(err, check.NotNil)
+}
+
+func (suite *ControlDictionarySuite) TestWordsContainFullPrimaryTable(c *check.C) {
+	dictionary, err := NewControlDictionary(map[ControlWord]int{controlWordOf(CtrlColorTile2ColorsStatic, 0x0101): 10})
+
+	c.Assert(err, check.IsNil)
+	c.Check(len(dictionary.Words()), check.Equals, 4096)
+}
+
+func (suite *ControlDictionarySuite) TestSingleWordIsEncodedWithOneBit(c *check.C) {
+	word := controlWordOf(CtrlColorTile2ColorsStatic, 0x0101)
+	dictionary, _ := NewControlDictionary(map[ControlWord]int{word: 10})
+	writer := NewBitstreamWriter()
+
+	dictionary.Encode(writer, word)
+
+	decoded := suite.decode(dictionary.Words(), writer.Data(), 1)
+	c.Check(decoded[0].Count(), check.Equals, 1)
+	c.Check(decoded[0].withCount(0), check.Equals, word)
+}
+
+func (suite *ControlDictionarySuite) TestFrequentWordsHaveShorterCodes(c *check.C) {
+	frequent := controlWordOf(CtrlColorTile2ColorsStatic, 0x0101)
+	rare := controlWordOf(CtrlColorTile2ColorsStatic, 0x0202)
+	dictionary, _ := NewControlDictionary(map[ControlWord]int{
+		frequent: 100, rare: 1,
+		controlWordOf(CtrlColorTile2ColorsStatic, 0x0303): 10,
+		controlWordOf(CtrlColorTile2ColorsStatic, 0x0404): 10})
+
+	c.Check(dictionary.codes[frequent].bits < dictionary.codes[rare].bits, check.Equals, true)
+}
+
+func (suite *ControlDictionarySuite) TestLongCodesAreDecodedWithLongOffsets(c *check.C) {
+	frequencies := make(map[ControlWord]int)
+	var words []ControlWord
+	for index := 0; index < 40; index++ {
+		word := controlWordOf(CtrlColorTile2ColorsMasked, uint32(index))
+		frequencies[word] = 1 << uint(index%30)
+		words = append(words, word)
+	}
+	dictionary, err := NewControlDictionary(frequencies)
+	c.Assert(err, check.IsNil)
+	c.Assert(len(dictionary.Words()) > 4096, check.Equals, true)
+	writer := NewBitstreamWriter()
+
+	for _, word := range words {
+		dictionary.Encode(writer, word)
+	}
+
+	decoded := suite.decode(dictionary.Words(), writer.Data(), len(words))
+	for index, word := range words {
+		c.Check(decoded[index].withCount(0), check.Equals, word, check.Commentf("word %d", index))
+	}
+}
+
+func (suite *ControlDictionarySuite) TestEncodePanicsForUnknownWord(c *check.C) {
+	dictionary, _ := NewControlDictionary(map[ControlWord]int{controlWordOf(CtrlColorTile2ColorsStatic, 0x0101): 10})
+	writer := NewBitstreamWriter()
+
+	c.Check(func() { dictionary.Encode(writer, controlWordOf(CtrlColorTile2ColorsStatic, 0x0202)) }, check.Panics,
+		"Control word 000202 not in dictionary")
+}
+
+func (suite *ControlDictionarySuite) decode(words []ControlWord, data []byte, count int) []ControlWord {
+	decoder := &FrameDecoder{controlWords: words}
+	bitstream := NewBitstreamReader(data)
+	var result []ControlWord
+	for len(result) < count {
+		result = append(result, decoder.readNextControlWord(bitstream))
+	}
+	return result
+}
@@ -32,3 +32,13 @@ func (word ControlWord) Type() ControlType {
 func (word ControlWord) Parameter() uint32 {
 	return uint32((uint32(word) >> 0) & 0x1FFFF)
 }
+
+// controlWordOf returns the control word of given type and parameter, with a count of zero.
+func controlWordOf(ctrlType ControlType, parameter uint32) ControlWord {
+	return ControlWord((uint32(ctrlType) << 17) | (parameter & 0x1FFFF))
+}
+
+// withCount returns the control word with the count value replaced.
+func (word ControlWord) withCount(count int) ControlWord {
+	return ControlWord((uint32(word) & 0x000FFFFF) | (uint32(count) << 20))
+}
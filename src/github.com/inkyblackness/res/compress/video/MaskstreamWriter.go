@@ -0,0 +1,33 @@
+package video
+
+// MaskstreamWriter writes mask integers into a byte array.
+// It is the counterpart of MaskstreamReader.
+type MaskstreamWriter struct {
+	data []byte
+}
+
+// NewMaskstreamWriter returns a new writer instance with an empty stream.
+func NewMaskstreamWriter() *MaskstreamWriter {
+	return &MaskstreamWriter{}
+}
+
+// Write appends a mask integer of given byte length, least significant byte first.
+//
+// Writing more than 8, or less than 0, bytes panics.
+func (writer *MaskstreamWriter) Write(bytes int, value uint64) {
+	if bytes > 8 {
+		panic("Limit of byte count: 8")
+	}
+	if bytes < 0 {
+		panic("Minimum byte count: 0")
+	}
+
+	for i := 0; i < bytes; i++ {
+		writer.data = append(writer.data, byte(value>>uint64(8*i)))
+	}
+}
+
+// Data returns the stream written so far.
+func (writer *MaskstreamWriter) Data() []byte {
+	return writer.data
+}
@@ -0,0 +1,50 @@
+package video
+
+import (
+	check "gopkg.in/check.v1"
+)
+
+type MaskstreamWriterSuite struct {
+}
+
+var _ = check.Suite(&MaskstreamWriterSuite{})
+
+func (suite *MaskstreamWriterSuite) TestWritePanicsForMoreThan8Bytes(c *check.C) {
+	writer := NewMaskstreamWriter()
+
+	c.Check(func() { writer.Write(9, 0) }, check.Panics, "Limit of byte count: 8")
+}
+
+func (suite *MaskstreamWriterSuite) TestWritePanicsForLessThan0Bytes(c *check.C) {
+	writer := NewMaskstreamWriter()
+
+	c.Check(func() { writer.Write(-1, 0) }, check.Panics, "Minimum byte count: 0")
+}
+
+func (suite *MaskstreamWriterSuite) TestWriteStoresIntegerInLittleEndianOrder(c *check.C) {
+	writer := NewMaskstreamWriter()
+
+	writer.Write(2, 0x2211)
+
+	c.Check(writer.Data(), check.DeepEquals, []byte{0x11, 0x22})
+}
+
+func (suite *MaskstreamWriterSuite) TestWriteAppendsToPreviousData(c *check.C) {
+	writer := NewMaskstreamWriter()
+
+	writer.Write(2, 0x2211)
+	writer.Write(3, 0xFF554433)
+
+	c.Check(writer.Data(), check.DeepEquals, []byte{0x11, 0x22, 0x33, 0x44, 0x55})
+}
+
+func (suite *MaskstreamWriterSuite) TestWrittenValuesCanBeReadBack(c *check.C) {
+	writer := NewMaskstreamWriter()
+
+	writer.Write(8, 0x8877665544332211)
+	writer.Write(4, 0xAABBCCDD)
+	reader := NewMaskstreamReader(writer.Data())
+
+	c.Check(reader.Read(8), check.Equals, uint64(0x8877665544332211))
+	c.Check(reader.Read(4), check.Equals, uint64(0xAABBCCDD))
+}
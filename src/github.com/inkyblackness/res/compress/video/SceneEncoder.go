@@ -0,0 +1,284 @@
+package video
+
+import (
+	"fmt"
+	"image"
+)
+
+const (
+	// skipRestOfRow is the skip count that skips all remaining tiles of a row.
+	skipRestOfRow = 0x1F
+	// maxSkipTiles is the highest amount of tiles a single skip with count can cover.
+	maxSkipTiles = skipRestOfRow
+	// maxLookupOffset is the highest offset into the palette lookup list a control word can refer to.
+	maxLookupOffset = 0x1FFFF
+)
+
+var (
+	skipControl   = controlWordOf(CtrlSkip, 0)
+	repeatControl = controlWordOf(CtrlRepeatPrevious, 0)
+)
+
+// EncodedScene is the result of a SceneEncoder.
+type EncodedScene struct {
+	// ControlWords is the dictionary for all frames, to be packed with PackControlWords.
+	ControlWords []ControlWord
+	// PaletteLookupList is the color list for all frames.
+	PaletteLookupList []byte
+	// Frames contains the streams for each frame, in the order they were added.
+	Frames []EncodedFrame
+}
+
+// EncodedFrame contains the streams of one frame, as they are passed to FrameDecoder.Decode().
+type EncodedFrame struct {
+	Bitstream  []byte
+	Maskstream []byte
+}
+
+// tileOperation is the encoded form of one control word within a bitstream.
+type tileOperation struct {
+	control   ControlWord
+	skip      bool
+	skipCount uint32
+	mask      uint64
+	maskBytes int
+}
+
+// SceneEncoder creates the streams for a sequence of frames that share one control
+// dictionary and palette lookup list.
+//
+// Each frame is encoded as the change to its previous frame, with the first frame
+// starting from all pixel being zero. As the decoder never writes pixel of palette index 0,
+// a pixel can not change back to index 0; Such frames are rejected.
+type SceneEncoder struct {
+	width           int
+	height          int
+	horizontalTiles int
+	verticalTiles   int
+
+	previous      []byte
+	lookupList    []byte
+	lookupOffsets map[string]uint32
+	frames        [][]tileOperation
+}
+
+// NewSceneEncoder returns a new encoder for frames of given size.
+// Both width and height must be a multiple of TileSideLength.
+func NewSceneEncoder(width, height int) *SceneEncoder {
+	return &SceneEncoder{
+		width:           width,
+		height:          height,
+		horizontalTiles: width / TileSideLength,
+		verticalTiles:   height / TileSideLength,
+		previous:        make([]byte, width*height),
+		lookupOffsets:   make(map[string]uint32)}
+}
+
+// AddFrame encodes the next frame of the scene.
+// The palette of the frame is not considered, only the pixel values are.
+func (encoder *SceneEncoder) AddFrame(frame *image.Paletted) error {
+	bounds := frame.Bounds()
+	if (encoder.width%TileSideLength != 0) || (encoder.height%TileSideLength != 0) {
+		return fmt.Errorf("size %dx%d is not a multiple of the tile size", encoder.width, encoder.height)
+	}
+	if (bounds.Dx() != encoder.width) || (bounds.Dy() != encoder.height) {
+		return fmt.Errorf("frame size %dx%d differs from scene size %dx%d", bounds.Dx(), bounds.Dy(), encoder.width, encoder.height)
+	}
+	current := make([]byte, len(encoder.previous))
+	for y := 0; y < encoder.height; y++ {
+		rowStart := frame.PixOffset(bounds.Min.X, bounds.Min.Y+y)
+		copy(current[y*encoder.width:(y+1)*encoder.width], frame.Pix[rowStart:rowStart+encoder.width])
+	}
+	for index, value := range current {
+		if (value == 0x00) && (encoder.previous[index] != 0x00) {
+			return fmt.Errorf("pixel at %d:%d can not change back to palette index 0",
+				index%encoder.width, index/encoder.width)
+		}
+	}
+
+	var operations []tileOperation
+	lastControl := ControlWord(0)
+	addOperation := func(operation tileOperation) {
+		control := operation.control
+		if control == lastControl {
+			operation.control = repeatControl
+		}
+		lastControl = control
+		operations = append(operations, operation)
+	}
+	for vTile := 0; vTile < encoder.verticalTiles; vTile++ {
+		for hTile := 0; hTile < encoder.horizontalTiles; {
+			unchanged := 0
+			for (hTile+unchanged < encoder.horizontalTiles) && encoder.tileUnchanged(current, hTile+unchanged, vTile) {
+				unchanged++
+			}
+			if hTile+unchanged == encoder.horizontalTiles {
+				addOperation(tileOperation{control: skipControl, skip: true, skipCount: skipRestOfRow})
+				hTile += unchanged
+			} else if unchanged > 0 {
+				skipped := unchanged
+				if skipped > maxSkipTiles {
+					skipped = maxSkipTiles
+				}
+				addOperation(tileOperation{control: skipControl, skip: true, skipCount: uint32(skipped - 1)})
+				hTile += skipped
+			} else {
+				operation, err := encoder.colorTile(current, hTile, vTile)
+				if err != nil {
+					return err
+				}
+				addOperation(operation)
+				hTile++
+			}
+		}
+	}
+
+	encoder.frames = append(encoder.frames, operations)
+	encoder.previous = current
+	return nil
+}
+
+// Encode creates the control dictionary for all added frames and returns the resulting streams.
+func (encoder *SceneEncoder) Encode() (scene EncodedScene, err error) {
+	if len(encoder.frames) == 0 {
+		return scene, fmt.Errorf("no frames added")
+	}
+	frequencies := make(map[ControlWord]int)
+	for _, operations := range encoder.frames {
+		for _, operation := range operations {
+			frequencies[operation.control]++
+		}
+	}
+	dictionary, err := NewControlDictionary(frequencies)
+	if err != nil {
+		return
+	}
+
+	scene.ControlWords = dictionary.Words()
+	scene.PaletteLookupList = encoder.lookupList
+	for _, operations := range encoder.frames {
+		bitstream := NewBitstreamWriter()
+		maskstream := NewMaskstreamWriter()
+		for _, operation := range operations {
+			dictionary.Encode(bitstream, operation.control)
+			if operation.skip {
+				bitstream.Write(5, operation.skipCount)
+			}
+			maskstream.Write(operation.maskBytes, operation.mask)
+		}
+		scene.Frames = append(scene.Frames, EncodedFrame{Bitstream: bitstream.Data(), Maskstream: maskstream.Data()})
+	}
+	return
+}
+
+func (encoder *SceneEncoder) tileUnchanged(current []byte, hTile, vTile int) bool {
+	start := vTile*TileSideLength*encoder.width + hTile*TileSideLength
+	for row := 0; row < TileSideLength; row++ {
+		offset := start + row*encoder.width
+		for column := 0; column < TileSideLength; column++ {
+			if current[offset+column] != encoder.previous[offset+column] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// colorTile determines how to color the pixel of the tile that changed.
+// Pixel that keep their value are either colored with the same value, if that is used
+// by changed pixel anyway, or left alone with a palette value of zero.
+func (encoder *SceneEncoder) colorTile(current []byte, hTile, vTile int) (tileOperation, error) {
+	start := vTile*TileSideLength*encoder.width + hTile*TileSideLength
+	var pixel [PixelPerTile]byte
+	var changed [PixelPerTile]bool
+	var used [256]bool
+	for i := 0; i < PixelPerTile; i++ {
+		offset := start + (i % TileSideLength) + encoder.width*(i/TileSideLength)
+		pixel[i] = current[offset]
+		changed[i] = current[offset] != encoder.previous[offset]
+		if changed[i] {
+			used[pixel[i]] = true
+		}
+	}
+	for i := 0; i < PixelPerTile; i++ {
+		if !changed[i] && !used[pixel[i]] {
+			pixel[i] = 0x00
+			used[0x00] = true
+		}
+	}
+	var colors []byte
+	for value, isUsed := range used {
+		if isUsed {
+			colors = append(colors, byte(value))
+		}
+	}
+	return encoder.colorOperation(pixel, colors)
+}
+
+// colorOperation returns the operation with the least amount of colors for given pixel.
+// colors contains the distinct values of the pixel in ascending order.
+func (encoder *SceneEncoder) colorOperation(pixel [PixelPerTile]byte, colors []byte) (operation tileOperation, err error) {
+	var indices [256]uint64
+	for index, value := range colors {
+		indices[value] = uint64(index)
+	}
+	mask := func(indexBitSize uint64) (result uint64) {
+		for i, value := range pixel {
+			result |= indices[value] << (indexBitSize * uint64(i))
+		}
+		return
+	}
+	twoColors := func(first, second byte) uint32 {
+		return uint32(first) | (uint32(second) << 8)
+	}
+
+	switch {
+	case len(colors) == 1:
+		operation.control = controlWordOf(CtrlColorTile2ColorsStatic, twoColors(colors[0], colors[0]))
+	case len(colors) == 2:
+		operation.mask = mask(1)
+		if operation.mask == 0xAAAA {
+			operation = tileOperation{control: controlWordOf(CtrlColorTile2ColorsStatic, twoColors(colors[0], colors[1]))}
+		} else if operation.mask == 0x5555 {
+			operation = tileOperation{control: controlWordOf(CtrlColorTile2ColorsStatic, twoColors(colors[1], colors[0]))}
+		} else {
+			operation.control = controlWordOf(CtrlColorTile2ColorsMasked, twoColors(colors[0], colors[1]))
+			operation.maskBytes = 2
+		}
+	default:
+		indexBitSize := uint64(2)
+		for len(colors) > 1<<indexBitSize {
+			indexBitSize++
+		}
+		list := make([]byte, 1<<indexBitSize)
+		for index := range list {
+			if index < len(colors) {
+				list[index] = colors[index]
+			} else {
+				list[index] = colors[len(colors)-1]
+			}
+		}
+		var offset uint32
+		offset, err = encoder.lookupOffset(list)
+		operation.control = controlWordOf(CtrlColorTile4ColorsMasked+ControlType(indexBitSize-2), offset)
+		operation.mask = mask(indexBitSize)
+		operation.maskBytes = int(indexBitSize) * PixelPerTile / 8
+	}
+	return
+}
+
+// lookupOffset returns the offset of given list of colors within the palette lookup list.
+// Lists are shared among all tiles.
+func (encoder *SceneEncoder) lookupOffset(list []byte) (uint32, error) {
+	key := string(list)
+	if offset, existing := encoder.lookupOffsets[key]; existing {
+		return offset, nil
+	}
+	offset := uint32(len(encoder.lookupList))
+	if offset > maxLookupOffset {
+		return 0, fmt.Errorf("palette lookup list exceeds limit of %d bytes", maxLookupOffset)
+	}
+	encoder.lookupList = append(encoder.lookupList, list...)
+	encoder.lookupOffsets[key] = offset
+	return offset, nil
+}
@@ -0,0 +1,150 @@
+package video
+
+import (
+	"image"
+	"image/color"
+
+	check "gopkg.in/check.v1"
+)
+
+type SceneEncoderSuite struct {
+	width  int
+	height int
+}
+
+var _ = check.Suite(&SceneEncoderSuite{})
+
+func (suite *SceneEncoderSuite) SetUpTest(c *check.C) {
+	suite.width = TileSideLength * 12
+	suite.height = TileSideLength * 3
+}
+
+func (suite *SceneEncoderSuite) TestEncodeReturnsErrorWithoutFrames(c *check.C) {
+	encoder := NewSceneEncoder(suite.width, suite.height)
+
+	_, err := encoder.Encode()
+
+	c.Check(err, check.NotNil)
+}
+
+func (suite *SceneEncoderSuite) TestAddFrameReturnsErrorForDifferentSize(c *check.C) {
+	encoder := NewSceneEncoder(suite.width, suite.height)
+
+	err := encoder.AddFrame(suite.frame(func(x, y int) byte { return 1 }, suite.width, suite.height+TileSideLength))
+
+	c.Check(err, check.NotNil)
+}
+
+func (suite *SceneEncoderSuite) TestAddFrameReturnsErrorForSizeNotMultipleOfTiles(c *check.C) {
+	encoder := NewSceneEncoder(suite.width+1, suite.height)
+
+	err := encoder.AddFrame(suite.frame(func(x, y int) byte { return 1 }, suite.width+1, suite.height))
+
+	c.Check(err, check.NotNil)
+}
+
+func (suite *SceneEncoderSuite) TestAddFrameReturnsErrorForPixelChangingBackToZero(c *check.C) {
+	encoder := NewSceneEncoder(suite.width, suite.height)
+	encoder.AddFrame(suite.frame(func(x, y int) byte { return 1 }, suite.width, suite.height))
+
+	err := encoder.AddFrame(suite.frame(func(x, y int) byte { return byte(x % 2) }, suite.width, suite.height))
+
+	c.Check(err, check.ErrorMatches, "pixel at 0:0 .*")
+}
+
+func (suite *SceneEncoderSuite) TestSingleColorFrame(c *check.C) {
+	suite.verifyRoundTrip(c, func(x, y int) byte { return 0x20 })
+}
+
+func (suite *SceneEncoderSuite) TestFrameWithStripes(c *check.C) {
+	suite.verifyRoundTrip(c,
+		func(x, y int) byte { return byte(0x10 + x%2) },
+		func(x, y int) byte { return byte(0x11 - x%2) })
+}
+
+func (suite *SceneEncoderSuite) TestFramesWithManyColorsPerTile(c *check.C) {
+	suite.verifyRoundTrip(c,
+		func(x, y int) byte { return byte(1 + (x+y*3)%(1+x/4)) },
+		func(x, y int) byte { return byte(1 + (x*y)%(1+y+x/4)) },
+		func(x, y int) byte { return byte(1 + (x*7+y*5)%16) })
+}
+
+func (suite *SceneEncoderSuite) TestFramesWithPartialChanges(c *check.C) {
+	suite.verifyRoundTrip(c,
+		func(x, y int) byte { return byte(1 + (x/4+y/4)%3) },
+		func(x, y int) byte {
+			if (x > 9) && (x < 15) && (y == 5) {
+				return 0x40
+			}
+			return byte(1 + (x/4+y/4)%3)
+		},
+		func(x, y int) byte {
+			if (x > 30) && (y < 4) {
+				return byte(0x50 + x%5)
+			}
+			return byte(1 + (x/4+y/4)%3)
+		})
+}
+
+func (suite *SceneEncoderSuite) TestZeroPixelAreKeptUntilColored(c *check.C) {
+	suite.verifyRoundTrip(c,
+		func(x, y int) byte { return byte((x + y) % 3) },
+		func(x, y int) byte { return byte(1 + (x+y)%3) })
+}
+
+func (suite *SceneEncoderSuite) TestLongRowsAreSkippedInSteps(c *check.C) {
+	suite.width = TileSideLength * 80
+	suite.verifyRoundTrip(c,
+		func(x, y int) byte { return 1 },
+		func(x, y int) byte {
+			if x == suite.width-1 {
+				return 2
+			}
+			return 1
+		})
+}
+
+func (suite *SceneEncoderSuite) TestColorListsAreShared(c *check.C) {
+	encoder := NewSceneEncoder(suite.width, suite.height)
+	encoder.AddFrame(suite.frame(func(x, y int) byte { return byte(1 + (x+y)%4) }, suite.width, suite.height))
+
+	scene, err := encoder.Encode()
+
+	c.Assert(err, check.IsNil)
+	c.Check(scene.PaletteLookupList, check.DeepEquals, []byte{1, 2, 3, 4})
+}
+
+func (suite *SceneEncoderSuite) verifyRoundTrip(c *check.C, frames ...func(x, y int) byte) {
+	encoder := NewSceneEncoder(suite.width, suite.height)
+	for _, pixel := range frames {
+		err := encoder.AddFrame(suite.frame(pixel, suite.width, suite.height))
+		c.Assert(err, check.IsNil)
+	}
+	scene, err := encoder.Encode()
+	c.Assert(err, check.IsNil)
+	c.Assert(len(scene.Frames), check.Equals, len(frames))
+
+	buffer := make([]byte, suite.width*suite.height)
+	builder := NewFrameDecoderBuilder(suite.width, suite.height)
+	builder.ForStandardFrame(buffer, suite.width)
+	builder.WithPaletteLookupList(scene.PaletteLookupList)
+	words, err := UnpackControlWords(PackControlWords(scene.ControlWords))
+	c.Assert(err, check.IsNil)
+	builder.WithControlWords(words)
+	for index, pixel := range frames {
+		builder.Build().Decode(scene.Frames[index].Bitstream, scene.Frames[index].Maskstream)
+
+		expected := suite.frame(pixel, suite.width, suite.height)
+		c.Check(buffer, check.DeepEquals, expected.Pix, check.Commentf("frame %d", index))
+	}
+}
+
+func (suite *SceneEncoderSuite) frame(pixel func(x, y int) byte, width, height int) *image.Paletted {
+	frame := image.NewPaletted(image.Rect(0, 0, width, height), color.Palette{})
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			frame.Pix[y*frame.Stride+x] = pixel(x, y)
+		}
+	}
+	return frame
+}
@@ -42,7 +42,7 @@ func (sequence *VideoClipSequence) Code(coder serial.Coder) {
 	coder.Code(&sequence.Width)
 	coder.Code(&sequence.Height)
 	coder.Code(&sequence.FramesID)
-	coder.Code(sequence.Unknown0006)
+	coder.Code(&sequence.Unknown0006)
 	coder.Code(&sequence.IntroFlag)
 	for _, entry := range sequence.Entries {
 		coder.Code(entry)
@@ -0,0 +1,175 @@
+package font
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type bdfSource struct {
+	ascent  int
+	descent int
+	glyphs  map[rune]Glyph
+}
+
+// NewBdfSource reads a font in the Glyph Bitmap Distribution Format (BDF) from given reader.
+// The encoding of the characters is taken as Unicode; Characters without encoding are ignored.
+func NewBdfSource(reader io.Reader) (GlyphSource, error) {
+	if reader == nil {
+		return nil, fmt.Errorf("reader is nil")
+	}
+	parser := &bdfParser{scanner: bufio.NewScanner(reader)}
+	source := &bdfSource{glyphs: make(map[rune]Glyph)}
+	ascentKnown := false
+	descentKnown := false
+
+	for parser.next() {
+		switch parser.keyword() {
+		case "FONTBOUNDINGBOX":
+			box := parser.numbers(4)
+			if !ascentKnown {
+				source.ascent = box[1] + box[3]
+			}
+			if !descentKnown {
+				source.descent = -box[3]
+			}
+		case "FONT_ASCENT":
+			source.ascent = parser.numbers(1)[0]
+			ascentKnown = true
+		case "FONT_DESCENT":
+			source.descent = parser.numbers(1)[0]
+			descentKnown = true
+		case "STARTCHAR":
+			encoding, glyph := parser.character()
+			if encoding >= 0 {
+				source.glyphs[rune(encoding)] = glyph
+			}
+		}
+	}
+	if parser.err == nil {
+		parser.err = parser.scanner.Err()
+	}
+	if parser.err != nil {
+		return nil, parser.err
+	}
+	return source, nil
+}
+
+func (source *bdfSource) Ascent() int {
+	return source.ascent
+}
+
+func (source *bdfSource) Descent() int {
+	return source.descent
+}
+
+func (source *bdfSource) Glyph(r rune) (Glyph, bool) {
+	glyph, available := source.glyphs[r]
+	return glyph, available
+}
+
+// bdfParser reads the lines of a BDF file. It stops at the first error.
+type bdfParser struct {
+	scanner *bufio.Scanner
+	line    int
+	fields  []string
+	err     error
+}
+
+func (parser *bdfParser) next() bool {
+	for (parser.err == nil) && parser.scanner.Scan() {
+		parser.line++
+		parser.fields = strings.Fields(parser.scanner.Text())
+		if len(parser.fields) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (parser *bdfParser) keyword() string {
+	return parser.fields[0]
+}
+
+func (parser *bdfParser) fail(format string, args ...interface{}) {
+	if parser.err == nil {
+		parser.err = fmt.Errorf("line %d: %s", parser.line, fmt.Sprintf(format, args...))
+	}
+}
+
+// numbers returns the given amount of integer values following the keyword.
+func (parser *bdfParser) numbers(count int) []int {
+	values := make([]int, count)
+	if len(parser.fields) < count+1 {
+		parser.fail("%s requires %d values", parser.keyword(), count)
+		return values
+	}
+	for index := range values {
+		value, err := strconv.Atoi(parser.fields[index+1])
+		if err != nil {
+			parser.fail("invalid value %q", parser.fields[index+1])
+		}
+		values[index] = value
+	}
+	return values
+}
+
+// character reads the properties of a character up to ENDCHAR.
+func (parser *bdfParser) character() (encoding int, glyph Glyph) {
+	encoding = -1
+	var box []int
+	for parser.next() {
+		switch parser.keyword() {
+		case "ENCODING":
+			encoding = parser.numbers(1)[0]
+		case "DWIDTH":
+			glyph.Advance = parser.numbers(2)[0]
+		case "BBX":
+			box = parser.numbers(4)
+		case "BITMAP":
+			if box == nil {
+				parser.fail("BITMAP without BBX")
+				return
+			}
+			glyph.Mask = parser.bitmap(box)
+		case "ENDCHAR":
+			if glyph.Mask == nil {
+				glyph.Mask = image.NewAlpha(image.Rectangle{})
+			}
+			return
+		}
+	}
+	parser.fail("character not complete")
+	return
+}
+
+// bitmap reads the rows of a glyph with given bounding box.
+func (parser *bdfParser) bitmap(box []int) *image.Alpha {
+	width, height, left, bottom := box[0], box[1], box[2], box[3]
+	if (width < 0) || (height < 0) {
+		parser.fail("invalid bounding box %dx%d", width, height)
+		return nil
+	}
+	mask := image.NewAlpha(image.Rect(left, -(bottom + height), left+width, -bottom))
+	for row := 0; row < height; row++ {
+		if !parser.next() {
+			parser.fail("bitmap incomplete")
+			return mask
+		}
+		data, err := hex.DecodeString(parser.keyword())
+		if (err != nil) || (len(data)*8 < width) {
+			parser.fail("invalid bitmap row %q", parser.keyword())
+			return mask
+		}
+		for column := 0; column < width; column++ {
+			if (data[column/8] & (0x80 >> uint(column%8))) != 0 {
+				mask.Pix[row*mask.Stride+column] = 0xFF
+			}
+		}
+	}
+	return mask
+}
@@ -0,0 +1,95 @@
+package font
+
+import (
+	"bytes"
+	"image"
+	"io"
+	"strings"
+
+	check "gopkg.in/check.v1"
+)
+
+type BdfSourceSuite struct {
+}
+
+var _ = check.Suite(&BdfSourceSuite{})
+
+const testBdf = `STARTFONT 2.1
+FONT -test-fixed-medium-r-normal--4-40-75-75-c-30-iso10646-1
+SIZE 4 75 75
+FONTBOUNDINGBOX 3 5 0 -2
+STARTPROPERTIES 2
+FONT_ASCENT 3
+FONT_DESCENT 1
+ENDPROPERTIES
+CHARS 2
+STARTCHAR A
+ENCODING 65
+SWIDTH 750 0
+DWIDTH 3 0
+BBX 2 3 0 0
+BITMAP
+40
+C0
+80
+ENDCHAR
+STARTCHAR unencoded
+ENCODING -1
+DWIDTH 1 0
+BBX 1 1 0 0
+BITMAP
+80
+ENDCHAR
+ENDFONT
+`
+
+func (suite *BdfSourceSuite) TestReturnsErrorOnNil(c *check.C) {
+	_, err := NewBdfSource(nil)
+
+	c.Check(err, check.NotNil)
+}
+
+func (suite *BdfSourceSuite) TestReadsMetrics(c *check.C) {
+	source, err := NewBdfSource(strings.NewReader(testBdf))
+
+	c.Assert(err, check.IsNil)
+	c.Check(source.Ascent(), check.Equals, 3)
+	c.Check(source.Descent(), check.Equals, 1)
+}
+
+func (suite *BdfSourceSuite) TestMetricsDefaultToBoundingBox(c *check.C) {
+	source, err := NewBdfSource(strings.NewReader("STARTFONT 2.1\nFONTBOUNDINGBOX 3 5 0 -2\nENDFONT\n"))
+
+	c.Assert(err, check.IsNil)
+	c.Check(source.Ascent(), check.Equals, 3)
+	c.Check(source.Descent(), check.Equals, 2)
+}
+
+func (suite *BdfSourceSuite) TestReadsEncodedGlyphs(c *check.C) {
+	source, _ := NewBdfSource(strings.NewReader(testBdf))
+
+	glyph, available := source.Glyph('A')
+
+	c.Assert(available, check.Equals, true)
+	c.Check(glyph.Advance, check.Equals, 3)
+	c.Check(glyph.Mask.Bounds(), check.Equals, image.Rect(0, -3, 2, 0))
+	c.Check(glyph.Mask.Pix, check.DeepEquals, []byte{0x00, 0xFF, 0xFF, 0xFF, 0xFF, 0x00})
+}
+
+func (suite *BdfSourceSuite) TestIgnoresUnencodedGlyphs(c *check.C) {
+	source, _ := NewBdfSource(strings.NewReader(testBdf))
+
+	_, available := source.Glyph(-1)
+
+	c.Check(available, check.Equals, false)
+}
+
+func (suite *BdfSourceSuite) TestReturnsErrorForIncompleteBitmap(c *check.C) {
+	_, err := NewBdfSource(strings.NewReader(testBdf[:strings.Index(testBdf, "80\nENDCHAR")]))
+
+	c.Check(err, check.ErrorMatches, "line .*: bitmap incomplete")
+}
+
+func bytesReader(data []byte) io.ReadSeeker {
+	return bytes.NewReader(data)
+}
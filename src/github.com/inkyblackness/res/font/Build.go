@@ -0,0 +1,131 @@
+package font
+
+import (
+	"fmt"
+
+	"github.com/inkyblackness/res/text"
+)
+
+// coverageThreshold is the coverage from which a pixel of a monochrome font is set.
+const coverageThreshold = 0x80
+
+// FromSource is the value of Height and Baseline in BuildOptions that takes them from the glyph source.
+const FromSource = -1
+
+// BuildOptions describe how a font is built from the glyphs of a source.
+type BuildOptions struct {
+	// FirstCharacter and LastCharacter specify the range of characters of the font, both inclusive.
+	FirstCharacter int
+	LastCharacter  int
+	// Codepage maps the characters to the runes of the glyph source. The default codepage is used if nil.
+	Codepage text.Codepage
+
+	// Height is the amount of pixel rows of the bitmap. A negative value, such as FromSource,
+	// covers the baseline and the descent of the source.
+	Height int
+	// Baseline is the row of the bitmap the glyphs are placed on. A negative value, such as FromSource,
+	// takes the ascent of the source.
+	Baseline int
+	// Spacing is added to the advance of each glyph. It may be negative to put glyphs closer together.
+	Spacing int
+
+	// Shades are the palette indices of a color font, for increasing coverage of a pixel.
+	// The coverage is split evenly into one more level than there are shades; Pixels of the lowest
+	// level are left at zero. If no shades are given, a monochrome font is built instead.
+	Shades []byte
+}
+
+// Build creates a font with the glyphs of given source. Each glyph is cut to its advance.
+// Characters the source has no glyph for, as well as character 0x00, are empty and have no width.
+func Build(source GlyphSource, options BuildOptions) (Font, error) {
+	if (options.FirstCharacter < 0) || (options.LastCharacter > 0xFF) || (options.FirstCharacter > options.LastCharacter) {
+		return nil, fmt.Errorf("invalid character range [%d, %d]", options.FirstCharacter, options.LastCharacter)
+	}
+	codepage := options.Codepage
+	if codepage == nil {
+		codepage = text.DefaultCodepage()
+	}
+	baseline := options.Baseline
+	if baseline < 0 {
+		baseline = source.Ascent()
+	}
+	height := options.Height
+	if height < 0 {
+		height = baseline + source.Descent()
+	}
+	if (height <= 0) || (height > 0xFFFF) {
+		return nil, fmt.Errorf("invalid height %d", height)
+	}
+
+	characterCount := options.LastCharacter - options.FirstCharacter + 1
+	glyphs := make([]Glyph, characterCount)
+	xOffsets := make([]int, characterCount+1)
+	for index := range glyphs {
+		character := options.FirstCharacter + index
+		width := 0
+		if r, known := runeOf(codepage, character); known {
+			if glyph, available := source.Glyph(r); available {
+				glyphs[index] = glyph
+				width = glyph.Advance + options.Spacing
+			}
+		}
+		if width < 0 {
+			width = 0
+		}
+		xOffsets[index+1] = xOffsets[index] + width
+	}
+	pixelWidth := xOffsets[characterCount]
+	if pixelWidth > 0xFFFF {
+		return nil, fmt.Errorf("glyphs too wide with %d pixels", pixelWidth)
+	}
+
+	monochrome := len(options.Shades) == 0
+	bitmapWidth := pixelWidth
+	if monochrome {
+		bitmapWidth = (pixelWidth + 7) / 8
+	}
+	font := newSimpleFont(monochrome, bitmapWidth, height, options.FirstCharacter, options.LastCharacter)
+	for index, offset := range xOffsets {
+		font.xOffsets[index] = uint16(offset)
+	}
+	for index, glyph := range glyphs {
+		if glyph.Mask != nil {
+			font.drawGlyph(glyph, xOffsets[index], xOffsets[index+1], baseline, options.Shades)
+		}
+	}
+	return font, nil
+}
+
+// runeOf returns the rune a codepage has for given character.
+func runeOf(codepage text.Codepage, character int) (rune, bool) {
+	runes := []rune(codepage.Decode([]byte{byte(character)}))
+	if len(runes) != 1 {
+		return 0, false
+	}
+	return runes[0], true
+}
+
+// drawGlyph places the mask of a glyph in the bitmap, limited to the columns from left to right (exclusive).
+func (font *simpleFont) drawGlyph(glyph Glyph, left, right int, baseline int, shades []byte) {
+	bounds := glyph.Mask.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		row := baseline + y
+		if (row < 0) || (row >= font.bitmapHeight) {
+			continue
+		}
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			column := left + x
+			if (column < left) || (column >= right) {
+				continue
+			}
+			coverage := glyph.Mask.AlphaAt(x, y).A
+			if font.monochrome {
+				if coverage >= coverageThreshold {
+					font.bitmap[row*font.bitmapWidth+column/8] |= 0x80 >> uint(column%8)
+				}
+			} else if level := int(coverage) * (len(shades) + 1) / 0x100; level > 0 {
+				font.bitmap[row*font.bitmapWidth+column] = shades[level-1]
+			}
+		}
+	}
+}
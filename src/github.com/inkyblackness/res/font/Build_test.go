@@ -0,0 +1,120 @@
+package font
+
+import (
+	"image"
+
+	check "gopkg.in/check.v1"
+)
+
+type BuildSuite struct {
+}
+
+var _ = check.Suite(&BuildSuite{})
+
+// testGlyphSource provides glyphs for 'A' and 'B' with an ascent of 3 and a descent of 1.
+type testGlyphSource struct{}
+
+func (source testGlyphSource) Ascent() int  { return 3 }
+func (source testGlyphSource) Descent() int { return 1 }
+
+func (source testGlyphSource) Glyph(r rune) (Glyph, bool) {
+	switch r {
+	case 'A':
+		mask := image.NewAlpha(image.Rect(0, -3, 2, 0))
+		copy(mask.Pix, []byte{0xFF, 0x00, 0x00, 0x80, 0x7F, 0x40})
+		return Glyph{Mask: mask, Advance: 3}, true
+	case 'B':
+		mask := image.NewAlpha(image.Rect(-1, 0, 3, 1))
+		copy(mask.Pix, []byte{0xFF, 0xFF, 0xFF, 0xFF})
+		return Glyph{Mask: mask, Advance: 2}, true
+	}
+	return Glyph{}, false
+}
+
+// optionsFor returns build options for given character range, with height and baseline from the source.
+func optionsFor(first, last int) BuildOptions {
+	return BuildOptions{FirstCharacter: first, LastCharacter: last, Height: FromSource, Baseline: FromSource}
+}
+
+func (suite *BuildSuite) TestBuildCreatesMonochromeFont(c *check.C) {
+	font, err := Build(testGlyphSource{}, optionsFor('A', 'C'))
+
+	c.Assert(err, check.IsNil)
+	c.Check(font.IsMonochrome(), check.Equals, true)
+	c.Check(font.FirstCharacter(), check.Equals, int('A'))
+	c.Check(font.LastCharacter(), check.Equals, int('C'))
+	c.Check([]int{font.GlyphXOffset(0), font.GlyphXOffset(1), font.GlyphXOffset(2), font.GlyphXOffset(3)},
+		check.DeepEquals, []int{0, 3, 5, 5})
+	c.Check(font.BitmapWidth(), check.Equals, 1)
+	c.Check(font.BitmapHeight(), check.Equals, 4)
+	c.Check(font.Bitmap(), check.DeepEquals, []byte{0x80, 0x40, 0x00, 0x18})
+}
+
+func (suite *BuildSuite) TestBuildCreatesColorFontWithShades(c *check.C) {
+	options := optionsFor('A', 'A')
+	options.Shades = []byte{10, 20, 30}
+	font, err := Build(testGlyphSource{}, options)
+
+	c.Assert(err, check.IsNil)
+	c.Check(font.IsMonochrome(), check.Equals, false)
+	c.Check(font.BitmapWidth(), check.Equals, 3)
+	c.Check(font.Bitmap(), check.DeepEquals, []byte{
+		30, 0, 0,
+		0, 20, 0,
+		10, 10, 0,
+		0, 0, 0})
+}
+
+func (suite *BuildSuite) TestBuildAppliesBaselineHeightAndSpacing(c *check.C) {
+	font, err := Build(testGlyphSource{}, BuildOptions{FirstCharacter: 'B', LastCharacter: 'B',
+		Baseline: 1, Height: 2, Spacing: 1, Shades: []byte{1}})
+
+	c.Assert(err, check.IsNil)
+	c.Check(font.GlyphXOffset(1), check.Equals, 3)
+	c.Check(font.Bitmap(), check.DeepEquals, []byte{
+		0, 0, 0,
+		1, 1, 1})
+}
+
+func (suite *BuildSuite) TestBuildAcceptsBaselineOnFirstRow(c *check.C) {
+	options := optionsFor('B', 'B')
+	options.Baseline = 0
+	options.Shades = []byte{1}
+	font, err := Build(testGlyphSource{}, options)
+
+	c.Assert(err, check.IsNil)
+	c.Check(font.BitmapHeight(), check.Equals, 1)
+	c.Check(font.Bitmap(), check.DeepEquals, []byte{1, 1})
+}
+
+func (suite *BuildSuite) TestBuildReturnsErrorForZeroHeight(c *check.C) {
+	options := optionsFor('A', 'A')
+	options.Height = 0
+	_, err := Build(testGlyphSource{}, options)
+
+	c.Check(err, check.ErrorMatches, "invalid height 0")
+}
+
+func (suite *BuildSuite) TestBuildMapsCharactersWithCodepage(c *check.C) {
+	font, err := Build(testGlyphSource{}, optionsFor(0x00, 0x80))
+
+	c.Assert(err, check.IsNil)
+	c.Check(font.GlyphXOffset(0x80)-font.GlyphXOffset(0x41), check.Equals, 5)
+	c.Check(font.GlyphXOffset(0x81), check.Equals, 5)
+}
+
+func (suite *BuildSuite) TestBuildReturnsErrorForInvalidRange(c *check.C) {
+	_, err := Build(testGlyphSource{}, optionsFor('B', 'A'))
+
+	c.Check(err, check.NotNil)
+}
+
+func (suite *BuildSuite) TestBuiltFontCanBeSaved(c *check.C) {
+	font, _ := Build(testGlyphSource{}, optionsFor('A', 'B'))
+
+	loaded, err := Load(bytesReader(Save(font)))
+
+	c.Assert(err, check.IsNil)
+	c.Check(loaded.Bitmap(), check.DeepEquals, font.Bitmap())
+	c.Check(loaded.GlyphXOffset(2), check.Equals, 5)
+}
@@ -22,3 +22,12 @@ type Font interface {
 	// result for the next index after the last character.
 	GlyphXOffset(index int) int
 }
+
+// HeaderKeepingFont is a font that keeps the header data of unknown meaning, as loaded fonts do.
+// Encoders store the header data of such fonts, and zeroes for all others.
+type HeaderKeepingFont interface {
+	Font
+
+	// UnknownHeaderData returns the header data of unknown meaning.
+	UnknownHeaderData() UnknownHeaderData
+}
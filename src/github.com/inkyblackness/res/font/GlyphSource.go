@@ -0,0 +1,25 @@
+package font
+
+import (
+	"image"
+)
+
+// Glyph is the image of a character, as provided by a GlyphSource.
+type Glyph struct {
+	// Mask holds the coverage of the pixels, from 0x00 for empty to 0xFF for fully covered.
+	// Its bounds are relative to the origin of the glyph, which is on the baseline.
+	// Rows above the baseline have negative coordinates.
+	Mask *image.Alpha
+	// Advance is the distance, in pixels, from the origin of this glyph to the one of the next.
+	Advance int
+}
+
+// GlyphSource provides the glyphs of a typeface at a fixed size, for building a font.
+type GlyphSource interface {
+	// Ascent returns the amount of pixel rows above the baseline.
+	Ascent() int
+	// Descent returns the amount of pixel rows below the baseline.
+	Descent() int
+	// Glyph returns the glyph for given rune. It returns false if the source has no glyph for it.
+	Glyph(r rune) (Glyph, bool)
+}
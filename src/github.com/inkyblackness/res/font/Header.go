@@ -39,3 +39,9 @@ type Header struct {
 	// Height of the bitmap in bytes
 	Height uint16
 }
+
+// UnknownHeaderData contains the header data of unknown meaning, so that it can be kept when re-encoding a font.
+type UnknownHeaderData struct {
+	Unknown0002 [34]byte
+	Unknown0028 [32]byte
+}
@@ -25,6 +25,7 @@ func Load(source io.ReadSeeker) (font Font, err error) {
 	simpleFont := newSimpleFont(header.Type == Monochrome,
 		int(header.Width), int(header.Height),
 		int(header.FirstCharacter), int(header.LastCharacter))
+	simpleFont.unknownHeaderData = UnknownHeaderData{Unknown0002: header.Unknown0002, Unknown0028: header.Unknown0028}
 
 	source.Seek(int64(header.XOffsetStart), os.SEEK_SET)
 	binary.Read(source, binary.LittleEndian, simpleFont.xOffsets)
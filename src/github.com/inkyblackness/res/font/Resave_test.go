@@ -23,6 +23,19 @@ func (suite *ResaveSuite) TestLoadSaveLoadSaveCreatesSameData(c *check.C) {
 	c.Check(data2, check.DeepEquals, data1)
 }
 
+func (suite *ResaveSuite) TestResaveKeepsUnknownHeaderData(c *check.C) {
+	data1 := suite.aSimpleFont()
+	data1[2] = 0x12
+	data1[0x28+31] = 0x34
+	newFont, err := Load(bytes.NewReader(data1))
+
+	c.Assert(err, check.IsNil)
+
+	data2 := Save(newFont)
+
+	c.Check(data2, check.DeepEquals, data1)
+}
+
 func (suite *ResaveSuite) aSimpleFont() []byte {
 	var header Header
 	buf := bytes.NewBuffer(nil)
@@ -6,6 +6,7 @@ import (
 )
 
 // Save encodes a bitmap font into a stream of bytes.
+// The header data of unknown meaning is taken from the font should it be a HeaderKeepingFont.
 func Save(font Font) []byte {
 	writer := bytes.NewBuffer(nil)
 	var header Header
@@ -16,6 +17,11 @@ func Save(font Font) []byte {
 	} else {
 		header.Type = Color
 	}
+	if keeping, isKeeping := font.(HeaderKeepingFont); isKeeping {
+		data := keeping.UnknownHeaderData()
+		header.Unknown0002 = data.Unknown0002
+		header.Unknown0028 = data.Unknown0028
+	}
 	header.FirstCharacter = uint16(font.FirstCharacter())
 	header.LastCharacter = uint16(font.LastCharacter())
 	header.XOffsetStart = uint32(HeaderSize)
@@ -0,0 +1,120 @@
+package font
+
+import (
+	"image"
+	"math"
+
+	xfont "golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+type sfntSource struct {
+	font    *sfnt.Font
+	buffer  sfnt.Buffer
+	ppem    fixed.Int26_6
+	metrics xfont.Metrics
+}
+
+// NewSfntSource returns a glyph source that rasterizes the outlines of given TrueType or OpenType font data.
+// The pixel size is the height of the em square; The glyphs are rasterized with anti-aliasing and without hinting.
+func NewSfntSource(data []byte, pixelSize float64) (GlyphSource, error) {
+	parsed, err := sfnt.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	source := &sfntSource{font: parsed, ppem: fixed.Int26_6(math.Round(pixelSize * 64))}
+	source.metrics, err = parsed.Metrics(&source.buffer, source.ppem, xfont.HintingNone)
+	if err != nil {
+		return nil, err
+	}
+	return source, nil
+}
+
+func (source *sfntSource) Ascent() int {
+	return source.metrics.Ascent.Ceil()
+}
+
+func (source *sfntSource) Descent() int {
+	return source.metrics.Descent.Ceil()
+}
+
+func (source *sfntSource) Glyph(r rune) (glyph Glyph, available bool) {
+	index, err := source.font.GlyphIndex(&source.buffer, r)
+	if (err != nil) || (index == 0) {
+		return
+	}
+	advance, err := source.font.GlyphAdvance(&source.buffer, index, source.ppem, xfont.HintingNone)
+	if err != nil {
+		return
+	}
+	segments, err := source.font.LoadGlyph(&source.buffer, index, source.ppem, nil)
+	if err != nil {
+		return
+	}
+	glyph.Advance = advance.Round()
+	glyph.Mask = rasterize(segments)
+	return glyph, true
+}
+
+// rasterize returns the coverage of the given outline, in bounds relative to its origin.
+func rasterize(segments []sfnt.Segment) *image.Alpha {
+	var minX, minY, maxX, maxY fixed.Int26_6
+	for index, segment := range segments {
+		for _, point := range segment.Args[:argumentCount(segment.Op)] {
+			if (index == 0) || (point.X < minX) {
+				minX = point.X
+			}
+			if (index == 0) || (point.Y < minY) {
+				minY = point.Y
+			}
+			if (index == 0) || (point.X > maxX) {
+				maxX = point.X
+			}
+			if (index == 0) || (point.Y > maxY) {
+				maxY = point.Y
+			}
+		}
+	}
+	rect := image.Rect(minX.Floor(), minY.Floor(), maxX.Ceil(), maxY.Ceil())
+	if rect.Empty() {
+		return image.NewAlpha(rect)
+	}
+
+	rasterizer := vector.NewRasterizer(rect.Dx(), rect.Dy())
+	point := func(value fixed.Point26_6) (float32, float32) {
+		return float32(value.X)/64 - float32(rect.Min.X), float32(value.Y)/64 - float32(rect.Min.Y)
+	}
+	for _, segment := range segments {
+		ax, ay := point(segment.Args[0])
+		switch segment.Op {
+		case sfnt.SegmentOpMoveTo:
+			rasterizer.MoveTo(ax, ay)
+		case sfnt.SegmentOpLineTo:
+			rasterizer.LineTo(ax, ay)
+		case sfnt.SegmentOpQuadTo:
+			bx, by := point(segment.Args[1])
+			rasterizer.QuadTo(ax, ay, bx, by)
+		case sfnt.SegmentOpCubeTo:
+			bx, by := point(segment.Args[1])
+			cx, cy := point(segment.Args[2])
+			rasterizer.CubeTo(ax, ay, bx, by, cx, cy)
+		}
+	}
+	drawn := image.NewAlpha(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	rasterizer.Draw(drawn, drawn.Bounds(), image.Opaque, image.Point{})
+
+	return &image.Alpha{Pix: drawn.Pix, Stride: drawn.Stride, Rect: rect}
+}
+
+// argumentCount returns the amount of points used by a segment operator.
+func argumentCount(op sfnt.SegmentOp) int {
+	switch op {
+	case sfnt.SegmentOpQuadTo:
+		return 2
+	case sfnt.SegmentOpCubeTo:
+		return 3
+	}
+	return 1
+}
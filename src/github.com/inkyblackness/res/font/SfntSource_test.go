@@ -0,0 +1,57 @@
+package font
+
+import (
+	"golang.org/x/image/font/gofont/goregular"
+
+	check "gopkg.in/check.v1"
+)
+
+type SfntSourceSuite struct {
+}
+
+var _ = check.Suite(&SfntSourceSuite{})
+
+func (suite *SfntSourceSuite) TestReturnsErrorForInvalidData(c *check.C) {
+	_, err := NewSfntSource([]byte{0x00, 0x01, 0x02}, 12)
+
+	c.Check(err, check.NotNil)
+}
+
+func (suite *SfntSourceSuite) TestProvidesMetricsForPixelSize(c *check.C) {
+	source, err := NewSfntSource(goregular.TTF, 16)
+
+	c.Assert(err, check.IsNil)
+	c.Check(source.Ascent(), check.Equals, 16)
+	c.Check(source.Descent(), check.Equals, 4)
+}
+
+func (suite *SfntSourceSuite) TestRasterizesGlyphsAboveBaseline(c *check.C) {
+	source, _ := NewSfntSource(goregular.TTF, 16)
+
+	glyph, available := source.Glyph('I')
+
+	c.Assert(available, check.Equals, true)
+	c.Check(glyph.Advance > 0, check.Equals, true)
+	bounds := glyph.Mask.Bounds()
+	c.Check(bounds.Max.Y, check.Equals, 0)
+	c.Check(bounds.Min.Y < -8, check.Equals, true)
+	c.Check(glyph.Mask.AlphaAt((bounds.Min.X+bounds.Max.X)/2, -5).A, check.Equals, uint8(0xFF))
+}
+
+func (suite *SfntSourceSuite) TestProvidesEmptyGlyphForSpace(c *check.C) {
+	source, _ := NewSfntSource(goregular.TTF, 16)
+
+	glyph, available := source.Glyph(' ')
+
+	c.Assert(available, check.Equals, true)
+	c.Check(glyph.Mask.Bounds().Empty(), check.Equals, true)
+	c.Check(glyph.Advance > 0, check.Equals, true)
+}
+
+func (suite *SfntSourceSuite) TestReportsMissingGlyph(c *check.C) {
+	source, _ := NewSfntSource(goregular.TTF, 16)
+
+	_, available := source.Glyph(0xE000)
+
+	c.Check(available, check.Equals, false)
+}
@@ -0,0 +1,147 @@
+package font
+
+import (
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+)
+
+// Metrics describe everything of a font besides its bitmap, which is stored as a sheet.
+// They are stored next to the sheet, as JSON.
+type Metrics struct {
+	// Monochrome is set for fonts that store their pixels as bits. The sheet of such a font
+	// uses palette index 0x01 for set pixels.
+	Monochrome bool `json:"monochrome"`
+	// FirstCharacter and LastCharacter are the range of characters of the font, both inclusive.
+	FirstCharacter int `json:"firstCharacter"`
+	LastCharacter  int `json:"lastCharacter"`
+	// XOffsets are the columns the glyphs start at, with one more entry for the end of the last glyph.
+	XOffsets []int `json:"xOffsets"`
+	// Unknown0002 and Unknown0028 are the header data of unknown meaning, as hexadecimal bytes.
+	Unknown0002 string `json:"unknown0002"`
+	Unknown0028 string `json:"unknown0028"`
+}
+
+// monochromePalette is the palette of sheets of monochrome fonts.
+var monochromePalette = color.Palette{
+	color.NRGBA{R: 0x00, G: 0x00, B: 0x00, A: 0xFF},
+	color.NRGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}}
+
+// ToSheet returns the bitmap of given font as one image, together with its metrics.
+// The sheet of a color font uses the given palette. Should it be nil, a gray ramp is used.
+// All pixels of the bitmap are kept, including the padding of monochrome fonts.
+func ToSheet(font Font, palette color.Palette) (*image.Paletted, *Metrics) {
+	metrics := &Metrics{
+		Monochrome:     font.IsMonochrome(),
+		FirstCharacter: font.FirstCharacter(),
+		LastCharacter:  font.LastCharacter(),
+		XOffsets:       make([]int, font.LastCharacter()-font.FirstCharacter()+2)}
+	for index := range metrics.XOffsets {
+		metrics.XOffsets[index] = font.GlyphXOffset(index)
+	}
+	var unknown UnknownHeaderData
+	if keeping, isKeeping := font.(HeaderKeepingFont); isKeeping {
+		unknown = keeping.UnknownHeaderData()
+	}
+	metrics.Unknown0002 = hex.EncodeToString(unknown.Unknown0002[:])
+	metrics.Unknown0028 = hex.EncodeToString(unknown.Unknown0028[:])
+
+	bitmap := font.Bitmap()
+	if font.IsMonochrome() {
+		sheet := image.NewPaletted(image.Rect(0, 0, font.BitmapWidth()*8, font.BitmapHeight()), monochromePalette)
+		for index := range sheet.Pix {
+			if (bitmap[index/8] & (0x80 >> uint(index%8))) != 0 {
+				sheet.Pix[index] = 0x01
+			}
+		}
+		return sheet, metrics
+	}
+	if palette == nil {
+		palette = grayPalette()
+	}
+	sheet := image.NewPaletted(image.Rect(0, 0, font.BitmapWidth(), font.BitmapHeight()), palette)
+	copy(sheet.Pix, bitmap)
+	return sheet, metrics
+}
+
+// FromSheet creates a font from a sheet and its metrics, as provided by ToSheet.
+// The palette indices of the sheet are used as they are; For monochrome fonts, any index other
+// than zero sets the pixel. The sheet of a monochrome font must have a width that is a multiple of eight.
+func FromSheet(sheet *image.Paletted, metrics *Metrics) (Font, error) {
+	bounds := sheet.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if (metrics.FirstCharacter < 0) || (metrics.LastCharacter > math.MaxUint16) ||
+		(metrics.FirstCharacter > metrics.LastCharacter) {
+		return nil, fmt.Errorf("invalid character range [%d, %d]", metrics.FirstCharacter, metrics.LastCharacter)
+	}
+	bitmapWidth := width
+	if metrics.Monochrome {
+		if width%8 != 0 {
+			return nil, fmt.Errorf("sheet width %d of a monochrome font is not a multiple of 8", width)
+		}
+		bitmapWidth = width / 8
+	}
+	if (bitmapWidth > math.MaxUint16) || (height > math.MaxUint16) {
+		return nil, fmt.Errorf("sheet of size %dx%d too large", width, height)
+	}
+	expectedOffsets := metrics.LastCharacter - metrics.FirstCharacter + 2
+	if len(metrics.XOffsets) != expectedOffsets {
+		return nil, fmt.Errorf("%d x offsets given, expected %d", len(metrics.XOffsets), expectedOffsets)
+	}
+	for index, offset := range metrics.XOffsets {
+		if (offset < 0) || (offset > width) || ((index > 0) && (offset < metrics.XOffsets[index-1])) {
+			return nil, fmt.Errorf("x offset %d of glyph %d is out of order or beyond the sheet", offset, index)
+		}
+	}
+
+	font := newSimpleFont(metrics.Monochrome, bitmapWidth, height, metrics.FirstCharacter, metrics.LastCharacter)
+	for index, offset := range metrics.XOffsets {
+		font.xOffsets[index] = uint16(offset)
+	}
+	err := decodeUnknown(font.unknownHeaderData.Unknown0002[:], metrics.Unknown0002, "unknown0002")
+	if err == nil {
+		err = decodeUnknown(font.unknownHeaderData.Unknown0028[:], metrics.Unknown0028, "unknown0028")
+	}
+	if err != nil {
+		return nil, err
+	}
+	for y := 0; y < height; y++ {
+		row := sheet.Pix[y*sheet.Stride : y*sheet.Stride+width]
+		if metrics.Monochrome {
+			for x, value := range row {
+				if value != 0x00 {
+					font.bitmap[y*bitmapWidth+x/8] |= 0x80 >> uint(x%8)
+				}
+			}
+		} else {
+			copy(font.bitmap[y*bitmapWidth:], row)
+		}
+	}
+	return font, nil
+}
+
+// decodeUnknown fills the given header data from its hexadecimal text. An empty text keeps the data at zero.
+func decodeUnknown(data []byte, text string, name string) error {
+	if len(text) == 0 {
+		return nil
+	}
+	decoded, err := hex.DecodeString(text)
+	if err != nil {
+		return fmt.Errorf("invalid %v: %v", name, err)
+	}
+	if len(decoded) != len(data) {
+		return fmt.Errorf("%v has %d bytes, expected %d", name, len(decoded), len(data))
+	}
+	copy(data, decoded)
+	return nil
+}
+
+func grayPalette() color.Palette {
+	palette := make(color.Palette, 256)
+	for index := range palette {
+		palette[index] = color.Gray{Y: byte(index)}
+	}
+	return palette
+}
@@ -0,0 +1,128 @@
+package font
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+
+	check "gopkg.in/check.v1"
+)
+
+type SheetSuite struct {
+}
+
+var _ = check.Suite(&SheetSuite{})
+
+func (suite *SheetSuite) TestToSheetOfMonochromeFontUnpacksBits(c *check.C) {
+	font := newSimpleFont(true, 2, 1, 'A', 'B')
+	font.bitmap = []byte{0xA0, 0x01}
+	font.xOffsets = []uint16{0, 3, 9}
+
+	sheet, metrics := ToSheet(font, nil)
+
+	c.Assert(sheet.Bounds(), check.Equals, image.Rect(0, 0, 16, 1))
+	c.Check(sheet.Pix, check.DeepEquals, []byte{1, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1})
+	c.Check(metrics.Monochrome, check.Equals, true)
+	c.Check(metrics.FirstCharacter, check.Equals, int('A'))
+	c.Check(metrics.LastCharacter, check.Equals, int('B'))
+	c.Check(metrics.XOffsets, check.DeepEquals, []int{0, 3, 9})
+}
+
+func (suite *SheetSuite) TestToSheetOfColorFontUsesGivenPalette(c *check.C) {
+	font := newSimpleFont(false, 2, 1, 'A', 'A')
+	font.bitmap = []byte{0x00, 0x05}
+	palette := color.Palette{color.Black, color.White}
+
+	sheet, _ := ToSheet(font, palette)
+
+	c.Check(sheet.Pix, check.DeepEquals, []byte{0x00, 0x05})
+	c.Check(sheet.Palette, check.DeepEquals, palette)
+}
+
+func (suite *SheetSuite) TestToSheetEncodesUnknownHeaderDataAsHex(c *check.C) {
+	font := newSimpleFont(false, 1, 1, 'A', 'A')
+	font.unknownHeaderData.Unknown0002[1] = 0xAB
+
+	_, metrics := ToSheet(font, nil)
+
+	c.Check(metrics.Unknown0002[:6], check.Equals, "00ab00")
+	c.Check(len(metrics.Unknown0002), check.Equals, 68)
+	c.Check(len(metrics.Unknown0028), check.Equals, 64)
+}
+
+func (suite *SheetSuite) TestSheetRoundTripIsLossless(c *check.C) {
+	for _, monochrome := range []bool{true, false} {
+		data := suite.aFont(monochrome)
+		loaded, err := Load(bytes.NewReader(data))
+		c.Assert(err, check.IsNil)
+
+		sheet, metrics := ToSheet(loaded, nil)
+		restored, err := FromSheet(sheet, metrics)
+		c.Assert(err, check.IsNil)
+
+		c.Check(Save(restored), check.DeepEquals, data, check.Commentf("monochrome: %v", monochrome))
+	}
+}
+
+func (suite *SheetSuite) TestFromSheetKeepsUnknownHeaderDataZeroIfNotGiven(c *check.C) {
+	sheet := image.NewPaletted(image.Rect(0, 0, 1, 1), nil)
+	font, err := FromSheet(sheet, &Metrics{FirstCharacter: 'A', LastCharacter: 'A', XOffsets: []int{0, 1}})
+
+	c.Assert(err, check.IsNil)
+	c.Check(font.(*simpleFont).UnknownHeaderData(), check.Equals, UnknownHeaderData{})
+}
+
+func (suite *SheetSuite) TestFromSheetReturnsErrorForInvalidMetrics(c *check.C) {
+	sheet := image.NewPaletted(image.Rect(0, 0, 12, 1), nil)
+	valid := func() *Metrics {
+		return &Metrics{FirstCharacter: 'A', LastCharacter: 'B', XOffsets: []int{0, 4, 12}}
+	}
+	tests := []struct {
+		modify   func(*Metrics)
+		expected string
+	}{
+		{func(m *Metrics) { m.FirstCharacter = 'C' }, "invalid character range.*"},
+		{func(m *Metrics) { m.Monochrome = true }, ".*not a multiple of 8"},
+		{func(m *Metrics) { m.XOffsets = []int{0, 12} }, "2 x offsets given, expected 3"},
+		{func(m *Metrics) { m.XOffsets = []int{0, 5, 4} }, "x offset 4 of glyph 2 .*"},
+		{func(m *Metrics) { m.XOffsets = []int{0, 4, 13} }, "x offset 13 of glyph 2 .*"},
+		{func(m *Metrics) { m.Unknown0002 = "zz" }, "invalid unknown0002.*"},
+		{func(m *Metrics) { m.Unknown0028 = "0011" }, "unknown0028 has 2 bytes, expected 32"},
+	}
+	for _, test := range tests {
+		metrics := valid()
+		test.modify(metrics)
+		_, err := FromSheet(sheet, metrics)
+		c.Check(err, check.ErrorMatches, test.expected)
+	}
+}
+
+func (suite *SheetSuite) aFont(monochrome bool) []byte {
+	var header Header
+	buf := bytes.NewBuffer(nil)
+	bitmap := []byte{0x81, 0x7E, 0x00, 0xFF, 0x3C, 0xC3}
+
+	if monochrome {
+		header.Type = Monochrome
+	} else {
+		header.Type = Color
+	}
+	for index := range header.Unknown0002 {
+		header.Unknown0002[index] = byte(index)
+	}
+	for index := range header.Unknown0028 {
+		header.Unknown0028[index] = byte(0xFF - index)
+	}
+	header.FirstCharacter = 32
+	header.LastCharacter = 33
+	header.XOffsetStart = uint32(HeaderSize)
+	header.BitmapStart = header.XOffsetStart + uint32(6)
+	header.Width = 2
+	header.Height = 3
+	binary.Write(buf, binary.LittleEndian, &header)
+	binary.Write(buf, binary.LittleEndian, []uint16{0, 1, 2})
+	buf.Write(bitmap)
+
+	return buf.Bytes()
+}
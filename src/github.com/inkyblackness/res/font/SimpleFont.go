@@ -10,8 +10,12 @@ type simpleFont struct {
 	firstCharacter int
 	lastCharacter  int
 	xOffsets       []uint16
+
+	unknownHeaderData UnknownHeaderData
 }
 
+var _ HeaderKeepingFont = &simpleFont{}
+
 func newSimpleFont(monochrome bool, width, height int, firstCharacter, lastCharacter int) *simpleFont {
 	return &simpleFont{
 		monochrome:     monochrome,
@@ -50,3 +54,7 @@ func (font *simpleFont) LastCharacter() int {
 func (font *simpleFont) GlyphXOffset(index int) int {
 	return int(font.xOffsets[index])
 }
+
+func (font *simpleFont) UnknownHeaderData() UnknownHeaderData {
+	return font.unknownHeaderData
+}
@@ -6,24 +6,51 @@ import (
 
 // BitmapHeader describes the header of an encoded bitmap
 type BitmapHeader struct {
-	Unknown0000      [4]byte
-	Type             BitmapType
+	// Unknown0000 is the place of the pointer to the pixel data while the bitmap is
+	// loaded. It has no meaning in files, where the pixel data follows the header.
+	Unknown0000 [4]byte
+	// Type describes how the pixel data is stored.
+	Type BitmapType
+	// TransparencyFlag is a set of flags. Bit 0 marks palette index 0x00 as transparent.
 	TransparencyFlag int16
 
-	Width        uint16
-	Height       uint16
-	Stride       uint16
-	WidthFactor  byte
+	Width  uint16
+	Height uint16
+	// Stride is the amount of bytes per row of pixel data.
+	Stride uint16
+	// WidthFactor is the binary logarithm of the width, rounded down.
+	WidthFactor byte
+	// HeightFactor is the binary logarithm of the height, rounded down.
 	HeightFactor byte
 	HotspotBox   [4]uint16
 
 	PaletteOffset int32
 }
 
+// Transparent returns true if palette index 0x00 is marked as transparent.
+func (header *BitmapHeader) Transparent() bool {
+	return (header.TransparencyFlag & 1) != 0
+}
+
+// PowerOfTwoSize returns true if width and height are the powers of two the
+// factors specify.
+func (header *BitmapHeader) PowerOfTwoSize() bool {
+	return (header.Width == (1 << header.WidthFactor)) && (header.Height == (1 << header.HeightFactor))
+}
+
 func (header *BitmapHeader) String() (result string) {
-	result += fmt.Sprintf("Type: %v, %dx%d\n", header.Type, header.Width, header.Height)
-	result += fmt.Sprintf("Transparency: %v\n", header.TransparencyFlag != 0)
+	result += fmt.Sprintf("Type: %v, %dx%d (2^%d x 2^%d)\n", header.Type, header.Width, header.Height,
+		header.WidthFactor, header.HeightFactor)
+	result += fmt.Sprintf("Transparency: %v\n", header.Transparent())
 	result += fmt.Sprintf("%d,%d | %d,%d\n", header.HotspotBox[0], header.HotspotBox[1], header.HotspotBox[2], header.HotspotBox[3])
 	result += fmt.Sprintf("PaletteOffset: %d", header.PaletteOffset)
 	return
 }
+
+// rowBytes returns the minimum amount of bytes a row of pixel data requires.
+func (header *BitmapHeader) rowBytes() int {
+	if header.Type.Encoding() == MonochromeBitmap {
+		return (header.Type.BitOffset() + int(header.Width) + 7) / 8
+	}
+	return int(header.Width)
+}
@@ -2,22 +2,60 @@ package image
 
 import "fmt"
 
+// BitmapType describes how the pixel data of a bitmap is stored.
+// The lower byte identifies the encoding. For monochrome bitmaps, the upper
+// byte is the bit offset the pixel data starts at; it is zero for all others.
 type BitmapType int16
 
 const (
+	// MonochromeBitmap stores one bit per pixel, starting with the most significant bit.
+	MonochromeBitmap BitmapType = 0x0001
+	// UncompressedBitmap stores one palette index per pixel.
 	UncompressedBitmap BitmapType = 0x0002
-	CompressedBitmap   BitmapType = 0x0004
+	// CompressedBitmap stores one palette index per pixel, run-length encoded.
+	CompressedBitmap BitmapType = 0x0004
+	// TranslucentBitmap stores one palette index per pixel like UncompressedBitmap.
+	// Indices that have a translucency table assigned are drawn translucent.
+	TranslucentBitmap BitmapType = 0x0005
 )
 
+const bitmapEncodingMask BitmapType = 0x00FF
+
 func (bmpType BitmapType) String() (result string) {
 	switch bmpType {
+	case MonochromeBitmap:
+		result = "Monochrome"
 	case UncompressedBitmap:
 		result = "Uncompressed"
 	case CompressedBitmap:
 		result = "Compressed"
+	case TranslucentBitmap:
+		result = "Translucent"
 	default:
 		result = fmt.Sprintf("Unknown (0x%04X)", int16(bmpType))
 	}
 
 	return
 }
+
+// Encoding returns the type without the bit offset of monochrome bitmaps.
+func (bmpType BitmapType) Encoding() BitmapType {
+	return bmpType & bitmapEncodingMask
+}
+
+// BitOffset returns the bit offset of monochrome bitmaps.
+func (bmpType BitmapType) BitOffset() int {
+	return int(uint16(bmpType) >> 8)
+}
+
+// Supported returns true for types that can be read and written.
+func (bmpType BitmapType) Supported() bool {
+	switch bmpType.Encoding() {
+	case MonochromeBitmap:
+		return true
+	case UncompressedBitmap, CompressedBitmap, TranslucentBitmap:
+		return bmpType.BitOffset() == 0
+	default:
+		return false
+	}
+}
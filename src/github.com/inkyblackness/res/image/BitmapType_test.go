@@ -14,3 +14,23 @@ func (suite *BitmapTypeSuite) TestString(c *check.C) {
 	c.Check(CompressedBitmap.String(), check.Equals, "Compressed")
 	c.Check(BitmapType(0x0123).String(), check.Equals, "Unknown (0x0123)")
 }
+
+func (suite *BitmapTypeSuite) TestStringOfAdditionalTypes(c *check.C) {
+	c.Check(MonochromeBitmap.String(), check.Equals, "Monochrome")
+	c.Check(TranslucentBitmap.String(), check.Equals, "Translucent")
+}
+
+func (suite *BitmapTypeSuite) TestSupported(c *check.C) {
+	c.Check(MonochromeBitmap.Supported(), check.Equals, true)
+	c.Check(UncompressedBitmap.Supported(), check.Equals, true)
+	c.Check(CompressedBitmap.Supported(), check.Equals, true)
+	c.Check(TranslucentBitmap.Supported(), check.Equals, true)
+	c.Check(BitmapType(0x0301).Supported(), check.Equals, true)
+	c.Check(BitmapType(0x0003).Supported(), check.Equals, false)
+	c.Check(BitmapType(0x0102).Supported(), check.Equals, false)
+}
+
+func (suite *BitmapTypeSuite) TestEncodingAndBitOffset(c *check.C) {
+	c.Check(BitmapType(0x0301).Encoding(), check.Equals, MonochromeBitmap)
+	c.Check(BitmapType(0x0301).BitOffset(), check.Equals, 3)
+}
@@ -0,0 +1,175 @@
+package image
+
+import (
+	"image"
+	"image/color"
+	"sort"
+)
+
+type weightedColor struct {
+	channels [3]int32
+	count    int64
+}
+
+type colorBox struct {
+	colors []weightedColor
+	count  int64
+	error  int64
+}
+
+func newColorBox(colors []weightedColor) *colorBox {
+	box := &colorBox{colors: colors}
+	mean := box.mean()
+	for _, entry := range colors {
+		box.count += entry.count
+		for channel, value := range entry.channels {
+			diff := int64(value - mean[channel])
+			box.error += diff * diff * entry.count
+		}
+	}
+	return box
+}
+
+func (box *colorBox) mean() (result [3]int32) {
+	var sums [3]int64
+	var count int64
+	for _, entry := range box.colors {
+		count += entry.count
+		for channel, value := range entry.channels {
+			sums[channel] += int64(value) * entry.count
+		}
+	}
+	for channel, sum := range sums {
+		result[channel] = int32((sum + count/2) / count)
+	}
+	return
+}
+
+func (box *colorBox) longestChannel() (longest int) {
+	longestRange := int32(-1)
+	for channel := 0; channel < 3; channel++ {
+		min, max := int32(0xFF), int32(0)
+		for _, entry := range box.colors {
+			if entry.channels[channel] < min {
+				min = entry.channels[channel]
+			}
+			if entry.channels[channel] > max {
+				max = entry.channels[channel]
+			}
+		}
+		if (max - min) > longestRange {
+			longest = channel
+			longestRange = max - min
+		}
+	}
+	return
+}
+
+// split divides the box at the weighted median of its longest channel.
+func (box *colorBox) split() (*colorBox, *colorBox) {
+	channel := box.longestChannel()
+	sort.Slice(box.colors, func(a, b int) bool {
+		return box.colors[a].channels[channel] < box.colors[b].channels[channel]
+	})
+	splitIndex := 1
+	var lowerCount int64
+	for index := 0; index < len(box.colors)-1; index++ {
+		lowerCount += box.colors[index].count
+		splitIndex = index + 1
+		if lowerCount*2 >= box.count {
+			break
+		}
+	}
+	return newColorBox(box.colors[:splitIndex]), newColorBox(box.colors[splitIndex:])
+}
+
+// BuildPalette derives a palette of ColorsPerPixel entries for the given image,
+// suitable as the private palette of a bitmap. The colors are chosen with the
+// median cut algorithm, based on all pixels that are not mostly transparent.
+//
+// The entries of the given locked ranges are copied from the base palette and
+// are not used for the colors of the image. Index 0 is the transparent color,
+// unless it is locked as well. The base palette may be nil if no range is locked.
+func BuildPalette(img image.Image, base color.Palette, locked ...IndexRange) color.Palette {
+	palette := make(color.Palette, ColorsPerPixel)
+	var free []int
+	for index := range palette {
+		if isLockedIndex(index, locked) {
+			palette[index] = color.NRGBA{A: 0xFF}
+			if index < len(base) {
+				palette[index] = base[index]
+			}
+		} else if index == TransparentIndex {
+			palette[index] = color.NRGBA{}
+		} else {
+			palette[index] = color.NRGBA{A: 0xFF}
+			free = append(free, index)
+		}
+	}
+
+	for index, mean := range medianCut(histogramOf(img), len(free)) {
+		palette[free[index]] = color.NRGBA{R: uint8(mean[0]), G: uint8(mean[1]), B: uint8(mean[2]), A: 0xFF}
+	}
+
+	return palette
+}
+
+func isLockedIndex(index int, locked []IndexRange) bool {
+	for _, indexRange := range locked {
+		if indexRange.Contains(index) {
+			return true
+		}
+	}
+	return false
+}
+
+func histogramOf(img image.Image) []weightedColor {
+	counts := make(map[[3]int32]int64)
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			pixel := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if pixel.A >= 0x80 {
+				counts[[3]int32{int32(pixel.R), int32(pixel.G), int32(pixel.B)}]++
+			}
+		}
+	}
+	colors := make([]weightedColor, 0, len(counts))
+	for channels, count := range counts {
+		colors = append(colors, weightedColor{channels: channels, count: count})
+	}
+	sort.Slice(colors, func(a, b int) bool {
+		return colors[a].channels[0] < colors[b].channels[0] ||
+			(colors[a].channels[0] == colors[b].channels[0] && (colors[a].channels[1] < colors[b].channels[1] ||
+				(colors[a].channels[1] == colors[b].channels[1] && colors[a].channels[2] < colors[b].channels[2])))
+	})
+	return colors
+}
+
+// medianCut returns at most limit representative colors of the given colors.
+// The box with the largest squared error is split until the limit is reached.
+func medianCut(colors []weightedColor, limit int) [][3]int32 {
+	var result [][3]int32
+	if (len(colors) == 0) || (limit == 0) {
+		return result
+	}
+	boxes := []*colorBox{newColorBox(colors)}
+	for len(boxes) < limit {
+		worst := -1
+		for index, box := range boxes {
+			if (len(box.colors) > 1) && ((worst < 0) || (box.error > boxes[worst].error)) {
+				worst = index
+			}
+		}
+		if worst < 0 {
+			break
+		}
+		lower, upper := boxes[worst].split()
+		boxes[worst] = lower
+		boxes = append(boxes, upper)
+	}
+	for _, box := range boxes {
+		result = append(result, box.mean())
+	}
+	return result
+}
@@ -0,0 +1,78 @@
+package image
+
+import (
+	"image"
+	"image/color"
+
+	check "gopkg.in/check.v1"
+)
+
+type BuildPaletteSuite struct {
+}
+
+var _ = check.Suite(&BuildPaletteSuite{})
+
+func (suite *BuildPaletteSuite) gradientImage() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x * 4), G: uint8(y * 4), B: 0x80, A: 0xFF})
+		}
+	}
+	return img
+}
+
+func (suite *BuildPaletteSuite) TestPaletteHasTransparentFirstEntry(c *check.C) {
+	palette := BuildPalette(suite.gradientImage(), nil)
+
+	c.Assert(len(palette), check.Equals, ColorsPerPixel)
+	c.Check(palette[0], check.Equals, color.NRGBA{})
+}
+
+func (suite *BuildPaletteSuite) TestPaletteContainsAllColorsOfSimpleImages(c *check.C) {
+	img := image.NewNRGBA(image.Rect(0, 0, 3, 1))
+	img.Set(0, 0, color.NRGBA{R: 0x10, G: 0x20, B: 0x30, A: 0xFF})
+	img.Set(1, 0, color.NRGBA{R: 0xA0, G: 0x00, B: 0x00, A: 0xFF})
+	img.Set(2, 0, color.NRGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0x00})
+
+	palette := BuildPalette(img, nil)
+
+	c.Check(palette[1:3], check.DeepEquals, color.Palette{
+		color.NRGBA{R: 0x10, G: 0x20, B: 0x30, A: 0xFF},
+		color.NRGBA{R: 0xA0, G: 0x00, B: 0x00, A: 0xFF}})
+}
+
+func (suite *BuildPaletteSuite) TestLockedEntriesAreCopiedFromBase(c *check.C) {
+	base := make(color.Palette, ColorsPerPixel)
+	for index := range base {
+		base[index] = color.NRGBA{R: uint8(index), G: 0x01, B: 0x02, A: 0xFF}
+	}
+
+	palette := BuildPalette(suite.gradientImage(), base, IndexRange{First: 0, Count: 1}, IndexRange{First: 0x10, Count: 4})
+
+	c.Check(palette[0], check.Equals, base[0])
+	c.Check(palette[0x10:0x14], check.DeepEquals, base[0x10:0x14])
+	c.Check(palette[0x14], check.Not(check.Equals), base[0x14])
+}
+
+func (suite *BuildPaletteSuite) TestPaletteApproximatesManyColors(c *check.C) {
+	img := suite.gradientImage()
+	palette := BuildPalette(img, nil)
+	quantizer, _ := NewQuantizer(palette, NoDithering)
+
+	result := quantizer.Quantize(img)
+
+	maxDistance := 0
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			expected := img.NRGBAAt(x, y)
+			actual := palette[result.ColorIndexAt(x, y)].(color.NRGBA)
+			dR := int(expected.R) - int(actual.R)
+			dG := int(expected.G) - int(actual.G)
+			if distance := dR*dR + dG*dG; distance > maxDistance {
+				maxDistance = distance
+			}
+		}
+	}
+	c.Check(maxDistance <= 16*16, check.Equals, true, check.Commentf("max distance %v", maxDistance))
+}
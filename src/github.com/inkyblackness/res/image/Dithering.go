@@ -0,0 +1,41 @@
+package image
+
+import "fmt"
+
+// Dithering specifies how a Quantizer distributes the error between the
+// original color and the chosen palette color.
+type Dithering int
+
+const (
+	// NoDithering maps each pixel to the nearest palette color.
+	NoDithering Dithering = iota
+	// FloydSteinbergDithering diffuses the error to the neighbouring pixels.
+	FloydSteinbergDithering
+	// OrderedDithering offsets each pixel by a threshold of a 4x4 Bayer matrix.
+	OrderedDithering
+)
+
+var ditheringNames = map[Dithering]string{
+	NoDithering:             "none",
+	FloydSteinbergDithering: "floyd-steinberg",
+	OrderedDithering:        "ordered"}
+
+func (dithering Dithering) String() (result string) {
+	if name, known := ditheringNames[dithering]; known {
+		result = name
+	} else {
+		result = fmt.Sprintf("Unknown (%d)", int(dithering))
+	}
+
+	return
+}
+
+// ParseDithering returns the dithering with given name, as returned by String().
+func ParseDithering(name string) (Dithering, error) {
+	for dithering, knownName := range ditheringNames {
+		if knownName == name {
+			return dithering, nil
+		}
+	}
+	return NoDithering, fmt.Errorf("unknown dithering %q", name)
+}
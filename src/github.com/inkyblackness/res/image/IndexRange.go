@@ -0,0 +1,14 @@
+package image
+
+// IndexRange describes a consecutive range of palette indices.
+type IndexRange struct {
+	// First is the first index of the range.
+	First int
+	// Count is the number of indices in the range.
+	Count int
+}
+
+// Contains returns true if the given index is within the range.
+func (indexRange IndexRange) Contains(index int) bool {
+	return (index >= indexRange.First) && (index < (indexRange.First + indexRange.Count))
+}
@@ -34,6 +34,16 @@ func (bmp *MemoryBitmap) Compressed() bool {
 	return bmp.header.Type == CompressedBitmap
 }
 
+// Type returns the type the bitmap was stored with.
+func (bmp *MemoryBitmap) Type() BitmapType {
+	return bmp.header.Type
+}
+
+// Transparent returns whether palette index 0x00 is marked to be transparent.
+func (bmp *MemoryBitmap) Transparent() bool {
+	return bmp.header.Transparent()
+}
+
 // ImageWidth returns the width of the bitmap in pixel.
 func (bmp *MemoryBitmap) ImageWidth() uint16 {
 	return bmp.header.Width
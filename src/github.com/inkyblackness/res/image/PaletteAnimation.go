@@ -0,0 +1,50 @@
+package image
+
+import (
+	"image/color"
+	"time"
+)
+
+// PaletteAnimation describes all rotating ranges of a palette.
+// The colors of each range move towards higher indices, with the color of the
+// last entry wrapping around to the first entry of the range.
+type PaletteAnimation []PaletteCycle
+
+// PaletteAt returns a copy of the base palette as it is shown after given time.
+func (animation PaletteAnimation) PaletteAt(base color.Palette, elapsed time.Duration) color.Palette {
+	result := make(color.Palette, len(base))
+	copy(result, base)
+	for _, cycle := range animation {
+		first := cycle.Range.First
+		count := cycle.Range.Count
+		if (first < 0) || (count <= 0) || ((first + count) > len(base)) {
+			continue
+		}
+		shift := cycle.Shift(elapsed)
+		for offset := 0; offset < count; offset++ {
+			result[first+(offset+shift)%count] = base[first+offset]
+		}
+	}
+	return result
+}
+
+// Changed returns true if the palette shown after current time differs in
+// any rotation from the one shown after previous time.
+func (animation PaletteAnimation) Changed(previous, current time.Duration) bool {
+	for _, cycle := range animation {
+		if cycle.Shift(previous) != cycle.Shift(current) {
+			return true
+		}
+	}
+	return false
+}
+
+// Ranges returns the index ranges of all cycles. Static images should not use
+// these entries, see NewQuantizer.
+func (animation PaletteAnimation) Ranges() []IndexRange {
+	ranges := make([]IndexRange, len(animation))
+	for index, cycle := range animation {
+		ranges[index] = cycle.Range
+	}
+	return ranges
+}
@@ -0,0 +1,74 @@
+package image
+
+import (
+	"image/color"
+	"time"
+
+	check "gopkg.in/check.v1"
+)
+
+type PaletteAnimationSuite struct {
+	base      color.Palette
+	animation PaletteAnimation
+}
+
+var _ = check.Suite(&PaletteAnimationSuite{})
+
+func (suite *PaletteAnimationSuite) SetUpTest(c *check.C) {
+	suite.base = make(color.Palette, 8)
+	for index := range suite.base {
+		suite.base[index] = color.NRGBA{R: uint8(index), A: 0xFF}
+	}
+	suite.animation = PaletteAnimation{
+		{Range: IndexRange{First: 1, Count: 3}, Interval: 100 * time.Millisecond},
+		{Range: IndexRange{First: 5, Count: 2}, Interval: 250 * time.Millisecond}}
+}
+
+func (suite *PaletteAnimationSuite) redOf(palette color.Palette) []uint8 {
+	result := make([]uint8, len(palette))
+	for index, entry := range palette {
+		result[index] = entry.(color.NRGBA).R
+	}
+	return result
+}
+
+func (suite *PaletteAnimationSuite) TestPaletteAtStartIsBase(c *check.C) {
+	palette := suite.animation.PaletteAt(suite.base, 0)
+
+	c.Check(palette, check.DeepEquals, suite.base)
+}
+
+func (suite *PaletteAnimationSuite) TestPaletteAtRotatesRanges(c *check.C) {
+	palette := suite.animation.PaletteAt(suite.base, 260*time.Millisecond)
+
+	c.Check(suite.redOf(palette), check.DeepEquals, []uint8{0, 2, 3, 1, 4, 6, 5, 7})
+}
+
+func (suite *PaletteAnimationSuite) TestPaletteAtWrapsAround(c *check.C) {
+	palette := suite.animation.PaletteAt(suite.base, 300*time.Millisecond)
+
+	c.Check(suite.redOf(palette)[1:4], check.DeepEquals, []uint8{1, 2, 3})
+}
+
+func (suite *PaletteAnimationSuite) TestPaletteAtDoesNotModifyBase(c *check.C) {
+	suite.animation.PaletteAt(suite.base, 100*time.Millisecond)
+
+	c.Check(suite.redOf(suite.base), check.DeepEquals, []uint8{0, 1, 2, 3, 4, 5, 6, 7})
+}
+
+func (suite *PaletteAnimationSuite) TestPaletteAtIgnoresRangesOutsidePalette(c *check.C) {
+	animation := PaletteAnimation{{Range: IndexRange{First: 6, Count: 4}, Interval: time.Millisecond}}
+
+	palette := animation.PaletteAt(suite.base, 10*time.Millisecond)
+
+	c.Check(palette, check.DeepEquals, suite.base)
+}
+
+func (suite *PaletteAnimationSuite) TestChanged(c *check.C) {
+	c.Check(suite.animation.Changed(10*time.Millisecond, 90*time.Millisecond), check.Equals, false)
+	c.Check(suite.animation.Changed(90*time.Millisecond, 110*time.Millisecond), check.Equals, true)
+}
+
+func (suite *PaletteAnimationSuite) TestRanges(c *check.C) {
+	c.Check(suite.animation.Ranges(), check.DeepEquals, []IndexRange{{First: 1, Count: 3}, {First: 5, Count: 2}})
+}
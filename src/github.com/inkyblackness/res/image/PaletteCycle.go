@@ -0,0 +1,20 @@
+package image
+
+import "time"
+
+// PaletteCycle describes a range of palette entries that rotate while the game runs.
+type PaletteCycle struct {
+	// Range is the range of rotating entries.
+	Range IndexRange
+	// Interval is the time after which the colors move on by one entry.
+	Interval time.Duration
+}
+
+// Shift returns by how many entries the colors have moved after given time.
+// The result is within the range [0, Range.Count).
+func (cycle PaletteCycle) Shift(elapsed time.Duration) int {
+	if (cycle.Interval <= 0) || (cycle.Range.Count <= 0) || (elapsed < 0) {
+		return 0
+	}
+	return int((elapsed / cycle.Interval) % time.Duration(cycle.Range.Count))
+}
@@ -0,0 +1,160 @@
+package image
+
+import (
+	"errors"
+	"image"
+	"image/color"
+)
+
+// TransparentIndex is the palette index of transparent pixels.
+const TransparentIndex = 0
+
+// Quantizer maps the colors of arbitrary images to the indices of a fixed palette.
+//
+// Pixels that are more than half transparent are mapped to TransparentIndex.
+// All other pixels are mapped to the palette indices that are not reserved.
+// The transparent index is always reserved, as are ranges of the palette that
+// are animated during play and must therefore not be used for static images.
+//
+// A Quantizer is safe for concurrent use.
+type Quantizer struct {
+	colors     []color.NRGBA
+	palette    color.Palette
+	candidates []int
+	dithering  Dithering
+}
+
+var errNoUsableColors = errors.New("palette has no usable colors")
+
+// bayerMatrix contains the thresholds for ordered dithering.
+var bayerMatrix = [4][4]int32{
+	{0, 8, 2, 10},
+	{12, 4, 14, 6},
+	{3, 11, 1, 9},
+	{15, 7, 13, 5}}
+
+// orderedDitheringSpread is the range of offsets applied with ordered dithering.
+const orderedDitheringSpread = 32
+
+// NewQuantizer returns a quantizer for the given palette, using given dithering.
+// Opaque pixels are not mapped to any of the reserved indices.
+// An error is returned if the palette has no usable color left.
+func NewQuantizer(palette color.Palette, dithering Dithering, reserved ...IndexRange) (*Quantizer, error) {
+	quantizer := &Quantizer{
+		colors:    make([]color.NRGBA, len(palette)),
+		palette:   palette,
+		dithering: dithering}
+
+	for index, entry := range palette {
+		quantizer.colors[index] = color.NRGBAModel.Convert(entry).(color.NRGBA)
+		if !isReservedIndex(index, reserved) {
+			quantizer.candidates = append(quantizer.candidates, index)
+		}
+	}
+	if len(quantizer.candidates) == 0 {
+		return nil, errNoUsableColors
+	}
+
+	return quantizer, nil
+}
+
+func isReservedIndex(index int, reserved []IndexRange) bool {
+	if index == TransparentIndex {
+		return true
+	}
+	for _, indexRange := range reserved {
+		if indexRange.Contains(index) {
+			return true
+		}
+	}
+	return false
+}
+
+// Palette returns the palette the quantizer maps to.
+func (quantizer *Quantizer) Palette() color.Palette {
+	return quantizer.palette
+}
+
+// Quantize returns a paletted copy of the given image, using the palette of the quantizer.
+func (quantizer *Quantizer) Quantize(img image.Image) *image.Paletted {
+	bounds := img.Bounds()
+	result := image.NewPaletted(bounds, quantizer.palette)
+	width := bounds.Dx()
+	currentErrors := make([][3]int32, width+2)
+	nextErrors := make([][3]int32, width+2)
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			column := x - bounds.Min.X
+			pixel := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if pixel.A < 0x80 {
+				result.SetColorIndex(x, y, TransparentIndex)
+				continue
+			}
+			target := [3]int32{int32(pixel.R), int32(pixel.G), int32(pixel.B)}
+
+			switch quantizer.dithering {
+			case FloydSteinbergDithering:
+				for channel := range target {
+					target[channel] = clampChannel(target[channel] + currentErrors[column+1][channel]/16)
+				}
+			case OrderedDithering:
+				offset := (bayerMatrix[y&3][x&3]*2+1)*orderedDitheringSpread/32 - orderedDitheringSpread/2
+				for channel := range target {
+					target[channel] = clampChannel(target[channel] + offset)
+				}
+			}
+
+			index := quantizer.nearestIndex(target)
+			result.SetColorIndex(x, y, uint8(index))
+
+			if quantizer.dithering == FloydSteinbergDithering {
+				chosen := quantizer.colors[index]
+				diff := [3]int32{
+					target[0] - int32(chosen.R),
+					target[1] - int32(chosen.G),
+					target[2] - int32(chosen.B)}
+				for channel, value := range diff {
+					currentErrors[column+2][channel] += value * 7
+					nextErrors[column][channel] += value * 3
+					nextErrors[column+1][channel] += value * 5
+					nextErrors[column+2][channel] += value * 1
+				}
+			}
+		}
+		currentErrors, nextErrors = nextErrors, currentErrors
+		for column := range nextErrors {
+			nextErrors[column] = [3]int32{}
+		}
+	}
+
+	return result
+}
+
+func (quantizer *Quantizer) nearestIndex(target [3]int32) int {
+	bestIndex := quantizer.candidates[0]
+	bestDistance := int32(-1)
+
+	for _, index := range quantizer.candidates {
+		entry := quantizer.colors[index]
+		dR := target[0] - int32(entry.R)
+		dG := target[1] - int32(entry.G)
+		dB := target[2] - int32(entry.B)
+		distance := dR*dR + dG*dG + dB*dB
+		if (bestDistance < 0) || (distance < bestDistance) {
+			bestIndex = index
+			bestDistance = distance
+		}
+	}
+
+	return bestIndex
+}
+
+func clampChannel(value int32) int32 {
+	if value < 0 {
+		return 0
+	} else if value > 0xFF {
+		return 0xFF
+	}
+	return value
+}
@@ -0,0 +1,120 @@
+package image
+
+import (
+	"image"
+	"image/color"
+
+	check "gopkg.in/check.v1"
+)
+
+type QuantizerSuite struct {
+	palette color.Palette
+}
+
+var _ = check.Suite(&QuantizerSuite{})
+
+func (suite *QuantizerSuite) SetUpTest(c *check.C) {
+	suite.palette = make(color.Palette, 256)
+	for index := range suite.palette {
+		suite.palette[index] = color.NRGBA{R: 0, G: 0, B: 0, A: 0xFF}
+	}
+	suite.palette[0] = color.NRGBA{R: 0, G: 0, B: 0, A: 0}
+	suite.palette[1] = color.NRGBA{R: 0x00, G: 0x00, B: 0x00, A: 0xFF}
+	suite.palette[2] = color.NRGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}
+	suite.palette[3] = color.NRGBA{R: 0xFF, G: 0x00, B: 0x00, A: 0xFF}
+	suite.palette[4] = color.NRGBA{R: 0xF0, G: 0x10, B: 0x10, A: 0xFF}
+}
+
+func (suite *QuantizerSuite) uniformImage(width, height int, col color.Color) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, col)
+		}
+	}
+	return img
+}
+
+func (suite *QuantizerSuite) TestNewQuantizerReturnsErrorWithoutUsableColors(c *check.C) {
+	_, err := NewQuantizer(suite.palette[0:2], NoDithering, IndexRange{First: 1, Count: 1})
+
+	c.Check(err, check.NotNil)
+}
+
+func (suite *QuantizerSuite) TestQuantizeMapsToNearestColor(c *check.C) {
+	quantizer, _ := NewQuantizer(suite.palette, NoDithering)
+	img := suite.uniformImage(2, 1, color.NRGBA{R: 0xF8, G: 0x02, B: 0x02, A: 0xFF})
+	img.Set(1, 0, color.NRGBA{R: 0xE0, G: 0xE0, B: 0xE0, A: 0xFF})
+
+	result := quantizer.Quantize(img)
+
+	c.Check(result.Pix, check.DeepEquals, []byte{3, 2})
+}
+
+func (suite *QuantizerSuite) TestQuantizeMapsTransparentPixelsToIndexZero(c *check.C) {
+	quantizer, _ := NewQuantizer(suite.palette, FloydSteinbergDithering)
+	img := suite.uniformImage(2, 1, color.NRGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0x10})
+	img.Set(1, 0, color.NRGBA{R: 0x00, G: 0x00, B: 0x00, A: 0xFF})
+
+	result := quantizer.Quantize(img)
+
+	c.Check(result.Pix, check.DeepEquals, []byte{0, 1})
+}
+
+func (suite *QuantizerSuite) TestQuantizeAvoidsReservedIndices(c *check.C) {
+	quantizer, _ := NewQuantizer(suite.palette, NoDithering, IndexRange{First: 3, Count: 1})
+	img := suite.uniformImage(1, 1, color.NRGBA{R: 0xFF, G: 0x00, B: 0x00, A: 0xFF})
+
+	result := quantizer.Quantize(img)
+
+	c.Check(result.Pix, check.DeepEquals, []byte{4})
+}
+
+func (suite *QuantizerSuite) TestQuantizeKeepsBoundsAndPalette(c *check.C) {
+	quantizer, _ := NewQuantizer(suite.palette, OrderedDithering)
+	img := image.NewRGBA(image.Rect(10, 20, 13, 22))
+
+	result := quantizer.Quantize(img)
+
+	c.Check(result.Bounds(), check.Equals, img.Bounds())
+	c.Check(len(result.Palette), check.Equals, len(suite.palette))
+}
+
+func (suite *QuantizerSuite) averageBrightness(img *image.Paletted) float64 {
+	sum := 0
+	for _, index := range img.Pix {
+		r, _, _, _ := img.Palette[index].RGBA()
+		sum += int(r >> 8)
+	}
+	return float64(sum) / float64(len(img.Pix))
+}
+
+func (suite *QuantizerSuite) TestFloydSteinbergDitheringPreservesAverageColor(c *check.C) {
+	quantizer, _ := NewQuantizer(suite.palette[0:3], FloydSteinbergDithering)
+	img := suite.uniformImage(16, 16, color.NRGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xFF})
+
+	result := quantizer.Quantize(img)
+
+	c.Check(suite.averageBrightness(result) > 0x70, check.Equals, true)
+	c.Check(suite.averageBrightness(result) < 0x90, check.Equals, true)
+}
+
+func (suite *QuantizerSuite) TestOrderedDitheringMixesColors(c *check.C) {
+	quantizer, _ := NewQuantizer(suite.palette[0:3], OrderedDithering)
+	img := suite.uniformImage(4, 4, color.NRGBA{R: 0x7C, G: 0x7C, B: 0x7C, A: 0xFF})
+
+	result := quantizer.Quantize(img)
+
+	c.Check(suite.averageBrightness(result) > 0x40, check.Equals, true)
+	c.Check(suite.averageBrightness(result) < 0xC0, check.Equals, true)
+}
+
+func (suite *QuantizerSuite) TestDitheringNamesCanBeParsed(c *check.C) {
+	for _, dithering := range []Dithering{NoDithering, FloydSteinbergDithering, OrderedDithering} {
+		parsed, err := ParseDithering(dithering.String())
+		c.Check(err, check.IsNil)
+		c.Check(parsed, check.Equals, dithering)
+	}
+	_, err := ParseDithering("unknown")
+	c.Check(err, check.NotNil)
+}
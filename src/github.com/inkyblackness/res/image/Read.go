@@ -9,7 +9,10 @@ import (
 	"github.com/inkyblackness/res/compress/rle"
 )
 
-// Read tries to extract a bitmap from the given source
+// Read tries to extract a bitmap from the given source.
+// Bitmaps of unsupported types are reported with an UnsupportedTypeError.
+// The pixel data of the returned bitmap has one palette index per pixel,
+// regardless of the stored type.
 func Read(source io.ReadSeeker) (bmp Bitmap, err error) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -25,7 +28,16 @@ func Read(source io.ReadSeeker) (bmp Bitmap, err error) {
 	var data []byte
 	var palette color.Palette = nil
 
-	binary.Read(source, binary.LittleEndian, &header)
+	err = binary.Read(source, binary.LittleEndian, &header)
+	if err != nil {
+		return
+	}
+	if !header.Type.Supported() {
+		return nil, UnsupportedTypeError{Type: header.Type}
+	}
+	if int(header.Stride) < header.rowBytes() {
+		return nil, fmt.Errorf("stride %d is too small for width %d", header.Stride, header.Width)
+	}
 	data = make([]byte, int(header.Height)*int(header.Stride))
 	if header.Type == CompressedBitmap {
 		err = rle.Decompress(source, data)
@@ -40,8 +52,30 @@ func Read(source io.ReadSeeker) (bmp Bitmap, err error) {
 	}
 
 	if err == nil {
+		if header.Type.Encoding() == MonochromeBitmap {
+			data = expandMonochrome(&header, data)
+		}
 		bmp = NewMemoryBitmap(&header, data, palette)
 	}
 
 	return
 }
+
+// expandMonochrome converts the bits of a monochrome bitmap to palette indices
+// 0x00 and 0x01, and modifies the header to describe the expanded data.
+func expandMonochrome(header *BitmapHeader, packed []byte) []byte {
+	width := int(header.Width)
+	bitOffset := header.Type.BitOffset()
+	data := make([]byte, width*int(header.Height))
+
+	for row := 0; row < int(header.Height); row++ {
+		packedRow := packed[row*int(header.Stride):]
+		for column := 0; column < width; column++ {
+			bit := bitOffset + column
+			data[row*width+column] = (packedRow[bit/8] >> uint(7-bit%8)) & 1
+		}
+	}
+	header.Stride = header.Width
+
+	return data
+}
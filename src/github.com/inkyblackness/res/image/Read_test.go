@@ -49,6 +49,45 @@ func (suite *ReadSuite) TestReadWithPrivatePaletteReturnsPalette(c *check.C) {
 	c.Check(bmp.Palette(), check.NotNil)
 }
 
+func (suite *ReadSuite) TestReadReturnsTypedErrorForUnsupportedType(c *check.C) {
+	data := suite.getTestData(BitmapType(0x0003), []byte{0xAA}, false)
+	_, err := Read(bytes.NewReader(data))
+
+	c.Check(err, check.Equals, UnsupportedTypeError{Type: BitmapType(0x0003)})
+}
+
+func (suite *ReadSuite) TestReadOfTranslucentDataReturnsBitmap(c *check.C) {
+	data := suite.getTestData(TranslucentBitmap, []byte{0xCC}, false)
+	bmp, err := Read(bytes.NewReader(data))
+
+	c.Assert(err, check.IsNil)
+	c.Check(bmp.Row(0), check.DeepEquals, []byte{0xCC})
+}
+
+func (suite *ReadSuite) TestReadOfMonochromeDataExpandsBits(c *check.C) {
+	header := BitmapHeader{Type: BitmapType(0x0201), Width: 9, Height: 2, Stride: 2}
+	buf := bytes.NewBuffer(nil)
+	binary.Write(buf, binary.LittleEndian, &header)
+	buf.Write([]byte{0x2A, 0xA0, 0x3F, 0xE0})
+
+	bmp, err := Read(bytes.NewReader(buf.Bytes()))
+
+	c.Assert(err, check.IsNil)
+	c.Check(bmp.Row(0), check.DeepEquals, []byte{1, 0, 1, 0, 1, 0, 1, 0, 1})
+	c.Check(bmp.Row(1), check.DeepEquals, []byte{1, 1, 1, 1, 1, 1, 1, 1, 1})
+}
+
+func (suite *ReadSuite) TestReadReturnsErrorForTooSmallStride(c *check.C) {
+	header := BitmapHeader{Type: UncompressedBitmap, Width: 4, Height: 1, Stride: 2}
+	buf := bytes.NewBuffer(nil)
+	binary.Write(buf, binary.LittleEndian, &header)
+	buf.Write([]byte{0x01, 0x02, 0x03, 0x04})
+
+	_, err := Read(bytes.NewReader(buf.Bytes()))
+
+	c.Check(err, check.NotNil)
+}
+
 func (suite *ReadSuite) getTestData(bmpType BitmapType, data []byte, withPalette bool) []byte {
 	var header BitmapHeader
 	buf := bytes.NewBuffer(nil)
@@ -0,0 +1,112 @@
+package image
+
+import (
+	"errors"
+	"image/color"
+	"io"
+	"io/ioutil"
+)
+
+// ShadingTable maps palette indices to the indices that show the same colors
+// at lower light levels. Level 0 is the brightest level; it typically maps
+// each index to itself.
+type ShadingTable struct {
+	levels [][ColorsPerPixel]byte
+}
+
+var errShadingTableLength = errors.New("shading table does not consist of complete levels")
+
+// NewShadingTable returns a shading table with given levels.
+func NewShadingTable(levels [][ColorsPerPixel]byte) *ShadingTable {
+	return &ShadingTable{levels: levels}
+}
+
+// LoadShadingTable reads a shading table from given reader. The table consists
+// of one mapping of ColorsPerPixel entries per light level.
+func LoadShadingTable(reader io.Reader) (*ShadingTable, error) {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	if (len(data) == 0) || ((len(data) % ColorsPerPixel) != 0) {
+		return nil, errShadingTableLength
+	}
+	levels := make([][ColorsPerPixel]byte, len(data)/ColorsPerPixel)
+	for index := range levels {
+		copy(levels[index][:], data[index*ColorsPerPixel:])
+	}
+	return NewShadingTable(levels), nil
+}
+
+// LevelCount returns the number of light levels in the table.
+func (table *ShadingTable) LevelCount() int {
+	return len(table.levels)
+}
+
+// Level returns the mapping of given light level. Levels beyond the range
+// of the table are limited to the first or last level respectively.
+func (table *ShadingTable) Level(level int) [ColorsPerPixel]byte {
+	if level < 0 {
+		level = 0
+	} else if level >= len(table.levels) {
+		level = len(table.levels) - 1
+	}
+	return table.levels[level]
+}
+
+// Shade returns the index that shows given index at given light level.
+func (table *ShadingTable) Shade(index byte, level int) byte {
+	mapping := table.Level(level)
+	return mapping[index]
+}
+
+// ShadeBitmap returns a copy of the bitmap as it is shown at given light level.
+// Index 0x00 is kept to retain the transparency of the bitmap.
+func (table *ShadingTable) ShadeBitmap(bmp Bitmap, level int) *MemoryBitmap {
+	mapping := table.Level(level)
+	var header BitmapHeader
+	header.Type = UncompressedBitmap
+	if stored, isStorable := bmp.(StorableBitmap); isStorable {
+		if stored.Type() == CompressedBitmap {
+			header.Type = CompressedBitmap
+		}
+		if stored.Transparent() {
+			header.TransparencyFlag = 1
+		}
+	}
+	header.Width = bmp.ImageWidth()
+	header.Height = bmp.ImageHeight()
+	header.Stride = header.Width
+	header.WidthFactor = highestBitShift(header.Width)
+	header.HeightFactor = highestBitShift(header.Height)
+	hotspot := bmp.Hotspot()
+	header.HotspotBox = [4]uint16{uint16(hotspot.Min.X), uint16(hotspot.Min.Y), uint16(hotspot.Max.X), uint16(hotspot.Max.Y)}
+
+	width := int(header.Width)
+	data := make([]byte, width*int(header.Height))
+	for row := 0; row < int(header.Height); row++ {
+		shadedRow := data[row*width : (row+1)*width]
+		for column, index := range bmp.Row(row)[:width] {
+			if index != 0x00 {
+				shadedRow[column] = mapping[index]
+			}
+		}
+	}
+
+	return NewMemoryBitmap(&header, data, bmp.Palette())
+}
+
+// ShadePalette returns a palette that shows the colors of given palette at
+// given light level. Rendering a bitmap with this palette equals rendering the
+// bitmap returned by ShadeBitmap with the original palette.
+func (table *ShadingTable) ShadePalette(palette color.Palette, level int) color.Palette {
+	mapping := table.Level(level)
+	result := make(color.Palette, len(palette))
+	for index := range result {
+		result[index] = palette[index]
+		if (index != 0x00) && (index < len(mapping)) && (int(mapping[index]) < len(palette)) {
+			result[index] = palette[mapping[index]]
+		}
+	}
+	return result
+}
@@ -0,0 +1,83 @@
+package image
+
+import (
+	"bytes"
+	"image/color"
+
+	check "gopkg.in/check.v1"
+)
+
+type ShadingTableSuite struct {
+	table *ShadingTable
+}
+
+var _ = check.Suite(&ShadingTableSuite{})
+
+func (suite *ShadingTableSuite) SetUpTest(c *check.C) {
+	data := make([]byte, ColorsPerPixel*3)
+	for index := 0; index < ColorsPerPixel; index++ {
+		data[index] = byte(index)
+		data[ColorsPerPixel+index] = byte(index / 2)
+		data[ColorsPerPixel*2+index] = 0x01
+	}
+	suite.table, _ = LoadShadingTable(bytes.NewReader(data))
+}
+
+func (suite *ShadingTableSuite) TestLoadShadingTableReturnsErrorForIncompleteLevels(c *check.C) {
+	_, err := LoadShadingTable(bytes.NewReader(make([]byte, ColorsPerPixel+1)))
+
+	c.Check(err, check.NotNil)
+}
+
+func (suite *ShadingTableSuite) TestLoadShadingTableReturnsErrorForEmptyData(c *check.C) {
+	_, err := LoadShadingTable(bytes.NewReader(nil))
+
+	c.Check(err, check.NotNil)
+}
+
+func (suite *ShadingTableSuite) TestLevelCount(c *check.C) {
+	c.Check(suite.table.LevelCount(), check.Equals, 3)
+}
+
+func (suite *ShadingTableSuite) TestShade(c *check.C) {
+	c.Check(suite.table.Shade(0x40, 0), check.Equals, byte(0x40))
+	c.Check(suite.table.Shade(0x40, 1), check.Equals, byte(0x20))
+	c.Check(suite.table.Shade(0x40, 2), check.Equals, byte(0x01))
+}
+
+func (suite *ShadingTableSuite) TestShadeLimitsLevels(c *check.C) {
+	c.Check(suite.table.Shade(0x40, -1), check.Equals, byte(0x40))
+	c.Check(suite.table.Shade(0x40, 15), check.Equals, byte(0x01))
+}
+
+func (suite *ShadingTableSuite) TestShadeBitmapMapsAllButTransparentPixels(c *check.C) {
+	header := BitmapHeader{Type: CompressedBitmap, Width: 3, Height: 1, Stride: 4}
+	bmp := NewMemoryBitmap(&header, []byte{0x00, 0x10, 0x80, 0xFF}, nil)
+
+	shaded := suite.table.ShadeBitmap(bmp, 1)
+
+	c.Check(shaded.Row(0), check.DeepEquals, []byte{0x00, 0x08, 0x40})
+	c.Check(shaded.Compressed(), check.Equals, true)
+}
+
+func (suite *ShadingTableSuite) TestShadeBitmapKeepsStorageProperties(c *check.C) {
+	header := BitmapHeader{Type: UncompressedBitmap, Width: 2, Height: 1, Stride: 2}
+	bmp := WithStorage(NewMemoryBitmap(&header, []byte{0x00, 0x10}, nil), CompressedBitmap, true)
+
+	shaded := suite.table.ShadeBitmap(bmp, 1)
+
+	c.Check(shaded.Type(), check.Equals, CompressedBitmap)
+	c.Check(shaded.Transparent(), check.Equals, true)
+}
+
+func (suite *ShadingTableSuite) TestShadePaletteEqualsShadedBitmap(c *check.C) {
+	palette := make(color.Palette, ColorsPerPixel)
+	for index := range palette {
+		palette[index] = color.NRGBA{R: byte(index), A: 0xFF}
+	}
+
+	shaded := suite.table.ShadePalette(palette, 2)
+
+	c.Check(shaded[0x00], check.Equals, palette[0x00])
+	c.Check(shaded[0x80], check.Equals, palette[0x01])
+}
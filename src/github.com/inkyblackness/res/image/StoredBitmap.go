@@ -0,0 +1,38 @@
+package image
+
+// StorableBitmap is a bitmap that describes how it shall be stored.
+// Encoders, such as the content codec for bitmaps, store such bitmaps accordingly.
+type StorableBitmap interface {
+	Bitmap
+
+	// Type returns the type the bitmap shall be stored with.
+	Type() BitmapType
+	// Transparent returns whether palette index 0x00 shall be marked as transparent.
+	Transparent() bool
+}
+
+var _ StorableBitmap = &MemoryBitmap{}
+var _ StorableBitmap = &storedBitmap{}
+
+// storedBitmap is a bitmap with explicit storage properties.
+type storedBitmap struct {
+	Bitmap
+	bitmapType  BitmapType
+	transparent bool
+}
+
+// WithStorage returns a bitmap that reports given type and transparency flag,
+// regardless of what the wrapped bitmap reports.
+func WithStorage(bmp Bitmap, bitmapType BitmapType, transparent bool) StorableBitmap {
+	return &storedBitmap{Bitmap: bmp, bitmapType: bitmapType, transparent: transparent}
+}
+
+// Type returns the type the bitmap shall be stored with.
+func (bmp *storedBitmap) Type() BitmapType {
+	return bmp.bitmapType
+}
+
+// Transparent returns whether palette index 0x00 shall be marked as transparent.
+func (bmp *storedBitmap) Transparent() bool {
+	return bmp.transparent
+}
@@ -0,0 +1,23 @@
+package image
+
+import (
+	"image"
+
+	check "gopkg.in/check.v1"
+)
+
+type StoredBitmapSuite struct {
+}
+
+var _ = check.Suite(&StoredBitmapSuite{})
+
+func (suite *StoredBitmapSuite) TestWithStorageOverridesStorageProperties(c *check.C) {
+	img := image.NewPaletted(image.Rect(0, 0, 2, 1), nil)
+	img.Pix[1] = 0x05
+
+	bmp := WithStorage(ToBitmap(img, nil), CompressedBitmap, true)
+
+	c.Check(bmp.Type(), check.Equals, CompressedBitmap)
+	c.Check(bmp.Transparent(), check.Equals, true)
+	c.Check(bmp.Row(0), check.DeepEquals, []byte{0x00, 0x05})
+}
@@ -0,0 +1,24 @@
+package image
+
+import "io"
+
+// TranslucencyTable belongs to a translucent color. It maps the palette
+// indices of a background to the indices that show the background through
+// the translucent color.
+type TranslucencyTable [ColorsPerPixel]byte
+
+// LoadTranslucencyTable reads a translucency table from given reader.
+func LoadTranslucencyTable(reader io.Reader) (table *TranslucencyTable, err error) {
+	table = new(TranslucencyTable)
+	_, err = io.ReadFull(reader, table[:])
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// Blend returns the index that shows given background index through the
+// translucent color.
+func (table *TranslucencyTable) Blend(background byte) byte {
+	return table[background]
+}
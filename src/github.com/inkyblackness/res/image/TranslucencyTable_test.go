@@ -0,0 +1,27 @@
+package image
+
+import (
+	"bytes"
+
+	check "gopkg.in/check.v1"
+)
+
+type TranslucencyTableSuite struct {
+}
+
+var _ = check.Suite(&TranslucencyTableSuite{})
+
+func (suite *TranslucencyTableSuite) TestLoadTranslucencyTableReturnsErrorForShortData(c *check.C) {
+	_, err := LoadTranslucencyTable(bytes.NewReader(make([]byte, 10)))
+
+	c.Check(err, check.NotNil)
+}
+
+func (suite *TranslucencyTableSuite) TestBlend(c *check.C) {
+	data := make([]byte, ColorsPerPixel)
+	data[0x20] = 0x42
+	table, err := LoadTranslucencyTable(bytes.NewReader(data))
+
+	c.Assert(err, check.IsNil)
+	c.Check(table.Blend(0x20), check.Equals, byte(0x42))
+}
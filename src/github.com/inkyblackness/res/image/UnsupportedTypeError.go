@@ -0,0 +1,14 @@
+package image
+
+import "fmt"
+
+// UnsupportedTypeError is returned when reading or writing a bitmap of a type
+// that is not supported.
+type UnsupportedTypeError struct {
+	// Type is the type of the bitmap.
+	Type BitmapType
+}
+
+func (err UnsupportedTypeError) Error() string {
+	return fmt.Sprintf("unsupported bitmap type 0x%04X", uint16(err.Type))
+}
@@ -8,15 +8,21 @@ import (
 	"github.com/inkyblackness/res/compress/rle"
 )
 
-// Write serializes given bitmap. The provided type specifies whether the pixel data
-// shall be compressed. The start offset is used for images with a private palette.
+// Write serializes given bitmap. The provided type specifies how the pixel data
+// shall be stored. The start offset is used for images with a private palette.
 // The start offset specifies the byte length of the blocks before the current one.
 // Force transparency indicates that palette index 0x00 is meant to be treated as
 // transparent. Some usages of bitmaps imply transparency and don't require
 // this flag to be set.
-func Write(writer io.Writer, bmp Bitmap, bmpType BitmapType, forceTransparency bool, startOffset int) {
+// The hotspot of the bitmap is kept.
+// For monochrome bitmaps, all pixels with a palette index other than 0x00 are set.
+// An UnsupportedTypeError is returned for types that can not be written.
+func Write(writer io.Writer, bmp Bitmap, bmpType BitmapType, forceTransparency bool, startOffset int) error {
+	if !bmpType.Supported() {
+		return UnsupportedTypeError{Type: bmpType}
+	}
+
 	var header BitmapHeader
-	pixelData := writePixel(bmp, bmpType)
 
 	header.Type = bmpType
 	if forceTransparency {
@@ -24,9 +30,12 @@ func Write(writer io.Writer, bmp Bitmap, bmpType BitmapType, forceTransparency b
 	}
 	header.Width = bmp.ImageWidth()
 	header.Height = bmp.ImageHeight()
-	header.Stride = header.Width
+	header.Stride = uint16(header.rowBytes())
 	header.HeightFactor = highestBitShift(header.Height)
 	header.WidthFactor = highestBitShift(header.Width)
+	hotspot := bmp.Hotspot()
+	header.HotspotBox = [4]uint16{uint16(hotspot.Min.X), uint16(hotspot.Min.Y), uint16(hotspot.Max.X), uint16(hotspot.Max.Y)}
+	pixelData := writePixel(bmp, &header)
 	if bmp.Palette() != nil {
 		header.PaletteOffset = int32(startOffset + len(pixelData) + binary.Size(header))
 	}
@@ -35,22 +44,34 @@ func Write(writer io.Writer, bmp Bitmap, bmpType BitmapType, forceTransparency b
 	writer.Write(pixelData)
 	if bmp.Palette() != nil {
 		binary.Write(writer, binary.LittleEndian, privatePaletteFlag)
-		SavePalette(writer, bmp.Palette())
+		return SavePalette(writer, bmp.Palette())
 	}
+	return nil
 }
 
-func writePixel(bmp Bitmap, bmpType BitmapType) (result []byte) {
+func writePixel(bmp Bitmap, header *BitmapHeader) (result []byte) {
 	width := int(bmp.ImageWidth())
 	height := int(bmp.ImageHeight())
-	rawPixel := make([]byte, width*height)
+	stride := int(header.Stride)
+	rawPixel := make([]byte, stride*height)
 
 	for row := 0; row < height; row++ {
 		inRow := bmp.Row(row)
-		outRow := rawPixel[width*row:]
-		copy(outRow, inRow)
+		outRow := rawPixel[stride*row:]
+		if header.Type.Encoding() == MonochromeBitmap {
+			bitOffset := header.Type.BitOffset()
+			for column := 0; column < width; column++ {
+				if inRow[column] != 0x00 {
+					bit := bitOffset + column
+					outRow[bit/8] |= 0x80 >> uint(bit%8)
+				}
+			}
+		} else {
+			copy(outRow[:width], inRow)
+		}
 	}
 
-	if bmpType == CompressedBitmap {
+	if header.Type == CompressedBitmap {
 		buf := bytes.NewBuffer(nil)
 		rle.Compress(buf, rawPixel)
 		result = buf.Bytes()
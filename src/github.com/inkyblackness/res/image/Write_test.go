@@ -3,6 +3,7 @@ package image
 import (
 	"bytes"
 	"encoding/binary"
+	"image"
 
 	check "gopkg.in/check.v1"
 )
@@ -68,6 +69,40 @@ func (suite *WriteSuite) TestWriteWithForceTransparency(c *check.C) {
 	c.Check(result, check.DeepEquals, sourceData)
 }
 
+func (suite *WriteSuite) TestWriteMonochromePacksBits(c *check.C) {
+	header := BitmapHeader{Width: 10, Height: 1, Stride: 10}
+	bmp := NewMemoryBitmap(&header, []byte{1, 0, 0, 0, 0, 0, 0, 2, 0, 3}, nil)
+
+	buf := bytes.NewBuffer(nil)
+	err := Write(buf, bmp, MonochromeBitmap, false, 0)
+	c.Assert(err, check.IsNil)
+	result, _ := Read(bytes.NewReader(buf.Bytes()))
+
+	c.Check(buf.Bytes()[binary.Size(header):], check.DeepEquals, []byte{0x81, 0x40})
+	c.Check(result.Row(0), check.DeepEquals, []byte{1, 0, 0, 0, 0, 0, 0, 1, 0, 1})
+}
+
+func (suite *WriteSuite) TestWriteKeepsHotspot(c *check.C) {
+	header := BitmapHeader{Width: 4, Height: 4, Stride: 4, HotspotBox: [4]uint16{1, 2, 3, 4}}
+	bmp := NewMemoryBitmap(&header, make([]byte, 16), nil)
+
+	buf := bytes.NewBuffer(nil)
+	err := Write(buf, bmp, UncompressedBitmap, false, 0)
+	c.Assert(err, check.IsNil)
+	result, _ := Read(bytes.NewReader(buf.Bytes()))
+
+	c.Check(result.Hotspot(), check.Equals, image.Rect(1, 2, 3, 4))
+}
+
+func (suite *WriteSuite) TestWriteReturnsTypedErrorForUnsupportedType(c *check.C) {
+	header := BitmapHeader{Width: 1, Height: 1, Stride: 1}
+	bmp := NewMemoryBitmap(&header, []byte{1}, nil)
+
+	err := Write(bytes.NewBuffer(nil), bmp, BitmapType(0x0007), false, 0)
+
+	c.Check(err, check.Equals, UnsupportedTypeError{Type: BitmapType(0x0007)})
+}
+
 func (suite *WriteSuite) getTestData(bmpType BitmapType, data []byte, withPalette bool) []byte {
 	var header BitmapHeader
 	buf := bytes.NewBuffer(nil)
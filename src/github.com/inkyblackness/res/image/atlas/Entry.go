@@ -0,0 +1,54 @@
+package atlas
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"image/color"
+
+	"github.com/inkyblackness/res/image"
+)
+
+const paletteSize = image.ColorsPerPixel * 3
+
+// Entry describes one bitmap within a sheet.
+type Entry struct {
+	// Block is the index of the block the bitmap is stored in.
+	Block int `json:"block"`
+	// X and Y are the position of the top left pixel within the sheet.
+	X int `json:"x"`
+	Y int `json:"y"`
+	// Width and Height are the size of the bitmap in pixel.
+	Width  int `json:"width"`
+	Height int `json:"height"`
+	// Hotspot is the box of the hotspot, relative to the bitmap: left, top, right, bottom.
+	Hotspot [4]int `json:"hotspot"`
+	// Type is the type the bitmap is stored with.
+	Type        image.BitmapType `json:"type"`
+	Transparent bool             `json:"transparent"`
+	// PrivatePalette is set for bitmaps that have their own palette.
+	// The sheet shows these bitmaps with the palette of the sheet.
+	PrivatePalette bool `json:"privatePalette"`
+	// Palette is the private palette as hexadecimal RGB triples.
+	Palette string `json:"palette,omitempty"`
+}
+
+func encodePalette(palette color.Palette) (string, error) {
+	buffer := bytes.NewBuffer(nil)
+	err := image.SavePalette(buffer, palette)
+	return hex.EncodeToString(buffer.Bytes()), err
+}
+
+func (entry *Entry) privatePalette() (color.Palette, error) {
+	if !entry.PrivatePalette {
+		return nil, nil
+	}
+	data, err := hex.DecodeString(entry.Palette)
+	if err != nil {
+		return nil, fmt.Errorf("invalid palette of block %d: %v", entry.Block, err)
+	}
+	if len(data) != paletteSize {
+		return nil, fmt.Errorf("palette of block %d has %d bytes, expected %d", entry.Block, len(data), paletteSize)
+	}
+	return image.LoadPalette(bytes.NewReader(data))
+}
@@ -0,0 +1,7 @@
+package atlas
+
+// Layout describes where the bitmaps of a chunk are placed within a sheet.
+// It is stored next to the sheet, as JSON.
+type Layout struct {
+	Entries []Entry `json:"entries"`
+}
@@ -0,0 +1,107 @@
+package atlas
+
+import (
+	"fmt"
+	goimage "image"
+	"image/color"
+	"math"
+	"sort"
+
+	"github.com/inkyblackness/res/image"
+)
+
+// spacing is the amount of pixel between bitmaps in a sheet.
+const spacing = 1
+
+// Pack places the given bitmaps into one paletted sheet using the given palette.
+// Bitmaps are arranged in shelves, ordered by their height, in a sheet that is
+// roughly square. Pixel not covered by a bitmap have palette index 0x00.
+//
+// The returned layout has one entry per bitmap, with Block being the index of
+// the bitmap in the given slice. Type and transparency are taken from the bitmaps
+// that are an image.StorableBitmap.
+func Pack(bitmaps []image.Bitmap, palette color.Palette) (*goimage.Paletted, *Layout, error) {
+	layout := &Layout{Entries: make([]Entry, len(bitmaps))}
+	for index, bmp := range bitmaps {
+		entry, err := newEntry(index, bmp)
+		if err != nil {
+			return nil, nil, err
+		}
+		layout.Entries[index] = entry
+	}
+
+	width, height := arrange(layout.Entries)
+	sheet := goimage.NewPaletted(goimage.Rect(0, 0, width, height), palette)
+	for index, bmp := range bitmaps {
+		entry := &layout.Entries[index]
+		for row := 0; row < entry.Height; row++ {
+			copy(sheet.Pix[(entry.Y+row)*sheet.Stride+entry.X:], bmp.Row(row)[:entry.Width])
+		}
+	}
+
+	return sheet, layout, nil
+}
+
+func newEntry(index int, bmp image.Bitmap) (entry Entry, err error) {
+	hotspot := bmp.Hotspot()
+	entry = Entry{
+		Block:   index,
+		Width:   int(bmp.ImageWidth()),
+		Height:  int(bmp.ImageHeight()),
+		Hotspot: [4]int{hotspot.Min.X, hotspot.Min.Y, hotspot.Max.X, hotspot.Max.Y},
+		Type:    image.UncompressedBitmap}
+	if stored, isStorable := bmp.(image.StorableBitmap); isStorable {
+		entry.Type = stored.Type()
+		entry.Transparent = stored.Transparent()
+	}
+	if palette := bmp.Palette(); palette != nil {
+		entry.PrivatePalette = true
+		entry.Palette, err = encodePalette(palette)
+		if err != nil {
+			err = fmt.Errorf("failed to encode palette of block %d: %v", index, err)
+		}
+	}
+	return
+}
+
+// arrange sets the position of all entries and returns the size of the sheet.
+// The sheet is at least one pixel wide and high.
+func arrange(entries []Entry) (width, height int) {
+	order := make([]int, len(entries))
+	area := 0
+	width = 1
+	for index := range entries {
+		entry := &entries[index]
+		order[index] = index
+		area += (entry.Width + spacing) * (entry.Height + spacing)
+		if entry.Width > width {
+			width = entry.Width
+		}
+	}
+	if side := int(math.Ceil(math.Sqrt(float64(area)))); side > width {
+		width = side
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return entries[order[a]].Height > entries[order[b]].Height
+	})
+
+	x, y, shelfHeight := 0, 0, 0
+	for _, index := range order {
+		entry := &entries[index]
+		if (x > 0) && (x+entry.Width > width) {
+			x = 0
+			y += shelfHeight + spacing
+			shelfHeight = 0
+		}
+		entry.X, entry.Y = x, y
+		x += entry.Width + spacing
+		if entry.Height > shelfHeight {
+			shelfHeight = entry.Height
+		}
+	}
+	height = y + shelfHeight
+	if height < 1 {
+		height = 1
+	}
+	return
+}
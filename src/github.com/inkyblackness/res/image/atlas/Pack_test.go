@@ -0,0 +1,85 @@
+package atlas
+
+import (
+	goimage "image"
+	"image/color"
+	"testing"
+
+	"github.com/inkyblackness/res/image"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func aBitmap(bmpType image.BitmapType, width, height int, value byte, palette color.Palette) *image.MemoryBitmap {
+	header := image.BitmapHeader{Type: bmpType, Width: uint16(width), Height: uint16(height), Stride: uint16(width)}
+	data := make([]byte, width*height)
+	for index := range data {
+		data[index] = value
+	}
+	return image.NewMemoryBitmap(&header, data, palette)
+}
+
+func grayPalette() color.Palette {
+	palette := make(color.Palette, image.ColorsPerPixel)
+	for index := range palette {
+		palette[index] = color.NRGBA{R: byte(index), G: byte(index), B: byte(index), A: 0xFF}
+	}
+	return palette
+}
+
+func TestPackPlacesBitmapsWithoutOverlap(t *testing.T) {
+	bitmaps := []image.Bitmap{
+		aBitmap(image.UncompressedBitmap, 3, 2, 1, nil),
+		aBitmap(image.CompressedBitmap, 5, 4, 2, nil),
+		aBitmap(image.UncompressedBitmap, 1, 7, 3, nil),
+		aBitmap(image.UncompressedBitmap, 0, 0, 4, nil)}
+
+	sheet, layout, err := Pack(bitmaps, grayPalette())
+	require.Nil(t, err, "no error expected")
+	require.Equal(t, len(bitmaps), len(layout.Entries))
+
+	for index, entry := range layout.Entries {
+		area := goimage.Rect(entry.X, entry.Y, entry.X+entry.Width, entry.Y+entry.Height)
+		assert.Equal(t, index, entry.Block)
+		assert.True(t, area.In(sheet.Bounds()), "entry %d should be within sheet", index)
+		for other := index + 1; other < len(layout.Entries); other++ {
+			otherEntry := layout.Entries[other]
+			otherArea := goimage.Rect(otherEntry.X, otherEntry.Y, otherEntry.X+otherEntry.Width, otherEntry.Y+otherEntry.Height)
+			assert.True(t, area.Intersect(otherArea).Empty(), "entries %d and %d should not overlap", index, other)
+		}
+		for y := area.Min.Y; y < area.Max.Y; y++ {
+			for x := area.Min.X; x < area.Max.X; x++ {
+				require.Equal(t, byte(index+1), sheet.ColorIndexAt(x, y), "pixel %d,%d of entry %d", x, y, index)
+			}
+		}
+	}
+}
+
+func TestPackDescribesBitmapProperties(t *testing.T) {
+	header := image.BitmapHeader{Type: image.CompressedBitmap, TransparencyFlag: 1, Width: 2, Height: 2, Stride: 2,
+		HotspotBox: [4]uint16{0, 1, 2, 2}}
+	bitmaps := []image.Bitmap{
+		image.NewMemoryBitmap(&header, make([]byte, 4), nil),
+		aBitmap(image.UncompressedBitmap, 1, 1, 0, grayPalette())}
+
+	_, layout, err := Pack(bitmaps, grayPalette())
+	require.Nil(t, err, "no error expected")
+
+	first := layout.Entries[0]
+	assert.Equal(t, image.CompressedBitmap, first.Type)
+	assert.True(t, first.Transparent, "first should be transparent")
+	assert.Equal(t, [4]int{0, 1, 2, 2}, first.Hotspot)
+	assert.False(t, first.PrivatePalette, "first should have no private palette")
+	second := layout.Entries[1]
+	assert.True(t, second.PrivatePalette, "second should have a private palette")
+	assert.Equal(t, paletteSize*2, len(second.Palette))
+}
+
+func TestPackReturnsSheetOfMinimumSizeForNoBitmaps(t *testing.T) {
+	sheet, layout, err := Pack(nil, grayPalette())
+	require.Nil(t, err, "no error expected")
+
+	assert.Equal(t, goimage.Rect(0, 0, 1, 1), sheet.Bounds())
+	assert.Equal(t, 0, len(layout.Entries))
+}
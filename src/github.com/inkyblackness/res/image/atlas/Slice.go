@@ -0,0 +1,63 @@
+package atlas
+
+import (
+	"fmt"
+	goimage "image"
+	"math"
+
+	"github.com/inkyblackness/res/image"
+)
+
+// Slice cuts the bitmaps described by the layout out of the given sheet.
+// The returned slice is ordered by block index. The layout must describe each
+// block from zero up to the highest block index exactly once, and all bitmaps
+// must be within the bounds of the sheet.
+func Slice(sheet *goimage.Paletted, layout *Layout) ([]*image.MemoryBitmap, error) {
+	bitmaps := make([]*image.MemoryBitmap, len(layout.Entries))
+	bounds := sheet.Bounds()
+	for index := range layout.Entries {
+		entry := &layout.Entries[index]
+		if (entry.Block < 0) || (entry.Block >= len(bitmaps)) {
+			return nil, fmt.Errorf("block index %d is out of range, expected 0..%d", entry.Block, len(bitmaps)-1)
+		}
+		if bitmaps[entry.Block] != nil {
+			return nil, fmt.Errorf("block %d is listed more than once", entry.Block)
+		}
+		if !entry.Type.Supported() {
+			return nil, image.UnsupportedTypeError{Type: entry.Type}
+		}
+		area := goimage.Rect(entry.X, entry.Y, entry.X+entry.Width, entry.Y+entry.Height).Add(bounds.Min)
+		if (entry.Width < 0) || (entry.Height < 0) || (entry.Width > math.MaxUint16) || (entry.Height > math.MaxUint16) ||
+			!area.In(bounds) {
+			return nil, fmt.Errorf("block %d at %v is not within sheet %v", entry.Block, area, bounds)
+		}
+		for _, value := range entry.Hotspot {
+			if (value < 0) || (value > math.MaxUint16) {
+				return nil, fmt.Errorf("hotspot %v of block %d is out of range", entry.Hotspot, entry.Block)
+			}
+		}
+		palette, err := entry.privatePalette()
+		if err != nil {
+			return nil, err
+		}
+
+		header := image.BitmapHeader{
+			Type:   entry.Type,
+			Width:  uint16(entry.Width),
+			Height: uint16(entry.Height),
+			Stride: uint16(entry.Width)}
+		if entry.Transparent {
+			header.TransparencyFlag = 1
+		}
+		for corner, value := range entry.Hotspot {
+			header.HotspotBox[corner] = uint16(value)
+		}
+		data := make([]byte, entry.Width*entry.Height)
+		for row := 0; row < entry.Height; row++ {
+			start := sheet.PixOffset(area.Min.X, area.Min.Y+row)
+			copy(data[row*entry.Width:], sheet.Pix[start:start+entry.Width])
+		}
+		bitmaps[entry.Block] = image.NewMemoryBitmap(&header, data, palette)
+	}
+	return bitmaps, nil
+}
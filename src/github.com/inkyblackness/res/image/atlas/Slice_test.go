@@ -0,0 +1,67 @@
+package atlas
+
+import (
+	"bytes"
+	"encoding/json"
+	goimage "image"
+	"testing"
+
+	"github.com/inkyblackness/res/image"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSliceRestoresPackedBitmaps(t *testing.T) {
+	header := image.BitmapHeader{Type: image.CompressedBitmap, TransparencyFlag: 1, Width: 3, Height: 2, Stride: 3,
+		HotspotBox: [4]uint16{1, 0, 3, 2}}
+	original := []image.Bitmap{
+		image.NewMemoryBitmap(&header, []byte{1, 2, 3, 4, 5, 6}, nil),
+		aBitmap(image.UncompressedBitmap, 2, 4, 7, grayPalette()),
+		aBitmap(image.MonochromeBitmap, 9, 1, 1, nil)}
+	sheet, layout, err := Pack(original, grayPalette())
+	require.Nil(t, err, "no error expected packing")
+	encoded, err := json.Marshal(layout)
+	require.Nil(t, err, "no error expected encoding")
+	var decoded Layout
+	require.Nil(t, json.Unmarshal(encoded, &decoded), "no error expected decoding")
+
+	bitmaps, err := Slice(sheet, &decoded)
+	require.Nil(t, err, "no error expected slicing")
+
+	require.Equal(t, len(original), len(bitmaps))
+	for index, bmp := range bitmaps {
+		expected := original[index].(*image.MemoryBitmap)
+		assert.Equal(t, expected.Type(), bmp.Type(), "type of %d", index)
+		assert.Equal(t, expected.Transparent(), bmp.Transparent(), "transparency of %d", index)
+		assert.Equal(t, expected.Hotspot(), bmp.Hotspot(), "hotspot of %d", index)
+		assert.Equal(t, expected.Palette() != nil, bmp.Palette() != nil, "palette of %d", index)
+		for row := 0; row < int(expected.ImageHeight()); row++ {
+			assert.Equal(t, expected.Row(row), bmp.Row(row), "row %d of %d", row, index)
+		}
+	}
+	buffer := bytes.NewBuffer(nil)
+	assert.Nil(t, image.Write(buffer, bitmaps[2], bitmaps[2].Type(), false, 0), "monochrome should be writable")
+}
+
+func TestSliceReturnsErrorForInvalidLayouts(t *testing.T) {
+	sheet := goimage.NewPaletted(goimage.Rect(0, 0, 4, 4), grayPalette())
+	valid := Entry{Width: 2, Height: 2, Type: image.UncompressedBitmap}
+	tests := map[string]func(entries []Entry){
+		"outside":         func(entries []Entry) { entries[0].X = 3 },
+		"negative":        func(entries []Entry) { entries[0].Width = -1 },
+		"missing block":   func(entries []Entry) { entries[1].Block = 2 },
+		"duplicate block": func(entries []Entry) { entries[1].Block = 0 },
+		"unsupported":     func(entries []Entry) { entries[1].Type = image.BitmapType(0x0007) },
+		"hotspot":         func(entries []Entry) { entries[1].Hotspot[2] = 0x10000 },
+		"palette":         func(entries []Entry) { entries[1].PrivatePalette = true; entries[1].Palette = "0011" }}
+
+	for name, modify := range tests {
+		entries := []Entry{valid, valid}
+		entries[1].Block = 1
+		modify(entries)
+
+		_, err := Slice(sheet, &Layout{Entries: entries})
+		assert.NotNil(t, err, "error expected for %s", name)
+	}
+}
@@ -0,0 +1,75 @@
+package movi
+
+import (
+	"io"
+	"sort"
+)
+
+// AudioSource provides the audio track of a container as one sequence of samples.
+// The data of the audio entries is only accessed when the samples are requested,
+// instead of being collected beforehand.
+type AudioSource struct {
+	*io.SectionReader
+	sampleRate  float32
+	sampleCount int
+}
+
+// NewAudioSource returns a source for the audio entries of given container.
+func NewAudioSource(container Container) *AudioSource {
+	reader := &audioReader{container: container}
+	sampleCount := 0
+
+	for index := 0; index < container.EntryCount(); index++ {
+		entry := container.Entry(index)
+		if entry.Type() == Audio {
+			reader.entries = append(reader.entries, index)
+			reader.starts = append(reader.starts, sampleCount)
+			sampleCount += len(entry.Data())
+		}
+	}
+
+	return &AudioSource{
+		SectionReader: io.NewSectionReader(reader, 0, int64(sampleCount)),
+		sampleRate:    float32(container.AudioSampleRate()),
+		sampleCount:   sampleCount}
+}
+
+// SampleRate returns the amount of samples per second.
+func (source *AudioSource) SampleRate() float32 {
+	return source.sampleRate
+}
+
+// SampleCount returns the amount of samples of all audio entries.
+func (source *AudioSource) SampleCount() int {
+	return source.sampleCount
+}
+
+// audioReader provides random access to the samples of the audio entries of a container.
+type audioReader struct {
+	container Container
+	entries   []int
+	starts    []int
+}
+
+// ReadAt reads the samples of the given range. The range is limited by the section reader of the source.
+func (reader *audioReader) ReadAt(buffer []byte, offset int64) (read int, err error) {
+	position := int(offset)
+	index := sort.Search(len(reader.starts), func(index int) bool {
+		return reader.starts[index] > position
+	}) - 1
+
+	for (read < len(buffer)) && (index >= 0) && (index < len(reader.entries)) {
+		samples := reader.container.Entry(reader.entries[index]).Data()
+		start := position - reader.starts[index]
+		if start < len(samples) {
+			copied := copy(buffer[read:], samples[start:])
+			read += copied
+			position += copied
+		}
+		index++
+	}
+	if read < len(buffer) {
+		err = io.EOF
+	}
+	return
+}
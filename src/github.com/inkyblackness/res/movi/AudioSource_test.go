@@ -0,0 +1,54 @@
+package movi
+
+import (
+	"io"
+	"io/ioutil"
+
+	check "gopkg.in/check.v1"
+)
+
+type AudioSourceSuite struct {
+	container Container
+}
+
+var _ = check.Suite(&AudioSourceSuite{})
+
+func (suite *AudioSourceSuite) SetUpTest(c *check.C) {
+	builder := NewContainerBuilder()
+	builder.AudioSampleRate(22050)
+	builder.AddEntry(NewMemoryEntry(0.0, Audio, []byte{0x10, 0x20, 0x30}))
+	builder.AddEntry(NewMemoryEntry(0.0, Subtitle, []byte{0x00}))
+	builder.AddEntry(NewMemoryEntry(0.1, Audio, []byte{}))
+	builder.AddEntry(NewMemoryEntry(0.1, Audio, []byte{0x40, 0x50}))
+	suite.container = builder.Build()
+}
+
+func (suite *AudioSourceSuite) TestProvidesAllAudioEntries(c *check.C) {
+	source := NewAudioSource(suite.container)
+
+	c.Check(source.SampleRate(), check.Equals, float32(22050.0))
+	c.Check(source.SampleCount(), check.Equals, 5)
+	samples, err := ioutil.ReadAll(source)
+	c.Assert(err, check.IsNil)
+	c.Check(samples, check.DeepEquals, []byte{0x10, 0x20, 0x30, 0x40, 0x50})
+}
+
+func (suite *AudioSourceSuite) TestReadsAcrossEntriesFromSeekPosition(c *check.C) {
+	source := NewAudioSource(suite.container)
+	buffer := make([]byte, 3)
+
+	source.Seek(2, io.SeekStart)
+	read, err := source.Read(buffer)
+
+	c.Check(err, check.IsNil)
+	c.Check(buffer[:read], check.DeepEquals, []byte{0x30, 0x40, 0x50})
+}
+
+func (suite *AudioSourceSuite) TestIsEmptyWithoutAudio(c *check.C) {
+	source := NewAudioSource(NewContainerBuilder().Build())
+
+	_, err := source.Read(make([]byte, 1))
+
+	c.Check(source.SampleCount(), check.Equals, 0)
+	c.Check(err, check.Equals, io.EOF)
+}
@@ -0,0 +1,228 @@
+package movi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	goImage "image"
+	"image/color"
+	"sort"
+
+	"github.com/inkyblackness/res/audio"
+	"github.com/inkyblackness/res/image"
+	"github.com/inkyblackness/res/text"
+)
+
+// maxTimestamp is the latest time a container can represent.
+var maxTimestamp = timeFromRaw(0xFF, 0xFFFF)
+
+// TimedFrame is a video frame that is shown from its timestamp on.
+type TimedFrame struct {
+	Timestamp float32
+	Frame     *goImage.Paletted
+}
+
+// TimedPalette is a palette that is used from its timestamp on.
+type TimedPalette struct {
+	Timestamp float32
+	Palette   color.Palette
+}
+
+// TimedSubtitle is a text that is shown from its start until its end.
+type TimedSubtitle struct {
+	Start   float32
+	End     float32
+	Control SubtitleControl
+	Text    string
+}
+
+// Composition describes the media of a container, as created by Compose().
+type Composition struct {
+	// Frames are the video frames, in ascending order of their timestamps.
+	// All frames must have the same size, a multiple of the tile size of high resolution video.
+	Frames []TimedFrame
+	// Palettes is the schedule of palettes, in ascending order of their timestamps.
+	// Should the first one not start at zero, the palette of the first frame is the start palette.
+	Palettes []TimedPalette
+	// Sound is the optional audio track, starting at zero.
+	Sound audio.SoundData
+	// Subtitles are the texts to show. Texts of the same control must not overlap.
+	Subtitles []TimedSubtitle
+	// Duration is the minimum duration of the media. It is extended to cover all other media.
+	Duration float32
+}
+
+// Compose creates a container from the given composition.
+//
+// Frames are encoded as high resolution video. As a palette change clears the frame buffer,
+// each palette starts a new scene with its own control dictionary.
+// The sound is split into entries like ContainSoundData does, and each subtitle is
+// followed by an empty text of the same control at its end, unless the next text takes over.
+func Compose(composition Composition) (Container, error) {
+	builder := NewContainerBuilder()
+	var entries []Entry
+	duration := composition.Duration
+	extendTo := func(timestamp float32) {
+		if timestamp > duration {
+			duration = timestamp
+		}
+	}
+
+	palettes := composition.Palettes
+	for index, palette := range palettes {
+		if (index > 0) && (palette.Timestamp < palettes[index-1].Timestamp) {
+			return nil, fmt.Errorf("palette %d is out of order", index)
+		}
+		if len(palette.Palette) != image.ColorsPerPixel {
+			return nil, fmt.Errorf("palette %d has %d instead of %d colors", index, len(palette.Palette), image.ColorsPerPixel)
+		}
+	}
+	if (len(palettes) > 0) && (palettes[0].Timestamp == 0) {
+		builder.StartPalette(palettes[0].Palette)
+		palettes = palettes[1:]
+	} else if len(composition.Frames) > 0 {
+		startPalette := composition.Frames[0].Frame.Palette
+		if len(startPalette) != image.ColorsPerPixel {
+			return nil, fmt.Errorf("palette of first frame has %d instead of %d colors", len(startPalette), image.ColorsPerPixel)
+		}
+		builder.StartPalette(startPalette)
+	}
+	for _, palette := range palettes {
+		buf := bytes.NewBuffer(nil)
+		err := image.SavePalette(buf, palette.Palette)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries,
+			NewMemoryEntry(palette.Timestamp, PaletteReset, []byte{}),
+			NewMemoryEntry(palette.Timestamp, Palette, buf.Bytes()))
+		extendTo(palette.Timestamp)
+	}
+
+	videoEntries, err := composeVideo(composition.Frames, palettes)
+	if err != nil {
+		return nil, err
+	}
+	entries = append(entries, videoEntries...)
+	if len(composition.Frames) > 0 {
+		bounds := composition.Frames[0].Frame.Bounds()
+		builder.VideoWidth(uint16(bounds.Dx())).VideoHeight(uint16(bounds.Dy()))
+		extendTo(composition.Frames[len(composition.Frames)-1].Timestamp)
+	}
+
+	if composition.Sound != nil {
+		soundEntries, soundEnd := composeSound(composition.Sound)
+		entries = append(entries, soundEntries...)
+		builder.AudioSampleRate(uint16(composition.Sound.SampleRate()))
+		extendTo(soundEnd)
+	}
+
+	subtitleEntries, err := composeSubtitles(composition.Subtitles)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range subtitleEntries {
+		extendTo(entry.Timestamp())
+	}
+	entries = append(entries, subtitleEntries...)
+
+	if duration > maxTimestamp {
+		return nil, fmt.Errorf("duration of %v seconds exceeds limit of %v seconds", duration, maxTimestamp)
+	}
+	sort.SliceStable(entries, func(a, b int) bool { return entries[a].Timestamp() < entries[b].Timestamp() })
+	for _, entry := range entries {
+		builder.AddEntry(entry)
+	}
+	builder.MediaDuration(duration)
+	return builder.Build(), nil
+}
+
+// composeVideo encodes the frames in scenes, each starting at a palette change.
+func composeVideo(frames []TimedFrame, paletteChanges []TimedPalette) (entries []Entry, err error) {
+	var sceneFrames []*goImage.Paletted
+	var sceneTimestamps []float32
+	finishScene := func() error {
+		if len(sceneFrames) == 0 {
+			return nil
+		}
+		sceneEntries, sceneErr := EncodeHighResScene(sceneFrames, sceneTimestamps)
+		if sceneErr != nil {
+			return fmt.Errorf("scene at %v seconds: %v", sceneTimestamps[0], sceneErr)
+		}
+		entries = append(entries, sceneEntries...)
+		sceneFrames = nil
+		sceneTimestamps = nil
+		return nil
+	}
+
+	nextChange := 0
+	for index, frame := range frames {
+		if (index > 0) && (frame.Timestamp < frames[index-1].Timestamp) {
+			return nil, fmt.Errorf("frame %d is out of order", index)
+		}
+		for (nextChange < len(paletteChanges)) && (paletteChanges[nextChange].Timestamp <= frame.Timestamp) {
+			err = finishScene()
+			if err != nil {
+				return
+			}
+			nextChange++
+		}
+		sceneFrames = append(sceneFrames, frame.Frame)
+		sceneTimestamps = append(sceneTimestamps, frame.Timestamp)
+	}
+	err = finishScene()
+	return
+}
+
+// composeSound splits the sound data into entries and returns them together with their end time.
+func composeSound(soundData audio.SoundData) (entries []Entry, endTime float32) {
+	startOffset := 0
+	timePerEntry := timeFromRaw(timeToRaw(float32(audioEntrySize) / soundData.SampleRate()))
+
+	for (startOffset + audioEntrySize) <= soundData.SampleCount() {
+		endOffset := startOffset + audioEntrySize
+		entries = append(entries, NewMemoryEntry(endTime, Audio, soundData.Samples(startOffset, endOffset)))
+		endTime += timePerEntry
+		startOffset = endOffset
+	}
+	if startOffset < soundData.SampleCount() {
+		entries = append(entries, NewMemoryEntry(endTime, Audio, soundData.Samples(startOffset, soundData.SampleCount())))
+		endTime += timeFromRaw(timeToRaw(float32(soundData.SampleCount()-startOffset) / soundData.SampleRate()))
+	}
+	return
+}
+
+// composeSubtitles creates the entries for the texts, in order of their start per control.
+func composeSubtitles(subtitles []TimedSubtitle) (entries []Entry, err error) {
+	codepage := text.DefaultCodepage()
+	sorted := make([]TimedSubtitle, len(subtitles))
+	copy(sorted, subtitles)
+	sort.SliceStable(sorted, func(a, b int) bool { return sorted[a].Start < sorted[b].Start })
+
+	subtitleEntry := func(timestamp float32, control SubtitleControl, value string) Entry {
+		buf := bytes.NewBuffer(nil)
+		binary.Write(buf, binary.LittleEndian, &SubtitleHeader{Control: control})
+		buf.Write(codepage.Encode(value))
+		return NewMemoryEntry(timestamp, Subtitle, buf.Bytes())
+	}
+	for index, subtitle := range sorted {
+		if subtitle.End < subtitle.Start {
+			return nil, fmt.Errorf("subtitle %q ends before it starts", subtitle.Text)
+		}
+		endsFree := true
+		for _, next := range sorted[index+1:] {
+			if next.Control == subtitle.Control {
+				if next.Start < subtitle.End {
+					return nil, fmt.Errorf("subtitle %q overlaps with %q", subtitle.Text, next.Text)
+				}
+				endsFree = next.Start > subtitle.End
+				break
+			}
+		}
+		entries = append(entries, subtitleEntry(subtitle.Start, subtitle.Control, subtitle.Text))
+		if endsFree {
+			entries = append(entries, subtitleEntry(subtitle.End, subtitle.Control, ""))
+		}
+	}
+	return
+}
@@ -0,0 +1,142 @@
+package movi
+
+import (
+	"bytes"
+	goImage "image"
+	"image/color"
+
+	"github.com/inkyblackness/res/audio/mem"
+
+	check "gopkg.in/check.v1"
+)
+
+type ComposeSuite struct {
+	scenes EncodeHighResSceneSuite
+}
+
+var _ = check.Suite(&ComposeSuite{})
+
+func (suite *ComposeSuite) TestSoundIsSplitIntoEntries(c *check.C) {
+	samples := make([]byte, audioEntrySize+100)
+	for index := range samples {
+		samples[index] = byte(index)
+	}
+	container, err := Compose(Composition{Sound: mem.NewL8SoundData(22050, samples)})
+
+	c.Assert(err, check.IsNil)
+	c.Assert(container.EntryCount(), check.Equals, 2)
+	c.Check(container.Entry(1).Timestamp(), check.Equals, timeFromRaw(timeToRaw(float32(audioEntrySize)/22050)))
+	c.Check(container.AudioSampleRate(), check.Equals, uint16(22050))
+	c.Check(container.MediaDuration() > float32(len(samples)-1)/22050, check.Equals, true)
+}
+
+func (suite *ComposeSuite) TestDurationCoversAllMedia(c *check.C) {
+	container, err := Compose(Composition{
+		Duration:  1.0,
+		Subtitles: []TimedSubtitle{{Start: 1.5, End: 2.5, Control: SubtitleTextStd, Text: "a"}}})
+
+	c.Assert(err, check.IsNil)
+	c.Check(container.MediaDuration(), check.Equals, float32(2.5))
+}
+
+func (suite *ComposeSuite) TestSubtitlesAreEndedWithEmptyText(c *check.C) {
+	handler := suite.dispatch(c, Composition{
+		Palettes: []TimedPalette{{Timestamp: 0.0, Palette: suite.scenes.palette()}},
+		Subtitles: []TimedSubtitle{
+			{Start: 2.0, End: 3.0, Control: SubtitleTextStd, Text: "second"},
+			{Start: 1.0, End: 2.0, Control: SubtitleTextStd, Text: "first"},
+			{Start: 1.0, End: 1.5, Control: SubtitleTextGer, Text: "erster"}}})
+
+	c.Check(handler.subtitles, check.DeepEquals, []collectedSubtitle{
+		{timestamp: 1.0, control: SubtitleTextStd, text: "first"},
+		{timestamp: 1.0, control: SubtitleTextGer, text: "erster"},
+		{timestamp: 1.5, control: SubtitleTextGer, text: ""},
+		{timestamp: 2.0, control: SubtitleTextStd, text: "second"},
+		{timestamp: 3.0, control: SubtitleTextStd, text: ""}})
+}
+
+func (suite *ComposeSuite) TestReturnsErrorForOverlappingSubtitles(c *check.C) {
+	_, err := Compose(Composition{Subtitles: []TimedSubtitle{
+		{Start: 1.0, End: 2.0, Control: SubtitleTextStd, Text: "first"},
+		{Start: 1.5, End: 3.0, Control: SubtitleTextStd, Text: "second"}}})
+
+	c.Check(err, check.ErrorMatches, ".*overlaps.*")
+}
+
+func (suite *ComposeSuite) TestReturnsErrorForTooLongMedia(c *check.C) {
+	_, err := Compose(Composition{Duration: 300.0})
+
+	c.Check(err, check.NotNil)
+}
+
+func (suite *ComposeSuite) TestReturnsErrorForFramesOutOfOrder(c *check.C) {
+	_, err := Compose(Composition{Frames: []TimedFrame{
+		{Timestamp: 1.0, Frame: suite.scenes.frame(1)},
+		{Timestamp: 0.5, Frame: suite.scenes.frame(2)}}})
+
+	c.Check(err, check.ErrorMatches, "frame 1 is out of order")
+}
+
+func (suite *ComposeSuite) TestPaletteChangeStartsNewScene(c *check.C) {
+	otherPalette := make(color.Palette, 256)
+	for index := range otherPalette {
+		otherPalette[index] = color.NRGBA{R: byte(index), A: 0xFF}
+	}
+	frames := []*goImage.Paletted{suite.scenes.frame(3), suite.scenes.frame(1), suite.scenes.frame(2)}
+	handler := suite.dispatch(c, Composition{
+		Frames: []TimedFrame{
+			{Timestamp: 0.0, Frame: frames[0]},
+			{Timestamp: 0.5, Frame: frames[1]},
+			{Timestamp: 1.0, Frame: frames[2]}},
+		Palettes: []TimedPalette{
+			{Timestamp: 0.0, Palette: suite.scenes.palette()},
+			{Timestamp: 0.5, Palette: otherPalette}}})
+
+	c.Assert(len(handler.frames), check.Equals, len(frames))
+	for index, frame := range frames {
+		c.Check(handler.frames[index].Pix, check.DeepEquals, frame.Pix, check.Commentf("frame %d", index))
+	}
+	c.Check(handler.frames[0].Palette[0x10], check.DeepEquals, suite.scenes.palette()[0x10])
+	c.Check(handler.frames[1].Palette[0x10], check.DeepEquals, otherPalette[0x10])
+	c.Check(handler.timestamps, check.DeepEquals, []float32{0.0, 0.5, 1.0})
+}
+
+func (suite *ComposeSuite) TestComposedContainerCanBeWrittenAndRead(c *check.C) {
+	samples := make([]byte, 3*audioEntrySize)
+	container, err := Compose(Composition{
+		Frames:    []TimedFrame{{Timestamp: 0.25, Frame: suite.scenes.frame(1)}},
+		Sound:     mem.NewL8SoundData(22050, samples),
+		Subtitles: []TimedSubtitle{{Start: 0.25, End: 0.75, Control: SubtitleTextFrn, Text: "voilà"}}})
+	c.Assert(err, check.IsNil)
+	buffer := bytes.NewBuffer(nil)
+	Write(buffer, container)
+
+	read, readErr := Read(bytes.NewReader(buffer.Bytes()))
+	c.Assert(readErr, check.IsNil)
+	handler := &collectingMediaHandler{}
+	dispatcher := NewMediaDispatcher(read, handler)
+	for more := true; more; {
+		more, err = dispatcher.DispatchNext()
+		c.Assert(err, check.IsNil)
+	}
+
+	c.Check(read.VideoWidth(), check.Equals, uint16(32))
+	c.Check(read.VideoHeight(), check.Equals, uint16(16))
+	c.Check(len(handler.audio), check.Equals, len(samples))
+	c.Check(len(handler.frames), check.Equals, 1)
+	c.Check(handler.subtitles, check.DeepEquals, []collectedSubtitle{
+		{timestamp: 0.25, control: SubtitleTextFrn, text: "voilà"},
+		{timestamp: 0.75, control: SubtitleTextFrn, text: ""}})
+}
+
+func (suite *ComposeSuite) dispatch(c *check.C, composition Composition) *collectingMediaHandler {
+	container, err := Compose(composition)
+	c.Assert(err, check.IsNil)
+	handler := &collectingMediaHandler{}
+	dispatcher := NewMediaDispatcher(container, handler)
+	for more := true; more; {
+		more, err = dispatcher.DispatchNext()
+		c.Assert(err, check.IsNil)
+	}
+	return handler
+}
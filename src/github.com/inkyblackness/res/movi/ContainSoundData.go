@@ -8,29 +8,23 @@ import (
 
 const audioEntrySize = 0x2000
 
-// ContainSoundData packs a sound data into a container and encodes it.
-func ContainSoundData(soundData audio.SoundData) []byte {
+// ContainSound packs a sound data into a container that has no video.
+func ContainSound(soundData audio.SoundData) Container {
 	builder := NewContainerBuilder()
-	startOffset := 0
-	entryStartTime := float32(0)
-	timePerEntry := timeFromRaw(timeToRaw(float32(audioEntrySize) / soundData.SampleRate()))
+	entries, duration := composeSound(soundData)
 
-	for (startOffset + audioEntrySize) <= soundData.SampleCount() {
-		endOffset := startOffset + audioEntrySize
-		builder.AddEntry(NewMemoryEntry(entryStartTime, Audio, soundData.Samples(startOffset, endOffset)))
-		entryStartTime += timePerEntry
-		startOffset = endOffset
+	for _, entry := range entries {
+		builder.AddEntry(entry)
 	}
-	if startOffset < soundData.SampleCount() {
-		builder.AddEntry(NewMemoryEntry(entryStartTime, Audio, soundData.Samples(startOffset, soundData.SampleCount())))
-		entryStartTime += timeFromRaw(timeToRaw(float32(soundData.SampleCount()-startOffset) / soundData.SampleRate()))
-	}
-
-	builder.MediaDuration(entryStartTime)
+	builder.MediaDuration(duration)
 	builder.AudioSampleRate(uint16(soundData.SampleRate()))
 
-	container := builder.Build()
+	return builder.Build()
+}
+
+// ContainSoundData packs a sound data into a container and encodes it.
+func ContainSoundData(soundData audio.SoundData) []byte {
 	buffer := bytes.NewBuffer(nil)
-	Write(buffer, container)
+	Write(buffer, ContainSound(soundData))
 	return buffer.Bytes()
 }
@@ -0,0 +1,53 @@
+package movi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	goImage "image"
+
+	"github.com/inkyblackness/res/compress/video"
+)
+
+// EncodeHighResScene encodes the given frames as high resolution video and returns the entries
+// for a container. The control dictionary and the palette lookup list are placed at the timestamp
+// of the first frame, followed by one video entry for each frame at its respective timestamp.
+//
+// The frames are encoded as changes to their previous frame, starting from a cleared frame buffer;
+// See video.SceneEncoder for the restrictions this imposes.
+func EncodeHighResScene(frames []*goImage.Paletted, timestamps []float32) (entries []Entry, err error) {
+	if len(frames) == 0 {
+		return nil, fmt.Errorf("no frames given")
+	}
+	if len(frames) != len(timestamps) {
+		return nil, fmt.Errorf("%d timestamps given for %d frames", len(timestamps), len(frames))
+	}
+	bounds := frames[0].Bounds()
+	encoder := video.NewSceneEncoder(bounds.Dx(), bounds.Dy())
+	for index, frame := range frames {
+		err = encoder.AddFrame(frame)
+		if err != nil {
+			return nil, fmt.Errorf("frame %d: %v", index, err)
+		}
+	}
+	scene, err := encoder.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	entries = append(entries,
+		NewMemoryEntry(timestamps[0], ControlDictionary, video.PackControlWords(scene.ControlWords)),
+		NewMemoryEntry(timestamps[0], PaletteLookupList, scene.PaletteLookupList))
+	for index, frame := range scene.Frames {
+		pixelDataOffset := HighResVideoHeaderSize + len(frame.Bitstream)
+		if pixelDataOffset > 0xFFFF {
+			return nil, fmt.Errorf("frame %d: bitstream of %d bytes is too long", index, len(frame.Bitstream))
+		}
+		buf := bytes.NewBuffer(nil)
+		binary.Write(buf, binary.LittleEndian, &HighResVideoHeader{PixelDataOffset: uint16(pixelDataOffset)})
+		buf.Write(frame.Bitstream)
+		buf.Write(frame.Maskstream)
+		entries = append(entries, NewMemoryEntry(timestamps[index], HighResVideo, buf.Bytes()))
+	}
+	return entries, nil
+}
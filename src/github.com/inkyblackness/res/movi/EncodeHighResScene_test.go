@@ -0,0 +1,104 @@
+package movi
+
+import (
+	goImage "image"
+	"image/color"
+
+	check "gopkg.in/check.v1"
+)
+
+type EncodeHighResSceneSuite struct {
+}
+
+var _ = check.Suite(&EncodeHighResSceneSuite{})
+
+type collectedSubtitle struct {
+	timestamp float32
+	control   SubtitleControl
+	text      string
+}
+
+type collectingMediaHandler struct {
+	timestamps []float32
+	frames     []*goImage.Paletted
+	audio      []byte
+	subtitles  []collectedSubtitle
+}
+
+func (handler *collectingMediaHandler) OnAudio(timestamp float32, samples []byte) {
+	handler.audio = append(handler.audio, samples...)
+}
+
+func (handler *collectingMediaHandler) OnSubtitle(timestamp float32, control SubtitleControl, text string) {
+	handler.subtitles = append(handler.subtitles, collectedSubtitle{timestamp: timestamp, control: control, text: text})
+}
+
+func (handler *collectingMediaHandler) OnVideo(timestamp float32, frame *goImage.Paletted) {
+	handler.timestamps = append(handler.timestamps, timestamp)
+	handler.frames = append(handler.frames, frame)
+}
+
+func (suite *EncodeHighResSceneSuite) TestReturnsErrorForMismatchingTimestamps(c *check.C) {
+	_, err := EncodeHighResScene([]*goImage.Paletted{suite.frame(1)}, []float32{0.0, 1.0})
+
+	c.Check(err, check.NotNil)
+}
+
+func (suite *EncodeHighResSceneSuite) TestReturnsErrorForUnencodableFrames(c *check.C) {
+	_, err := EncodeHighResScene([]*goImage.Paletted{suite.frame(1), suite.frame(0)}, []float32{0.0, 1.0})
+
+	c.Check(err, check.ErrorMatches, "frame 1: .*")
+}
+
+func (suite *EncodeHighResSceneSuite) TestEntriesStartWithDictionaryAndLookupList(c *check.C) {
+	entries, err := EncodeHighResScene([]*goImage.Paletted{suite.frame(1), suite.frame(2)}, []float32{0.5, 1.0})
+
+	c.Assert(err, check.IsNil)
+	c.Assert(len(entries), check.Equals, 4)
+	c.Check(entries[0].Type(), check.Equals, ControlDictionary)
+	c.Check(entries[1].Type(), check.Equals, PaletteLookupList)
+	c.Check(entries[2].Type(), check.Equals, HighResVideo)
+	c.Check(entries[2].Timestamp(), check.Equals, float32(0.5))
+	c.Check(entries[3].Timestamp(), check.Equals, float32(1.0))
+}
+
+func (suite *EncodeHighResSceneSuite) TestEntriesAreDecodedByMediaDispatcher(c *check.C) {
+	frames := []*goImage.Paletted{suite.frame(1), suite.frame(2), suite.frame(3)}
+	entries, err := EncodeHighResScene(frames, []float32{0.0, 0.25, 0.5})
+	c.Assert(err, check.IsNil)
+	builder := NewContainerBuilder().VideoWidth(32).VideoHeight(16).StartPalette(suite.palette())
+	for _, entry := range entries {
+		builder.AddEntry(entry)
+	}
+	handler := &collectingMediaHandler{}
+	dispatcher := NewMediaDispatcher(builder.Build(), handler)
+
+	for more := true; more; {
+		more, err = dispatcher.DispatchNext()
+		c.Assert(err, check.IsNil)
+	}
+
+	c.Assert(len(handler.frames), check.Equals, len(frames))
+	for index, frame := range frames {
+		c.Check(handler.frames[index].Pix, check.DeepEquals, frame.Pix, check.Commentf("frame %d", index))
+	}
+	c.Check(handler.timestamps, check.DeepEquals, []float32{0.0, 0.25, 0.5})
+}
+
+func (suite *EncodeHighResSceneSuite) frame(seed int) *goImage.Paletted {
+	frame := goImage.NewPaletted(goImage.Rect(0, 0, 32, 16), suite.palette())
+	for index := range frame.Pix {
+		if seed != 0 {
+			frame.Pix[index] = byte(1 + (index*seed/3)%7 + (index/64)*seed)
+		}
+	}
+	return frame
+}
+
+func (suite *EncodeHighResSceneSuite) palette() color.Palette {
+	palette := make(color.Palette, 256)
+	for index := range palette {
+		palette[index] = color.NRGBA{R: byte(index), G: byte(index), B: byte(index), A: 0xFF}
+	}
+	return palette
+}
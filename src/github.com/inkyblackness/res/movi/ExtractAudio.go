@@ -4,25 +4,16 @@ import (
 	"bytes"
 
 	"github.com/inkyblackness/res/audio"
-	"github.com/inkyblackness/res/audio/mem"
 )
 
 // ExtractAudio decodes the given data array as a MOVI container and
-// extracts the audio track.
+// extracts the audio track. The samples of the returned data are taken from the
+// audio entries when they are requested. The returned data is safe for concurrent use.
 func ExtractAudio(data []byte) (soundData audio.SoundData, err error) {
 	container, err := Read(bytes.NewReader(data))
 
 	if container != nil {
-		var samples []byte
-
-		for i := 0; i < container.EntryCount(); i++ {
-			entry := container.Entry(i)
-
-			if entry.Type() == Audio {
-				samples = append(samples, entry.Data()...)
-			}
-		}
-		soundData = mem.NewL8SoundData(float32(container.AudioSampleRate()), samples)
+		soundData = audio.DataFromSource(NewAudioSource(container))
 	}
 	return
 }
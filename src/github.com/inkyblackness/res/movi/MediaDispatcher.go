@@ -3,12 +3,7 @@ package movi
 import (
 	"bytes"
 	"encoding/binary"
-	goImage "image"
-	"image/color"
 
-	"github.com/inkyblackness/res/compress/rle"
-	"github.com/inkyblackness/res/compress/video"
-	"github.com/inkyblackness/res/image"
 	"github.com/inkyblackness/res/text"
 )
 
@@ -22,24 +17,16 @@ type MediaDispatcher struct {
 
 	codepage text.Codepage
 
-	palette        color.Palette
-	decoderBuilder *video.FrameDecoderBuilder
-	frameBuffer    []byte
+	video *videoState
 }
 
 // NewMediaDispatcher returns a new instance of a dispatcher reading the provided container.
 func NewMediaDispatcher(container Container, handler MediaHandler) *MediaDispatcher {
-	width := int(container.VideoWidth())
-	height := int(container.VideoHeight())
 	dispatcher := &MediaDispatcher{
-		handler:        handler,
-		container:      container,
-		codepage:       text.DefaultCodepage(),
-		frameBuffer:    make([]byte, width*height),
-		decoderBuilder: video.NewFrameDecoderBuilder(width, height)}
-
-	dispatcher.setPalette(container.StartPalette())
-	dispatcher.decoderBuilder.ForStandardFrame(dispatcher.frameBuffer, width)
+		handler:   handler,
+		container: container,
+		codepage:  text.DefaultCodepage(),
+		video:     newVideoState(container)}
 
 	return dispatcher
 }
@@ -65,89 +52,25 @@ func (dispatcher *MediaDispatcher) process(entry Entry) (dispatched bool, err er
 		}
 	case Subtitle:
 		{
-			var subtitleHeader SubtitleHeader
-
-			binary.Read(bytes.NewReader(entry.Data()), binary.LittleEndian, &subtitleHeader)
-			subtitle := dispatcher.codepage.Decode(entry.Data()[SubtitleHeaderSize:])
-			dispatcher.handler.OnSubtitle(entry.Timestamp(), subtitleHeader.Control, subtitle)
+			control, subtitle := decodeSubtitle(dispatcher.codepage, entry.Data())
+			dispatcher.handler.OnSubtitle(entry.Timestamp(), control, subtitle)
 			dispatched = true
 		}
-
-	case Palette:
+	default:
 		{
-			newPalette, palErr := image.LoadPalette(bytes.NewReader(entry.Data()))
-
-			if palErr == nil {
-				dispatcher.setPalette(newPalette)
-				dispatcher.clearFrameBuffer()
-			} else {
-				err = palErr
+			dispatched, err = dispatcher.video.apply(entry)
+			if dispatched {
+				dispatcher.handler.OnVideo(entry.Timestamp(), dispatcher.video.frame())
 			}
 		}
-	case ControlDictionary:
-		{
-			words, wordsErr := video.UnpackControlWords(entry.Data())
-
-			if wordsErr == nil {
-				dispatcher.decoderBuilder.WithControlWords(words)
-			} else {
-				err = wordsErr
-			}
-		}
-	case PaletteLookupList:
-		{
-			dispatcher.decoderBuilder.WithPaletteLookupList(entry.Data())
-		}
-
-	case LowResVideo:
-		{
-			var videoHeader LowResVideoHeader
-			reader := bytes.NewReader(entry.Data())
-
-			binary.Read(reader, binary.LittleEndian, &videoHeader)
-			frameErr := rle.Decompress(reader, dispatcher.frameBuffer)
-			if frameErr == nil {
-				dispatcher.notifyVideoFrame(entry.Timestamp())
-				dispatched = true
-			} else {
-				err = frameErr
-			}
-		}
-	case HighResVideo:
-		{
-			var videoHeader HighResVideoHeader
-			reader := bytes.NewReader(entry.Data())
-
-			binary.Read(reader, binary.LittleEndian, &videoHeader)
-			bitstreamData := entry.Data()[HighResVideoHeaderSize:videoHeader.PixelDataOffset]
-			maskstreamData := entry.Data()[videoHeader.PixelDataOffset:]
-			decoder := dispatcher.decoderBuilder.Build()
-
-			decoder.Decode(bitstreamData, maskstreamData)
-			dispatcher.notifyVideoFrame(entry.Timestamp())
-			dispatched = true
-		}
 	}
 
 	return
 }
 
-func (dispatcher *MediaDispatcher) setPalette(newPalette color.Palette) {
-	dispatcher.palette = make([]color.Color, len(newPalette))
-
-	dispatcher.palette[0] = color.NRGBA{R: 0, G: 0, B: 0, A: 0xFF}
-	copy(dispatcher.palette[1:], newPalette[1:])
-}
-
-func (dispatcher *MediaDispatcher) clearFrameBuffer() {
-	for pixel := 0; pixel < len(dispatcher.frameBuffer); pixel++ {
-		dispatcher.frameBuffer[pixel] = 0x00
-	}
-}
+func decodeSubtitle(codepage text.Codepage, data []byte) (SubtitleControl, string) {
+	var subtitleHeader SubtitleHeader
 
-func (dispatcher *MediaDispatcher) notifyVideoFrame(timestamp float32) {
-	rect := goImage.Rect(0, 0, int(dispatcher.container.VideoWidth()), int(dispatcher.container.VideoHeight()))
-	paletted := goImage.NewPaletted(rect, dispatcher.palette)
-	copy(paletted.Pix, dispatcher.frameBuffer)
-	dispatcher.handler.OnVideo(timestamp, paletted)
+	binary.Read(bytes.NewReader(data), binary.LittleEndian, &subtitleHeader)
+	return subtitleHeader.Control, codepage.Decode(data[SubtitleHeaderSize:])
 }
@@ -0,0 +1,118 @@
+package movi
+
+import (
+	"bytes"
+	"encoding/binary"
+	goImage "image"
+	"image/color"
+
+	"github.com/inkyblackness/res/compress/rle"
+	"github.com/inkyblackness/res/compress/video"
+	"github.com/inkyblackness/res/image"
+)
+
+// videoState is everything the decoding of a video entry depends on.
+type videoState struct {
+	width  int
+	height int
+
+	palette           color.Palette
+	controlWords      []video.ControlWord
+	paletteLookupList []byte
+	frameBuffer       []byte
+}
+
+func newVideoState(container Container) *videoState {
+	width := int(container.VideoWidth())
+	height := int(container.VideoHeight())
+	state := &videoState{
+		width:       width,
+		height:      height,
+		frameBuffer: make([]byte, width*height)}
+
+	state.setPalette(container.StartPalette())
+	return state
+}
+
+// apply processes the given entry. It returns true if the entry completed a video frame.
+// Entries that do not affect the video are ignored.
+func (state *videoState) apply(entry Entry) (decoded bool, err error) {
+	switch entry.Type() {
+	case Palette:
+		{
+			newPalette, palErr := image.LoadPalette(bytes.NewReader(entry.Data()))
+
+			if palErr == nil {
+				state.setPalette(newPalette)
+				state.clearFrameBuffer()
+			} else {
+				err = palErr
+			}
+		}
+	case ControlDictionary:
+		{
+			words, wordsErr := video.UnpackControlWords(entry.Data())
+
+			if wordsErr == nil {
+				state.controlWords = words
+			} else {
+				err = wordsErr
+			}
+		}
+	case PaletteLookupList:
+		{
+			state.paletteLookupList = entry.Data()
+		}
+
+	case LowResVideo:
+		{
+			var videoHeader LowResVideoHeader
+			reader := bytes.NewReader(entry.Data())
+
+			binary.Read(reader, binary.LittleEndian, &videoHeader)
+			err = rle.Decompress(reader, state.frameBuffer)
+			decoded = err == nil
+		}
+	case HighResVideo:
+		{
+			var videoHeader HighResVideoHeader
+			reader := bytes.NewReader(entry.Data())
+
+			binary.Read(reader, binary.LittleEndian, &videoHeader)
+			bitstreamData := entry.Data()[HighResVideoHeaderSize:videoHeader.PixelDataOffset]
+			maskstreamData := entry.Data()[videoHeader.PixelDataOffset:]
+			builder := video.NewFrameDecoderBuilder(state.width, state.height)
+			builder.ForStandardFrame(state.frameBuffer, state.width)
+			builder.WithControlWords(state.controlWords)
+			builder.WithPaletteLookupList(state.paletteLookupList)
+			decoder := builder.Build()
+
+			decoder.Decode(bitstreamData, maskstreamData)
+			decoded = true
+		}
+	}
+
+	return
+}
+
+// frame returns a copy of the current frame buffer.
+func (state *videoState) frame() *goImage.Paletted {
+	paletted := goImage.NewPaletted(goImage.Rect(0, 0, state.width, state.height), state.palette)
+	copy(paletted.Pix, state.frameBuffer)
+	return paletted
+}
+
+func (state *videoState) setPalette(newPalette color.Palette) {
+	state.palette = make([]color.Color, len(newPalette))
+
+	if len(newPalette) > 0 {
+		state.palette[0] = color.NRGBA{R: 0, G: 0, B: 0, A: 0xFF}
+		copy(state.palette[1:], newPalette[1:])
+	}
+}
+
+func (state *videoState) clearFrameBuffer() {
+	for pixel := 0; pixel < len(state.frameBuffer); pixel++ {
+		state.frameBuffer[pixel] = 0x00
+	}
+}
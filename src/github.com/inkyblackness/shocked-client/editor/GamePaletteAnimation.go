@@ -0,0 +1,20 @@
+package editor
+
+import (
+	"time"
+
+	"github.com/inkyblackness/res/image"
+)
+
+// gameTick is the duration of one tick of the palette effects in the game.
+const gameTick = time.Second / 280
+
+// gamePaletteAnimation describes the rotating ranges of the game palette, as
+// they are shown for the lights and monitors in the world.
+var gamePaletteAnimation = image.PaletteAnimation{
+	{Range: image.IndexRange{First: 0x03, Count: 5}, Interval: 10 * gameTick},
+	{Range: image.IndexRange{First: 0x0B, Count: 5}, Interval: 7 * gameTick},
+	{Range: image.IndexRange{First: 0x10, Count: 5}, Interval: 5 * gameTick},
+	{Range: image.IndexRange{First: 0x15, Count: 3}, Interval: 17 * gameTick},
+	{Range: image.IndexRange{First: 0x18, Count: 3}, Interval: 25 * gameTick},
+	{Range: image.IndexRange{First: 0x1B, Count: 5}, Interval: 10 * gameTick}}
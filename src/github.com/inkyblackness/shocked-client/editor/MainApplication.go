@@ -2,6 +2,7 @@ package editor
 
 import (
 	"fmt"
+	"image/color"
 	"os"
 	"time"
 
@@ -52,6 +53,8 @@ type MainApplication struct {
 	gameObjectBitmaps    *graphics.BufferedTextureStore
 	gameObjectIcons      *graphics.BufferedTextureStore
 	worldPalette         *graphics.PaletteTexture
+	worldColors          color.Palette
+	worldPaletteTime     time.Duration
 	worldTextureRenderer *graphics.BitmapTextureRenderer
 }
 
@@ -183,17 +186,17 @@ func (app *MainApplication) createTextureStore(bitmaps *model.Bitmaps, request f
 }
 
 func (app *MainApplication) initWorldPalette() {
-	gamePalette := app.modelAdapter.GamePalette()
 	app.modelAdapter.OnGamePaletteChanged(func() {
-		gamePalette = app.modelAdapter.GamePalette()
+		app.updateWorldColors()
 		app.worldPalette.Update()
 	})
+	app.updateWorldColors()
 	app.worldPalette = graphics.NewPaletteTexture(app.gl, func(index int) (r byte, g byte, b byte, a byte) {
-		color := &gamePalette[index]
+		color := color.NRGBAModel.Convert(app.worldColors[index]).(color.NRGBA)
 
-		r = byte(color.Red)
-		g = byte(color.Green)
-		b = byte(color.Blue)
+		r = color.R
+		g = color.G
+		b = color.B
 		if index > 0 {
 			a = 0xFF
 		}
@@ -202,6 +205,28 @@ func (app *MainApplication) initWorldPalette() {
 	})
 }
 
+// updateWorldColors sets the colors of the world palette as they are shown
+// at the current time of the palette animation.
+func (app *MainApplication) updateWorldColors() {
+	gamePalette := app.modelAdapter.GamePalette()
+	base := make(color.Palette, len(gamePalette))
+	for index, entry := range gamePalette {
+		base[index] = entry
+	}
+	app.worldColors = gamePaletteAnimation.PaletteAt(base, app.worldPaletteTime)
+}
+
+// animateWorldPalette updates the world palette should the palette animation
+// have moved on since the last update.
+func (app *MainApplication) animateWorldPalette() {
+	now := time.Duration(app.elapsedMSec) * time.Millisecond
+	if gamePaletteAnimation.Changed(app.worldPaletteTime, now) {
+		app.worldPaletteTime = now
+		app.updateWorldColors()
+		app.worldPalette.Update()
+	}
+}
+
 func (app *MainApplication) initInterface() {
 	app.rectRenderer = graphics.NewRectangleRenderer(app.gl, &app.projectionMatrix)
 
@@ -249,6 +274,7 @@ func (app *MainApplication) render() {
 	gl.Clear(opengl.COLOR_BUFFER_BIT)
 
 	app.updateElapsedNano()
+	app.animateWorldPalette()
 	app.rootArea.Render()
 }
 
@@ -379,6 +405,11 @@ func (app *MainApplication) NewPaletteTexture(colorProvider graphics.ColorProvid
 	return graphics.NewPaletteTexture(app.gl, colorProvider)
 }
 
+// WorldPalette implements the graphics.Context interface.
+func (app *MainApplication) WorldPalette() *graphics.PaletteTexture {
+	return app.worldPalette
+}
+
 // WorldTextureStore implements the graphics.Context interface.
 func (app *MainApplication) WorldTextureStore(size dataModel.TextureSize) *graphics.BufferedTextureStore {
 	return app.worldTextures[size]
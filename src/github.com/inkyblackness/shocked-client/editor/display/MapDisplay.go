@@ -24,6 +24,7 @@ type MapDisplay struct {
 	renderContext *graphics.RenderContext
 
 	paletteTexture *graphics.PaletteTexture
+	shadingTexture *graphics.ShadingTexture
 
 	highlighter *BasicHighlighter
 	background  *GridRenderable
@@ -80,19 +81,23 @@ func NewMapDisplay(context Context, parent *ui.Area, scale float32) *MapDisplay
 		display.area = builder.Build()
 	}
 
-	display.paletteTexture = context.ForGraphics().NewPaletteTexture(display.paletteEntry)
-	display.context.ModelAdapter().OnGamePaletteChanged(func() {
-		display.paletteTexture.Update()
-	})
+	display.paletteTexture = context.ForGraphics().WorldPalette()
 
 	display.renderContext = context.NewRenderContext(display.camera.ViewMatrix())
 	display.highlighter = NewBasicHighlighter(display.renderContext)
 	display.background = NewGridRenderable(display.renderContext)
 	display.mapGrid = NewTileGridMapRenderable(display.renderContext)
-	display.textures = NewTileTextureMapRenderable(display.renderContext, display.paletteTexture, func(index int) *graphics.BitmapTexture {
-		id := display.levelAdapter.LevelTextureID(index)
-		return display.context.ForGraphics().WorldTextureStore(dataModel.TextureLarge).Texture(graphics.TextureKeyFromInt(id))
+	display.shadingTexture = graphics.NewShadingTexture(display.renderContext.OpenGl(), func() [][graphics.ColorsPerPalette]byte {
+		return display.context.ModelAdapter().ShadingTable().Levels
+	})
+	display.context.ModelAdapter().OnShadingTableChanged(func() {
+		display.shadingTexture.Update()
 	})
+	display.textures = NewTileTextureMapRenderable(display.renderContext, display.paletteTexture, display.shadingTexture,
+		func(index int) *graphics.BitmapTexture {
+			id := display.levelAdapter.LevelTextureID(index)
+			return display.context.ForGraphics().WorldTextureStore(dataModel.TextureLarge).Texture(graphics.TextureKeyFromInt(id))
+		})
 	display.colors = NewTileColorMapRenderable(display.renderContext)
 	display.slopeGrid = NewTileSlopeMapRenderable(display.renderContext)
 	display.objects = NewPlacedIconsRenderable(display.renderContext, display.paletteTexture)
@@ -118,20 +123,6 @@ func NewMapDisplay(context Context, parent *ui.Area, scale float32) *MapDisplay
 	return display
 }
 
-func (display *MapDisplay) paletteEntry(index int) (r, g, b, a byte) {
-	pal := display.context.ModelAdapter().GamePalette()
-	color := &pal[index]
-
-	r = byte(color.Red)
-	g = byte(color.Green)
-	b = byte(color.Blue)
-	if index > 0 {
-		a = 0xFF
-	}
-
-	return
-}
-
 // SetVisible sets the display visibility state.
 func (display *MapDisplay) SetVisible(visible bool) {
 	display.area.SetVisible(visible)
@@ -142,6 +133,11 @@ func (display *MapDisplay) SetTextureIndexQuery(query TextureIndexQuery) {
 	display.textures.SetTextureIndexQuery(query)
 }
 
+// SetShadowQuery sets which light levels the textures shall be shown with.
+func (display *MapDisplay) SetShadowQuery(query ShadowQuery) {
+	display.textures.SetShadowQuery(query)
+}
+
 // SetHighlightedTile requests to highlight the identified tile.
 func (display *MapDisplay) SetHighlightedTile(coord model.TileCoordinate) {
 	tileX, tileY := coord.XY()
@@ -40,13 +40,20 @@ in vec2 uv;
 
 uniform sampler2D palette;
 uniform sampler2D bitmap;
+uniform sampler2D shading;
+uniform int shadingLevel;
+uniform int shadingLevelCount;
 
 out vec4 fragColor;
 
 void main(void) {
 	vec4 pixel = texture(bitmap, uv);
+	float index = pixel.a;
 
-	fragColor = texture(palette, vec2(pixel.a, 0.5));
+	if ((shadingLevel >= 0) && (index > 0.0)) {
+		index = texture(shading, vec2(index, (float(shadingLevel) + 0.5) / float(shadingLevelCount))).a;
+	}
+	fragColor = texture(palette, vec2(index, 0.5));
 }
 `
 
@@ -73,6 +80,19 @@ func WallTexture(properties *model.RealWorldTileProperties) (textureIndex int, t
 	return *properties.WallTexture, 0
 }
 
+// ShadowQuery is a getter function to retrieve the light level from given tile properties.
+type ShadowQuery func(properties *model.RealWorldTileProperties) int
+
+// FloorShadowLevel returns the light level of the floor.
+func FloorShadowLevel(properties *model.RealWorldTileProperties) int {
+	return *properties.FloorShadow
+}
+
+// CeilingShadowLevel returns the light level of the ceiling.
+func CeilingShadowLevel(properties *model.RealWorldTileProperties) int {
+	return *properties.CeilingShadow
+}
+
 // TileTextureMapRenderable is a renderable for textures.
 type TileTextureMapRenderable struct {
 	context *graphics.RenderContext
@@ -86,12 +106,17 @@ type TileTextureMapRenderable struct {
 	projectionMatrixUniform opengl.Matrix4Uniform
 	uvMatrixUniform         opengl.Matrix4Uniform
 
-	paletteUniform int32
-	bitmapUniform  int32
+	paletteUniform           int32
+	bitmapUniform            int32
+	shadingUniform           int32
+	shadingLevelUniform      int32
+	shadingLevelCountUniform int32
 
 	paletteTexture    graphics.Texture
+	shadingTexture    *graphics.ShadingTexture
 	textureIndexQuery TextureIndexQuery
 	textureQuery      TextureQuery
+	shadowQuery       ShadowQuery
 
 	tiles        [][]*model.TileProperties
 	lastTileType model.TileType
@@ -111,8 +136,9 @@ func init() {
 }
 
 // NewTileTextureMapRenderable returns a new instance of a renderable for tile map textures.
+// The shading texture is used to show the textures at the light levels of the tiles.
 func NewTileTextureMapRenderable(context *graphics.RenderContext, paletteTexture graphics.Texture,
-	textureQuery TextureQuery) *TileTextureMapRenderable {
+	shadingTexture *graphics.ShadingTexture, textureQuery TextureQuery) *TileTextureMapRenderable {
 	gl := context.OpenGl()
 	program, programErr := opengl.LinkNewStandardProgram(gl, mapTileVertexShaderSource, mapTileFragmentShaderSource)
 
@@ -123,20 +149,24 @@ func NewTileTextureMapRenderable(context *graphics.RenderContext, paletteTexture
 		context: context,
 		program: program,
 
-		vao:                     opengl.NewVertexArrayObject(gl, program),
-		vertexPositionBuffer:    gl.GenBuffers(1)[0],
-		vertexPositionAttrib:    gl.GetAttribLocation(program, "vertexPosition"),
-		modelMatrixUniform:      opengl.Matrix4Uniform(gl.GetUniformLocation(program, "modelMatrix")),
-		viewMatrixUniform:       opengl.Matrix4Uniform(gl.GetUniformLocation(program, "viewMatrix")),
-		projectionMatrixUniform: opengl.Matrix4Uniform(gl.GetUniformLocation(program, "projectionMatrix")),
-		uvMatrixUniform:         opengl.Matrix4Uniform(gl.GetUniformLocation(program, "uvMatrix")),
-		paletteUniform:          gl.GetUniformLocation(program, "palette"),
-		bitmapUniform:           gl.GetUniformLocation(program, "bitmap"),
-		paletteTexture:          paletteTexture,
-		textureIndexQuery:       FloorTexture,
-		textureQuery:            textureQuery,
-		tiles:                   make([][]*model.TileProperties, int(tilesPerMapSide)),
-		lastTileType:            model.Solid}
+		vao:                      opengl.NewVertexArrayObject(gl, program),
+		vertexPositionBuffer:     gl.GenBuffers(1)[0],
+		vertexPositionAttrib:     gl.GetAttribLocation(program, "vertexPosition"),
+		modelMatrixUniform:       opengl.Matrix4Uniform(gl.GetUniformLocation(program, "modelMatrix")),
+		viewMatrixUniform:        opengl.Matrix4Uniform(gl.GetUniformLocation(program, "viewMatrix")),
+		projectionMatrixUniform:  opengl.Matrix4Uniform(gl.GetUniformLocation(program, "projectionMatrix")),
+		uvMatrixUniform:          opengl.Matrix4Uniform(gl.GetUniformLocation(program, "uvMatrix")),
+		paletteUniform:           gl.GetUniformLocation(program, "palette"),
+		bitmapUniform:            gl.GetUniformLocation(program, "bitmap"),
+		shadingUniform:           gl.GetUniformLocation(program, "shading"),
+		shadingLevelUniform:      gl.GetUniformLocation(program, "shadingLevel"),
+		shadingLevelCountUniform: gl.GetUniformLocation(program, "shadingLevelCount"),
+		paletteTexture:           paletteTexture,
+		shadingTexture:           shadingTexture,
+		textureIndexQuery:        FloorTexture,
+		textureQuery:             textureQuery,
+		tiles:                    make([][]*model.TileProperties, int(tilesPerMapSide)),
+		lastTileType:             model.Solid}
 
 	for i := 0; i < len(renderable.tiles); i++ {
 		renderable.tiles[i] = make([]*model.TileProperties, int(tilesPerMapSide))
@@ -165,6 +195,12 @@ func (renderable *TileTextureMapRenderable) SetTextureIndexQuery(query TextureIn
 	renderable.textureIndexQuery = query
 }
 
+// SetShadowQuery sets which light levels shall be shown. A nil query shows the
+// textures at full brightness.
+func (renderable *TileTextureMapRenderable) SetShadowQuery(query ShadowQuery) {
+	renderable.shadowQuery = query
+}
+
 // SetTile sets the properties for the specified tile coordinate.
 func (renderable *TileTextureMapRenderable) SetTile(x, y int, properties *model.TileProperties) {
 	renderable.tiles[y][x] = properties
@@ -192,6 +228,13 @@ func (renderable *TileTextureMapRenderable) Render() {
 		gl.BindTexture(opengl.TEXTURE_2D, renderable.paletteTexture.Handle())
 		gl.Uniform1i(renderable.paletteUniform, textureUnit)
 
+		textureUnit = 2
+		gl.ActiveTexture(opengl.TEXTURE0 + uint32(textureUnit))
+		gl.BindTexture(opengl.TEXTURE_2D, renderable.shadingTexture.Handle())
+		gl.Uniform1i(renderable.shadingUniform, textureUnit)
+		gl.Uniform1i(renderable.shadingLevelCountUniform, int32(renderable.shadingTexture.LevelCount()))
+		gl.Uniform1i(renderable.shadingLevelUniform, -1)
+
 		textureUnit = 1
 		gl.ActiveTexture(opengl.TEXTURE0 + uint32(textureUnit))
 
@@ -211,6 +254,9 @@ func (renderable *TileTextureMapRenderable) Render() {
 						verticeCount := renderable.ensureTileType(*tile.Type)
 						gl.BindTexture(opengl.TEXTURE_2D, texture.Handle())
 						gl.Uniform1i(renderable.bitmapUniform, textureUnit)
+						if renderable.shadowQuery != nil {
+							gl.Uniform1i(renderable.shadingLevelUniform, int32(renderable.shadowQuery(tile.RealWorld)))
+						}
 
 						gl.DrawArrays(opengl.TRIANGLES, 0, int32(verticeCount))
 					}
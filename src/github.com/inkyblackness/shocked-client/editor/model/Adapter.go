@@ -20,6 +20,7 @@ type Adapter struct {
 	availableLevelIDs *observable
 
 	palette            *observable
+	shadingTable       *observable
 	bitmapsAdapter     *BitmapsAdapter
 	textAdapter        *TextAdapter
 	soundAdapter       *SoundAdapter
@@ -40,7 +41,8 @@ func NewAdapter(store model.DataStore) *Adapter {
 
 		availableLevelIDs: newObservable(),
 
-		palette: newObservable()}
+		palette:      newObservable(),
+		shadingTable: newObservable()}
 
 	adapter.message.set("")
 	adapter.bitmapsAdapter = newBitmapsAdapter(adapter, store)
@@ -51,6 +53,7 @@ func NewAdapter(store model.DataStore) *Adapter {
 	adapter.activeLevel = newLevelAdapter(adapter, store, adapter.objectsAdapter)
 	adapter.electronicMessages = newElectronicMessageAdapter(adapter, store)
 	adapter.palette.set(&[256]model.Color{})
+	adapter.shadingTable.set(&model.ShadingTable{})
 
 	return adapter
 }
@@ -102,6 +105,8 @@ func (adapter *Adapter) RequestProject(projectID string) {
 		adapter.requestArchive("archive")
 		adapter.store.Palette(adapter.ActiveProjectID(), "game",
 			adapter.onGamePalette, adapter.simpleStoreFailure("Palette"))
+		adapter.store.ShadingTable(adapter.ActiveProjectID(),
+			adapter.onShadingTable, adapter.simpleStoreFailure("ShadingTable"))
 		adapter.objectsAdapter.refresh()
 		adapter.textureAdapter.refresh()
 		adapter.bitmapsAdapter.refresh()
@@ -127,6 +132,21 @@ func (adapter *Adapter) OnGamePaletteChanged(callback func()) {
 	adapter.palette.addObserver(callback)
 }
 
+func (adapter *Adapter) onShadingTable(table model.ShadingTable) {
+	adapter.shadingTable.set(&table)
+}
+
+// ShadingTable returns the table of light levels for the main palette.
+// The table has no levels until it has been loaded.
+func (adapter *Adapter) ShadingTable() *model.ShadingTable {
+	return adapter.shadingTable.get().(*model.ShadingTable)
+}
+
+// OnShadingTableChanged registers a callback for updates.
+func (adapter *Adapter) OnShadingTableChanged(callback func()) {
+	adapter.shadingTable.addObserver(callback)
+}
+
 // BitmapsAdapter returns the adapter for bitmaps.
 func (adapter *Adapter) BitmapsAdapter() *BitmapsAdapter {
 	return adapter.bitmapsAdapter
@@ -289,12 +289,12 @@ func (mode *GameTextsMode) importAudio(filePath string) {
 		defer func() {
 			_ = file.Close()
 		}()
-		data, dataErr := wav.Load(file)
+		data, dataErr := wav.LoadSoundData(file, 0)
 
 		if dataErr == nil {
 			mode.requestAudioChange(data)
 		} else {
-			mode.context.ModelAdapter().SetMessage("File not supported. Only .wav files with 16bit or 8bit LPCM possible.")
+			mode.context.ModelAdapter().SetMessage(fmt.Sprintf("File not supported: %v", dataErr))
 		}
 	} else {
 		mode.context.ModelAdapter().SetMessage(fmt.Sprintf("File could not be opened: %s", filePath))
@@ -27,6 +27,7 @@ func (item *coloringItem) String() string {
 type textureViewItem struct {
 	displayString string
 	query         display.TextureIndexQuery
+	shadowQuery   display.ShadowQuery
 }
 
 func (item *textureViewItem) String() string {
@@ -319,11 +320,13 @@ func NewLevelMapMode(context Context, parent *ui.Area, mapDisplay *display.MapDi
 
 			{
 				mode.textureViewLabel, mode.textureViewBox = realWorldPanelBuilder.addComboProperty("Map Texture View", mode.onTextureViewChanged)
-				items := make([]controls.ComboBoxItem, 3)
+				items := make([]controls.ComboBoxItem, 5)
 
-				items[0] = &textureViewItem{"Floor", display.FloorTexture}
-				items[1] = &textureViewItem{"Ceiling", display.CeilingTexture}
-				items[2] = &textureViewItem{"Wall", display.WallTexture}
+				items[0] = &textureViewItem{"Floor", display.FloorTexture, nil}
+				items[1] = &textureViewItem{"Ceiling", display.CeilingTexture, nil}
+				items[2] = &textureViewItem{"Wall", display.WallTexture, nil}
+				items[3] = &textureViewItem{"Floor (shaded)", display.FloorTexture, display.FloorShadowLevel}
+				items[4] = &textureViewItem{"Ceiling (shaded)", display.CeilingTexture, display.CeilingShadowLevel}
 
 				mode.textureViewBox.SetItems(items)
 				mode.textureViewBox.SetSelectedItem(items[0])
@@ -814,6 +817,7 @@ func (mode *LevelMapMode) onTilePropertyChangeRequested(item controls.ComboBoxIt
 func (mode *LevelMapMode) onTextureViewChanged(boxItem controls.ComboBoxItem) {
 	item := boxItem.(*textureViewItem)
 	mode.mapDisplay.SetTextureIndexQuery(item.query)
+	mode.mapDisplay.SetShadowQuery(item.shadowQuery)
 }
 
 func (mode *LevelMapMode) onFloorTextureChanged(index int) {
@@ -12,6 +12,8 @@ type Context interface {
 	UITextRenderer() *BitmapTextureRenderer
 
 	NewPaletteTexture(colorProvider ColorProvider) *PaletteTexture
+	// WorldPalette returns the game palette, animated as in the game.
+	WorldPalette() *PaletteTexture
 	BitmapsStore() *BufferedTextureStore
 	WorldTextureStore(size model.TextureSize) *BufferedTextureStore
 	GameObjectBitmapsStore() *BufferedTextureStore
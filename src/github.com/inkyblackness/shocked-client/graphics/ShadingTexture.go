@@ -0,0 +1,83 @@
+package graphics
+
+import (
+	"github.com/inkyblackness/shocked-client/opengl"
+)
+
+// ShadingProvider is a function to return the palette index mappings of all light levels.
+type ShadingProvider func() [][ColorsPerPalette]byte
+
+// ShadingTexture contains a shading table stored as OpenGL texture.
+// Each row of the texture is the mapping of one light level, stored in all
+// channels of the texels. Without any level, the texture contains one level
+// that maps all indices to themselves.
+type ShadingTexture struct {
+	gl opengl.OpenGl
+
+	shadingProvider ShadingProvider
+	handle          uint32
+	levelCount      int
+}
+
+// NewShadingTexture creates a new ShadingTexture instance.
+func NewShadingTexture(gl opengl.OpenGl, shadingProvider ShadingProvider) *ShadingTexture {
+	tex := &ShadingTexture{
+		gl:              gl,
+		shadingProvider: shadingProvider,
+		handle:          gl.GenTextures(1)[0]}
+
+	tex.Update()
+
+	return tex
+}
+
+// Dispose implements the GraphicsTexture interface.
+func (tex *ShadingTexture) Dispose() {
+	if tex.handle != 0 {
+		tex.gl.DeleteTextures([]uint32{tex.handle})
+		tex.handle = 0
+	}
+}
+
+// Handle returns the texture handle.
+func (tex *ShadingTexture) Handle() uint32 {
+	return tex.handle
+}
+
+// LevelCount returns the number of light levels in the texture.
+func (tex *ShadingTexture) LevelCount() int {
+	return tex.levelCount
+}
+
+// Update reloads the shading table.
+func (tex *ShadingTexture) Update() {
+	gl := tex.gl
+	levels := tex.shadingProvider()
+
+	if len(levels) == 0 {
+		var identity [ColorsPerPalette]byte
+		for index := range identity {
+			identity[index] = byte(index)
+		}
+		levels = [][ColorsPerPalette]byte{identity}
+	}
+	tex.levelCount = len(levels)
+	data := make([]byte, len(levels)*ColorsPerPalette*BytesPerRgba)
+	for level, mapping := range levels {
+		for index, value := range mapping {
+			offset := (level*ColorsPerPalette + index) * BytesPerRgba
+			data[offset+0] = value
+			data[offset+1] = value
+			data[offset+2] = value
+			data[offset+3] = value
+		}
+	}
+
+	gl.BindTexture(opengl.TEXTURE_2D, tex.handle)
+	gl.TexImage2D(opengl.TEXTURE_2D, 0, opengl.RGBA, ColorsPerPalette, int32(tex.levelCount),
+		0, opengl.RGBA, opengl.UNSIGNED_BYTE, data)
+	gl.TexParameteri(opengl.TEXTURE_2D, opengl.TEXTURE_MAG_FILTER, opengl.NEAREST)
+	gl.TexParameteri(opengl.TEXTURE_2D, opengl.TEXTURE_MIN_FILTER, opengl.NEAREST)
+	gl.GenerateMipmap(opengl.TEXTURE_2D)
+	gl.BindTexture(opengl.TEXTURE_2D, 0)
+}
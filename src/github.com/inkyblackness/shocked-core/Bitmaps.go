@@ -1,10 +1,12 @@
 package core
 
 import (
-	"bytes"
 	"fmt"
+	goimage "image"
+	"image/color"
 
 	"github.com/inkyblackness/res"
+	"github.com/inkyblackness/res/chunk"
 	"github.com/inkyblackness/res/image"
 	"github.com/inkyblackness/shocked-core/io"
 	model "github.com/inkyblackness/shocked-model"
@@ -12,11 +14,13 @@ import (
 
 // Bitmaps is the adapter for general bitmaps.
 type Bitmaps struct {
-	mfdArt [model.LanguageCount]*io.DynamicChunkStore
+	mfdArt   [model.LanguageCount]*io.DynamicChunkStore
+	palettes *Palettes
 }
 
 // NewBitmaps returns a new Bitmaps instance, if possible.
-func NewBitmaps(library io.StoreLibrary) (bitmaps *Bitmaps, err error) {
+// Images that are set are mapped to the game palette of given palettes.
+func NewBitmaps(library io.StoreLibrary, palettes *Palettes) (bitmaps *Bitmaps, err error) {
 	var mfdArt [model.LanguageCount]*io.DynamicChunkStore
 
 	for i := 0; i < model.LanguageCount && err == nil; i++ {
@@ -24,7 +28,7 @@ func NewBitmaps(library io.StoreLibrary) (bitmaps *Bitmaps, err error) {
 	}
 
 	if err == nil {
-		bitmaps = &Bitmaps{mfdArt: mfdArt}
+		bitmaps = &Bitmaps{mfdArt: mfdArt, palettes: palettes}
 	}
 
 	return
@@ -33,10 +37,12 @@ func NewBitmaps(library io.StoreLibrary) (bitmaps *Bitmaps, err error) {
 // Image returns the image data of identified bitmap.
 func (bitmaps *Bitmaps) Image(key model.ResourceKey) (bmp image.Bitmap, err error) {
 	var blockData []byte
+	contentType := chunk.Bitmap
 
 	if (key.Type == model.ResourceTypeMfdDataImages) && key.HasValidLanguage() {
 		holder := bitmaps.mfdArt[key.Language.ToIndex()].Get(res.ResourceID(key.Type))
 		if key.Index < holder.BlockCount() {
+			contentType = holder.ContentType()
 			blockData = holder.BlockData(key.Index)
 		}
 	} else {
@@ -44,7 +50,14 @@ func (bitmaps *Bitmaps) Image(key model.ResourceKey) (bmp image.Bitmap, err erro
 	}
 
 	if (err == nil) && (len(blockData) > 0) {
-		bmp, err = image.Read(bytes.NewReader(blockData))
+		var value interface{}
+		value, err = contents.DecodeData(contentType, blockData)
+		if err == nil {
+			var isBitmap bool
+			if bmp, isBitmap = value.(image.Bitmap); !isBitmap {
+				err = fmt.Errorf("Resource %v is not a bitmap", key)
+			}
+		}
 	} else {
 		bmp = image.NullBitmap()
 	}
@@ -53,7 +66,9 @@ func (bitmaps *Bitmaps) Image(key model.ResourceKey) (bmp image.Bitmap, err erro
 }
 
 // SetImage requests to set the bitmap data of a resource.
-func (bitmaps *Bitmaps) SetImage(key model.ResourceKey, bmp image.Bitmap) (resultKey model.ResourceKey, err error) {
+// Images in the game palette are stored as they are. All other images,
+// including true-color ones, are quantized to the game palette first.
+func (bitmaps *Bitmaps) SetImage(key model.ResourceKey, img goimage.Image) (resultKey model.ResourceKey, err error) {
 	if (key.Type == model.ResourceTypeMfdDataImages) && key.HasValidLanguage() {
 		holder := bitmaps.mfdArt[key.Language.ToIndex()].Get(res.ResourceID(key.Type))
 		insertIndex := key.Index
@@ -62,13 +77,50 @@ func (bitmaps *Bitmaps) SetImage(key model.ResourceKey, bmp image.Bitmap) (resul
 		if insertIndex >= available {
 			insertIndex = available
 		}
-		writer := bytes.NewBuffer(nil)
-		image.Write(writer, bmp, image.CompressedBitmap, true, 0)
-		holder.SetBlockData(insertIndex, writer.Bytes())
-		resultKey = model.MakeLocalizedResourceKey(key.Type, key.Language, insertIndex)
+		var paletted *goimage.Paletted
+		paletted, err = bitmaps.inGamePalette(img)
+		if err == nil {
+			var blockData []byte
+			bmp := image.WithStorage(image.ToBitmap(paletted, nil), image.CompressedBitmap, true)
+			blockData, err = contents.EncodeData(holder.ContentType(), bmp)
+			if err == nil {
+				holder.SetBlockData(insertIndex, blockData)
+				resultKey = model.MakeLocalizedResourceKey(key.Type, key.Language, insertIndex)
+			}
+		}
 	} else {
 		err = fmt.Errorf("Unsupported resource key %v", key)
 	}
 
 	return
 }
+
+// inGamePalette returns the given image with pixels of the game palette.
+func (bitmaps *Bitmaps) inGamePalette(img goimage.Image) (*goimage.Paletted, error) {
+	gamePalette, err := bitmaps.palettes.GamePalette()
+	if err != nil {
+		return nil, err
+	}
+	if paletted, isPaletted := img.(*goimage.Paletted); isPaletted && isSamePalette(paletted.Palette, gamePalette) {
+		return paletted, nil
+	}
+	quantizer, err := image.NewQuantizer(gamePalette, image.FloydSteinbergDithering)
+	if err != nil {
+		return nil, err
+	}
+
+	return quantizer.Quantize(img), nil
+}
+
+func isSamePalette(a, b color.Palette) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for index := range a {
+		if color.NRGBAModel.Convert(a[index]) != color.NRGBAModel.Convert(b[index]) {
+			return false
+		}
+	}
+
+	return true
+}
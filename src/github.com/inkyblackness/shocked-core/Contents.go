@@ -0,0 +1,8 @@
+package core
+
+import (
+	"github.com/inkyblackness/res/chunk/content"
+)
+
+// contents decodes and encodes the blocks of all known content types.
+var contents = content.NewRegistry()
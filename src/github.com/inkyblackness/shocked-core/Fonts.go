@@ -1,7 +1,6 @@
 package core
 
 import (
-	"bytes"
 	"encoding/base64"
 	"fmt"
 
@@ -34,9 +33,12 @@ func NewFonts(library io.StoreLibrary) (fonts *Fonts, err error) {
 func (fonts *Fonts) Font(id res.ResourceID) (font *model.Font, err error) {
 	fontChunk := fonts.gamescr.Get(id)
 	if fontChunk.ContentType() == chunk.Font {
-		fontBlockData := fontChunk.BlockData(0)
-		var fontData resFont.Font
-		fontData, err = resFont.Load(bytes.NewReader(fontBlockData))
+		var value interface{}
+		value, err = contents.DecodeData(fontChunk.ContentType(), fontChunk.BlockData(0))
+		fontData, isFont := value.(resFont.Font)
+		if (err == nil) && !isFont {
+			err = fmt.Errorf("Block of ID %v does not contain a font", id)
+		}
 
 		if err == nil {
 			isMonochrome := fontData.IsMonochrome()
@@ -57,7 +59,7 @@ func (fonts *Fonts) Font(id res.ResourceID) (font *model.Font, err error) {
 				font.GlyphXOffsets[charIndex] = fontData.GlyphXOffset(charIndex)
 			}
 		} else {
-			err = fmt.Errorf("Failed to load font ID %v", id)
+			err = fmt.Errorf("Failed to load font ID %v: %v", id, err)
 		}
 	} else {
 		err = fmt.Errorf("ID %v is not a font", id)
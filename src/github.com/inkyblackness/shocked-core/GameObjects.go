@@ -129,8 +129,10 @@ func (gameObjects *GameObjects) SetBitmap(id res.ObjectID, index int, bmp image.
 		blockIndex := uint16(gameObjects.objIconOffsets[id] + index)
 		holder := gameObjects.objart.Get(res.ResourceID(0x0546)) //.BlockData(uint16(gameObjects.objIconOffsets[id] + index))
 		buf := bytes.NewBuffer(nil)
-		image.Write(buf, bmp, image.CompressedBitmap, true, 0)
-		holder.SetBlockData(blockIndex, buf.Bytes())
+		err = image.Write(buf, bmp, image.CompressedBitmap, true, 0)
+		if err == nil {
+			holder.SetBlockData(blockIndex, buf.Bytes())
+		}
 	} else {
 		err = fmt.Errorf("Object index out of range: %v[%d]", id, index)
 	}
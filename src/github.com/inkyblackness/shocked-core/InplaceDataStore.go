@@ -134,10 +134,13 @@ func (inplace *InplaceDataStore) SetBitmap(projectID string, key model.ResourceK
 
 		if err == nil {
 			bitmaps := project.Bitmaps()
-			imgBitmap := inplace.fromRawBitmap(rawBitmap)
+			var gamePalette color.Palette
 			var resultKey model.ResourceKey
 
-			resultKey, err = bitmaps.SetImage(key, imgBitmap)
+			gamePalette, err = project.Palettes().GamePalette()
+			if err == nil {
+				resultKey, err = bitmaps.SetImage(key, image.FromBitmap(inplace.fromRawBitmap(rawBitmap), gamePalette))
+			}
 
 			if err == nil {
 				var imgResult image.Bitmap
@@ -473,6 +476,31 @@ func (inplace *InplaceDataStore) Palette(projectID string, paletteID string,
 	})
 }
 
+// ShadingTable implements the model.DataStore interface
+func (inplace *InplaceDataStore) ShadingTable(projectID string,
+	onSuccess func(table model.ShadingTable), onFailure model.FailureFunc) {
+	inplace.in(func() {
+		project, err := inplace.workspace.Project(projectID)
+
+		if err == nil {
+			var table *image.ShadingTable
+			table, err = project.Palettes().ShadingTable()
+			if err == nil {
+				var entity model.ShadingTable
+
+				entity.Levels = make([][256]byte, table.LevelCount())
+				for level := range entity.Levels {
+					entity.Levels[level] = table.Level(level)
+				}
+				inplace.out(func() { onSuccess(entity) })
+			}
+		}
+		if err != nil {
+			inplace.out(onFailure)
+		}
+	})
+}
+
 func (inplace *InplaceDataStore) encodePalette(out *[256]model.Color, palette color.Palette) {
 	for index, inColor := range palette {
 		outColor := &out[index]
@@ -721,11 +749,13 @@ func (inplace *InplaceDataStore) SetTextureBitmap(projectID string, textureID in
 		if err == nil {
 			textures := project.Textures()
 			imgBitmap := inplace.fromRawBitmap(rawBitmap)
-			textures.SetImage(textureID, model.TextureSize(size), imgBitmap)
-			imgResult := textures.Image(textureID, model.TextureSize(size))
-			rawResult := inplace.toRawBitmap(imgResult)
+			err = textures.SetImage(textureID, model.TextureSize(size), imgBitmap)
+			if err == nil {
+				imgResult := textures.Image(textureID, model.TextureSize(size))
+				rawResult := inplace.toRawBitmap(imgResult)
 
-			inplace.out(func() { onSuccess(&rawResult) })
+				inplace.out(func() { onSuccess(&rawResult) })
+			}
 		}
 		if err != nil {
 			inplace.out(onFailure)
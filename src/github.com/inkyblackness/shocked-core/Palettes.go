@@ -2,6 +2,7 @@ package core
 
 import (
 	"bytes"
+	"fmt"
 	"image/color"
 
 	"github.com/inkyblackness/res"
@@ -27,7 +28,31 @@ func NewPalettes(library io.StoreLibrary) (palettes *Palettes, err error) {
 }
 
 func (palettes *Palettes) GamePalette() (color.Palette, error) {
-	blockData := palettes.gamepal.Get(res.ResourceID(0x02BC)).BlockData(0)
+	holder := palettes.gamepal.Get(res.ResourceID(0x02BC))
+	if holder == nil {
+		return nil, fmt.Errorf("No game palette available")
+	}
+
+	return image.LoadPalette(bytes.NewReader(holder.BlockData(0)))
+}
+
+// ShadingTable returns the table of light levels for the game palette.
+func (palettes *Palettes) ShadingTable() (*image.ShadingTable, error) {
+	holder := palettes.gamepal.Get(res.ResourceID(0x02BD))
+	if holder == nil {
+		return nil, fmt.Errorf("No shading table available")
+	}
+
+	return image.LoadShadingTable(bytes.NewReader(holder.BlockData(0)))
+}
+
+// TranslucencyTable returns the table of the translucent color with given index.
+// The tables are stored in gamepal.res as blocks of the chunk following the shading table.
+func (palettes *Palettes) TranslucencyTable(index int) (*image.TranslucencyTable, error) {
+	holder := palettes.gamepal.Get(res.ResourceID(0x02BE))
+	if (holder == nil) || (index < 0) || (index >= int(holder.BlockCount())) {
+		return nil, fmt.Errorf("No translucency table %d available", index)
+	}
 
-	return image.LoadPalette(bytes.NewReader(blockData))
+	return image.LoadTranslucencyTable(bytes.NewReader(holder.BlockData(uint16(index))))
 }
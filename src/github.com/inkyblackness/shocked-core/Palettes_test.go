@@ -0,0 +1,69 @@
+package core
+
+import (
+	"github.com/inkyblackness/res"
+	"github.com/inkyblackness/res/chunk"
+	"github.com/inkyblackness/res/chunk/resfile"
+	"github.com/inkyblackness/res/image"
+	"github.com/inkyblackness/shocked-core/io"
+	"github.com/inkyblackness/shocked-core/release"
+
+	check "gopkg.in/check.v1"
+)
+
+type PalettesSuite struct {
+	source release.Release
+}
+
+var _ = check.Suite(&PalettesSuite{})
+
+func (suite *PalettesSuite) SetUpTest(c *check.C) {
+	suite.source = release.NewMemoryRelease()
+}
+
+func (suite *PalettesSuite) createGamePal(c *check.C, tables [][]byte) {
+	resource, _ := suite.source.NewResource("gamepal.res", "")
+	writer, _ := resource.AsSink()
+	store := chunk.NewProviderBackedStore(chunk.NullProvider())
+	if len(tables) > 0 {
+		store.Put(res.ResourceID(0x02BE), &chunk.Chunk{
+			Fragmented:    true,
+			BlockProvider: chunk.MemoryBlockProvider(tables)})
+	}
+	c.Assert(resfile.Write(writer, store), check.IsNil)
+	c.Assert(writer.Close(), check.IsNil)
+}
+
+func (suite *PalettesSuite) palettes(c *check.C) *Palettes {
+	palettes, err := NewPalettes(io.NewReleaseStoreLibrary(suite.source, release.NewMemoryRelease(), 0))
+	c.Assert(err, check.IsNil)
+	return palettes
+}
+
+func (suite *PalettesSuite) TestTranslucencyTableReadsTableFromGamePal(c *check.C) {
+	first := make([]byte, image.ColorsPerPixel)
+	second := make([]byte, image.ColorsPerPixel)
+	second[0x20] = 0x42
+	suite.createGamePal(c, [][]byte{first, second})
+
+	table, err := suite.palettes(c).TranslucencyTable(1)
+
+	c.Assert(err, check.IsNil)
+	c.Check(table.Blend(0x20), check.Equals, byte(0x42))
+}
+
+func (suite *PalettesSuite) TestTranslucencyTableReturnsErrorForUnknownIndex(c *check.C) {
+	suite.createGamePal(c, [][]byte{make([]byte, image.ColorsPerPixel)})
+
+	_, err := suite.palettes(c).TranslucencyTable(1)
+
+	c.Check(err, check.NotNil)
+}
+
+func (suite *PalettesSuite) TestTranslucencyTableReturnsErrorWithoutTables(c *check.C) {
+	suite.createGamePal(c, nil)
+
+	_, err := suite.palettes(c).TranslucencyTable(0)
+
+	c.Check(err, check.NotNil)
+}
@@ -35,16 +35,16 @@ func NewProject(name string, library io.StoreLibrary) (project *Project, err err
 	textures, err = NewTextures(library)
 
 	if err == nil {
-		bitmaps, err = NewBitmaps(library)
+		palettes, err = NewPalettes(library)
 	}
 	if err == nil {
-		texts, err = NewTexts(library)
+		bitmaps, err = NewBitmaps(library, palettes)
 	}
 	if err == nil {
-		sounds, err = NewSounds(library)
+		texts, err = NewTexts(library)
 	}
 	if err == nil {
-		palettes, err = NewPalettes(library)
+		sounds, err = NewSounds(library)
 	}
 	if err == nil {
 		archive, err = NewArchive(library, "archive.dat")
@@ -1,12 +1,10 @@
 package core
 
 import (
-	"bytes"
 	"fmt"
 
 	"github.com/inkyblackness/res"
 	"github.com/inkyblackness/res/audio"
-	memAudio "github.com/inkyblackness/res/audio/mem"
 	"github.com/inkyblackness/res/chunk"
 	"github.com/inkyblackness/res/movi"
 	"github.com/inkyblackness/shocked-core/io"
@@ -55,20 +53,19 @@ func (sounds *Sounds) Audio(key model.ResourceKey) (data audio.SoundData, err er
 		store := sounds.store(key)
 		holder := store.Get(res.ResourceID(int(key.Type) + int(key.Index)))
 
-		if (holder != nil) && (info.contentType == chunk.Media) {
-			blockData := holder.BlockData(0)
-			var container movi.Container
-			container, err = movi.Read(bytes.NewReader(blockData))
+		if holder != nil {
+			var value interface{}
+			value, err = contents.DecodeData(holder.ContentType(), holder.BlockData(0))
 
 			if err == nil {
-				samples := []byte{}
-				for index := 0; index < container.EntryCount(); index++ {
-					entry := container.Entry(index)
-					if entry.Type() == movi.Audio {
-						samples = append(samples, entry.Data()...)
-					}
+				switch typed := value.(type) {
+				case movi.Container:
+					data = audio.DataFromSource(movi.NewAudioSource(typed))
+				case audio.SoundData:
+					data = typed
+				default:
+					err = fmt.Errorf("Resource %v has no audio", key)
 				}
-				data = memAudio.NewL8SoundData(float32(container.AudioSampleRate()), samples)
 			}
 		}
 	} else {
@@ -84,21 +81,23 @@ func (sounds *Sounds) SetAudio(key model.ResourceKey, soundData audio.SoundData)
 
 	if known && (key.Index < info.limit) && key.HasValidLanguage() {
 		store := sounds.store(key)
+		resourceID := res.ResourceID(int(key.Type) + int(key.Index))
 
-		if info.contentType == chunk.Media {
-			resourceID := res.ResourceID(int(key.Type) + int(key.Index))
-
-			if soundData != nil {
-				encodedData := movi.ContainSoundData(soundData)
+		if soundData != nil {
+			var encodedData []byte
+			encodedData, err = contents.EncodeData(info.contentType, soundData)
+			if err == nil {
 				store.Put(resourceID,
 					&chunk.Chunk{
 						ContentType:   info.contentType,
 						BlockProvider: chunk.MemoryBlockProvider([][]byte{encodedData})})
-			} else {
-				store.Del(resourceID)
 			}
+		} else {
+			store.Del(resourceID)
+		}
+		if err == nil {
+			resultKey = key
 		}
-		resultKey = key
 	} else {
 		err = fmt.Errorf("Unsupported resource key: %v", key)
 	}
@@ -77,9 +77,12 @@ func (textures *Textures) Image(index int, size model.TextureSize) (bmp image.Bi
 }
 
 // SetImage requests to set the bitmap of identified & sized texture..
-func (textures *Textures) SetImage(index int, size model.TextureSize, imgBitmap image.Bitmap) {
+func (textures *Textures) SetImage(index int, size model.TextureSize, imgBitmap image.Bitmap) (err error) {
 	writer := bytes.NewBuffer(nil)
-	image.Write(writer, imgBitmap, image.UncompressedBitmap, false, 0)
+	err = image.Write(writer, imgBitmap, image.UncompressedBitmap, false, 0)
+	if err != nil {
+		return
+	}
 	blockData := writer.Bytes()
 
 	if size == model.TextureLarge {
@@ -91,6 +94,7 @@ func (textures *Textures) SetImage(index int, size model.TextureSize, imgBitmap
 	} else if size == model.TextureIcon {
 		textures.images.Get(res.ResourceID(0x004C)).SetBlockData(uint16(index), blockData)
 	}
+	return
 }
 
 func (textures *Textures) rawProperties(index int) (entry textprop.Entry) {
@@ -2,6 +2,7 @@ package io
 
 import (
 	"bytes"
+	"errors"
 	"io/ioutil"
 	"log"
 	"time"
@@ -19,9 +20,24 @@ import (
 	"github.com/inkyblackness/shocked-core/release"
 )
 
+// maxUnusedChunkDataSize is the amount of unused space in a chunk resource up to which
+// the resource is updated in place. Resources with more unused space are written anew.
+const maxUnusedChunkDataSize = 16 * 1024 * 1024
+
+// mappedChunkCacheLimit is the amount of decompressed data that is kept for each chunk resource
+// that is mapped from the source.
+const mappedChunkCacheLimit = 8 * 1024 * 1024
+
+var errNotUpdatable = errors.New("resource can not be updated in place")
+var errTooMuchUnusedSpace = errors.New("resource has too much unused space")
+
 // ReleaseStoreLibrary is a container with two releases: one source and one sink.
 // Stores can be retrieved from this library, which access the source release for
 // reading properties and the sink release for writing modified data.
+// Chunk resources of the source that are stored in local files are mapped into memory,
+// so that only the accessed data is loaded. These mappings are never released: Data of
+// them may still be referenced after the store was saved in the sink, and they are
+// limited to one mapping per resource, as the source is opened only once.
 type ReleaseStoreLibrary struct {
 	source      release.Release
 	sink        release.Release
@@ -73,7 +89,7 @@ func (library *ReleaseStoreLibrary) ChunkStore(name string) (chunkStore *Dynamic
 		} else if library.source.HasResource(name) {
 			chunkStore, err = library.openChunkStoreFrom(library.source, name)
 		} else {
-			chunkStore = library.createSavingChunkStore(chunk.NullProvider(), "", name)
+			chunkStore = library.createSavingChunkStore(chunk.NullProvider(), false, "", name)
 		}
 		if err == nil {
 			library.chunkStores[name] = chunkStore
@@ -129,22 +145,32 @@ func (library *ReleaseStoreLibrary) openChunkStoreFrom(rel release.Release, name
 	if err != nil {
 		return
 	}
-	reader, err := resource.AsSource()
+	inSink := rel == library.sink
+	var provider chunk.Provider
+	if localResource, isLocal := resource.(release.LocalResource); isLocal && !inSink {
+		provider, err = resfile.OpenMappedProvider(localResource.LocalPath(), mappedChunkCacheLimit)
+	} else {
+		provider, err = library.readChunkResource(resource)
+	}
 	if err != nil {
 		return
 	}
-	data, err := ioutil.ReadAll(reader)
-	_ = reader.Close()
+	chunkStore = library.createSavingChunkStore(provider, inSink, resource.Path(), name)
+
+	return
+}
+
+func (library *ReleaseStoreLibrary) readChunkResource(resource release.Resource) (provider chunk.Provider, err error) {
+	reader, err := resource.AsSource()
 	if err != nil {
 		return
 	}
-	provider, err := resfile.ReaderFrom(bytes.NewReader(data))
+	data, err := ioutil.ReadAll(reader)
+	_ = reader.Close()
 	if err != nil {
 		return
 	}
-	chunkStore = library.createSavingChunkStore(provider, resource.Path(), name)
-
-	return
+	return resfile.ReaderFrom(bytes.NewReader(data))
 }
 
 func (library *ReleaseStoreLibrary) openObjpropStoreFrom(rel release.Release, name string) (objpropStore objprop.Store, err error) {
@@ -183,19 +209,19 @@ func (library *ReleaseStoreLibrary) openTextpropStoreFrom(rel release.Release, n
 	return
 }
 
-func (library *ReleaseStoreLibrary) createSavingChunkStore(provider chunk.Provider, path string, name string) *DynamicChunkStore {
+func (library *ReleaseStoreLibrary) createSavingChunkStore(provider chunk.Provider, inSink bool, path string, name string) *DynamicChunkStore {
 	storeChanged := make(chan interface{})
 	onStoreChanged := func() { storeChanged <- nil }
 	chunkStore := NewDynamicChunkStore(chunk.NewProviderBackedStore(provider), onStoreChanged)
 
+	lastProvider := provider
+	lastInSink := inSink
 	saveAndSwap := func() {
 		chunkStore.Swap(func(oldStore chunk.Store) chunk.Store {
 			log.Printf("Saving resource <%s>/<%s>\n", path, name)
-			data := library.serializeChunkStore(oldStore)
-			log.Printf("Serialized previous data, recreating new reader for new data")
-			newProvider := library.saveAndReloadChunkData(data, path, name)
+			lastProvider, lastInSink = library.saveAndReloadChunkData(oldStore, lastProvider, lastInSink, path, name)
 
-			return chunk.NewProviderBackedStore(newProvider)
+			return chunk.NewProviderBackedStore(lastProvider)
 		})
 	}
 	library.startSaverRoutine(name, storeChanged, saveAndSwap)
@@ -210,27 +236,101 @@ func (library *ReleaseStoreLibrary) serializeChunkStore(store chunk.Store) []byt
 	return buffer.Data()
 }
 
-func (library *ReleaseStoreLibrary) saveAndReloadChunkData(data []byte, path string, name string) (provider chunk.Provider) {
-	newResource, err := library.saveResource(data, path, name)
-	var reader serial.SeekingReadCloser
-
-	if err == nil {
-		reader, err = newResource.AsSource()
+// saveAndReloadChunkData saves the given store in the sink and returns a provider for the saved data.
+// Should the store be based on the resource in the sink, only the chunks that differ from the
+// base provider are written. Otherwise, or should that fail, the resource is written anew.
+// The returned flag is set if the returned provider is based on the resource in the sink.
+func (library *ReleaseStoreLibrary) saveAndReloadChunkData(store chunk.Store, base chunk.Provider, baseInSink bool,
+	path string, name string) (provider chunk.Provider, inSink bool) {
+	var err error
+	var data []byte
+
+	if baseInSink {
+		err = library.updateChunkResource(store, base, name)
+		if err != nil {
+			log.Printf("Not updating sink in place, rewriting: %v\n", err)
+		}
+	}
+	if !baseInSink || (err != nil) {
+		data = library.serializeChunkStore(store)
+		log.Printf("Serialized previous data, recreating new reader for new data")
+		_, err = library.saveResource(data, path, name)
 	}
 	if err == nil {
-		var newData []byte
-		newData, err = ioutil.ReadAll(reader)
-		_ = reader.Close()
+		var newResource release.Resource
+		newResource, err = library.sink.GetResource(name)
 		if err == nil {
-			provider, err = resfile.ReaderFrom(bytes.NewReader(newData))
+			provider, err = library.readChunkResource(newResource)
 		}
 	}
 	if err != nil {
 		log.Printf("Failed to store in sink, buffering: %v\n", err)
+		if data == nil {
+			data = library.serializeChunkStore(store)
+		}
 		provider, _ = resfile.ReaderFrom(bytes.NewReader(data))
+		return provider, false
 	}
 
-	return
+	return provider, true
+}
+
+// updateChunkResource modifies the resource in the sink in place, so that it contains the given store.
+func (library *ReleaseStoreLibrary) updateChunkResource(store chunk.Store, base chunk.Provider, name string) error {
+	resource, err := library.sink.GetResource(name)
+	if err != nil {
+		return err
+	}
+	updatableResource, isUpdatable := resource.(release.UpdatableResource)
+	if !isUpdatable {
+		return errNotUpdatable
+	}
+	target, err := updatableResource.AsUpdatable()
+	if err != nil {
+		return err
+	}
+	err = library.updateChunks(target, store, base)
+	closeErr := target.Close()
+	if err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+func (library *ReleaseStoreLibrary) updateChunks(target release.Updatable, store chunk.Store, base chunk.Provider) error {
+	updater, err := resfile.NewUpdater(target)
+	if err != nil {
+		return err
+	}
+	if updater.UnusedSize() > maxUnusedChunkDataSize {
+		return errTooMuchUnusedSpace
+	}
+
+	ids := store.IDs()
+	kept := make(map[uint16]bool)
+	for _, id := range ids {
+		kept[id.Value()] = true
+	}
+	for _, id := range base.IDs() {
+		if !kept[id.Value()] {
+			updater.Del(id)
+		}
+	}
+	for _, id := range ids {
+		current, currentErr := store.Chunk(id)
+		if currentErr != nil {
+			return currentErr
+		}
+		if previous, previousErr := base.Chunk(id); (previousErr != nil) || (previous != current) || current.HasModifiedBlocks() {
+			err = updater.Put(id, current)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return updater.Commit()
 }
 
 func (library *ReleaseStoreLibrary) createSavingObjpropStore(provider objprop.Provider, path string, name string, closer func()) objprop.Store {
@@ -1,7 +1,10 @@
 package io
 
 import (
+	"bytes"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"time"
 
 	"github.com/inkyblackness/res"
@@ -53,6 +56,14 @@ func (suite *ReleaseStoreLibrarySuite) createChunkResource(rel release.Release,
 	_ = writer.Close()
 }
 
+func (suite *ReleaseStoreLibrarySuite) resourceData(name string) []byte {
+	resource, _ := suite.sink.GetResource(name)
+	reader, _ := resource.AsSource()
+	defer reader.Close()
+	data, _ := ioutil.ReadAll(reader)
+	return data
+}
+
 func (suite *ReleaseStoreLibrarySuite) createObjpropResource(rel release.Release, name string, filler func(consumer objprop.Consumer)) {
 	resource, _ := rel.NewResource(name, "")
 	writer, _ := resource.AsSink()
@@ -175,6 +186,56 @@ func (suite *ReleaseStoreLibrarySuite) TestSaveAllSavesModifiedSink(c *check.C)
 	c.Check(suite.sink.HasResource("source.res"), check.Equals, true)
 }
 
+func (suite *ReleaseStoreLibrarySuite) TestModifyingChunkSinkUpdatesSinkInPlace(c *check.C) {
+	suite.library = NewReleaseStoreLibrary(suite.source, suite.sink, 1000)
+	suite.createChunkResource(suite.sink, "sink.res", func(consumer chunk.Store) {
+		consumer.Put(chunk.ID(1), suite.aChunk())
+		consumer.Put(chunk.ID(2), suite.aChunk())
+	})
+	originalData := suite.resourceData("sink.res")
+	store, err := suite.library.ChunkStore("sink.res")
+	c.Assert(err, check.IsNil)
+
+	store.Get(res.ResourceID(2)).SetBlockData(0, []byte{0xAA, 0xBB})
+	suite.library.SaveAll()
+	time.Sleep(100 * time.Millisecond)
+
+	newData := suite.resourceData("sink.res")
+	c.Assert(len(newData) > len(originalData), check.Equals, true)
+	c.Check(newData[0x80:len(originalData)], check.DeepEquals, originalData[0x80:])
+	reader, err := resfile.ReaderFrom(bytes.NewReader(newData))
+	c.Assert(err, check.IsNil)
+	c.Check(reader.IDs(), check.DeepEquals, []chunk.Identifier{chunk.ID(1), chunk.ID(2)})
+	c.Check(store.Get(res.ResourceID(2)).BlockData(0), check.DeepEquals, []byte{0xAA, 0xBB})
+}
+
+func (suite *ReleaseStoreLibrarySuite) TestChunkStoreOfLocalSourceIsSavedInSink(c *check.C) {
+	sourcePath, _ := ioutil.TempDir("", "source")
+	defer os.RemoveAll(sourcePath)
+	localSource, _ := release.ReleaseFromDir(sourcePath)
+	suite.createChunkResource(localSource, "local.res", func(consumer chunk.Store) {
+		consumer.Put(chunk.ID(1), &chunk.Chunk{Fragmented: true, Compressed: true,
+			BlockProvider: chunk.MemoryBlockProvider([][]byte{{0x01, 0x01}, {0x02}})})
+	})
+	suite.library = NewReleaseStoreLibrary(localSource, suite.sink, 1000)
+	store, err := suite.library.ChunkStore("local.res")
+	c.Assert(err, check.IsNil)
+
+	blockStore := store.Get(res.ResourceID(1))
+	c.Check(blockStore.BlockData(0), check.DeepEquals, []byte{0x01, 0x01})
+	blockStore.SetBlockData(1, []byte{0xAA})
+	suite.library.SaveAll()
+	time.Sleep(100 * time.Millisecond)
+
+	reader, err := resfile.ReaderFrom(bytes.NewReader(suite.resourceData("local.res")))
+	c.Assert(err, check.IsNil)
+	savedChunk, _ := reader.Chunk(chunk.ID(1))
+	savedBlock, _ := savedChunk.Block(1)
+	savedData, _ := ioutil.ReadAll(savedBlock)
+	c.Check(savedData, check.DeepEquals, []byte{0xAA})
+	c.Check(store.Get(res.ResourceID(1)).BlockData(0), check.DeepEquals, []byte{0x01, 0x01})
+}
+
 func (suite *ReleaseStoreLibrarySuite) TestChunkStoreReturnsSameInstances(c *check.C) {
 	suite.createChunkResource(suite.source, "source.res", func(consumer chunk.Store) {
 		consumer.Put(chunk.ID(1), suite.aChunk())
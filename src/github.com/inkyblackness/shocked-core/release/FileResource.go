@@ -33,11 +33,23 @@ func (resource *fileResource) Path() string {
 	return resource.relativePath
 }
 
+func (resource *fileResource) LocalPath() string {
+	return filepath.Join(resource.basePath, resource.relativePath, resource.filename)
+}
+
 func (resource *fileResource) AsSource() (serial.SeekingReadCloser, error) {
-	return os.Open(filepath.Join(resource.basePath, resource.relativePath, resource.filename))
+	return os.Open(resource.LocalPath())
 }
 
 func (resource *fileResource) AsSink() (serial.SeekingWriteCloser, error) {
 	os.MkdirAll(filepath.Join(resource.basePath, resource.relativePath), os.FileMode(0755))
-	return os.Create(filepath.Join(resource.basePath, resource.relativePath, resource.filename))
+	return os.Create(resource.LocalPath())
+}
+
+func (resource *fileResource) AsUpdatable() (Updatable, error) {
+	file, err := os.OpenFile(resource.LocalPath(), os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
 }
@@ -1,6 +1,8 @@
 package release
 
 import (
+	"io"
+	"io/ioutil"
 	"os"
 	"path"
 	"runtime"
@@ -41,6 +43,12 @@ func (suite *FileResourceSuite) TestPathReturnsRelativePathOfResource(c *check.C
 	c.Check(resource.Path(), check.Equals, "rel")
 }
 
+func (suite *FileResourceSuite) TestLocalPathReturnsPathOfFile(c *check.C) {
+	resource := newFileResource("test1.res", suite.basePath, "rel", "file")
+
+	c.Check(resource.(LocalResource).LocalPath(), check.Equals, path.Join(suite.basePath, "rel", "file"))
+}
+
 func (suite *FileResourceSuite) TestAsSourceReturnsErrorForNotExisting(c *check.C) {
 	resource := newFileResource("test1.res", suite.basePath, ".", "notExisting.bin")
 	_, err := resource.AsSource()
@@ -85,3 +93,28 @@ func (suite *FileResourceSuite) TestAsSinkCreatesDirectoriesForNewFile(c *check.
 
 	c.Check(file, check.NotNil)
 }
+
+func (suite *FileResourceSuite) TestAsUpdatableReturnsErrorForNotExisting(c *check.C) {
+	resource := newFileResource("test1.res", suite.basePath, "dynamic", "notExisting.bin")
+	_, err := resource.(UpdatableResource).AsUpdatable()
+
+	c.Check(err, check.NotNil)
+}
+
+func (suite *FileResourceSuite) TestAsUpdatableKeepsExistingContent(c *check.C) {
+	resource := newFileResource("test1.res", suite.basePath, "dynamic", "updated.bin")
+	sink, _ := resource.AsSink()
+	sink.Write([]byte{0x01, 0x02, 0x03})
+	sink.Close()
+
+	file, err := resource.(UpdatableResource).AsUpdatable()
+	c.Assert(err, check.IsNil)
+	file.Seek(1, io.SeekStart)
+	file.Write([]byte{0x0B})
+	file.Close()
+
+	source, _ := resource.AsSource()
+	defer source.Close()
+	data, _ := ioutil.ReadAll(source)
+	c.Check(data, check.DeepEquals, []byte{0x01, 0x0B, 0x03})
+}
@@ -1,6 +1,7 @@
 package release
 
 import (
+	"bytes"
 	"fmt"
 
 	"github.com/inkyblackness/res/serial"
@@ -60,3 +61,28 @@ func (res *memoryResource) AsSink() (buf serial.SeekingWriteCloser, err error) {
 
 	return
 }
+
+// AsUpdatable returns an interface for reading and modifying the resource in place.
+func (res *memoryResource) AsUpdatable() (buf Updatable, err error) {
+	if res.readLocks == 0 && res.writeLocks == 0 {
+		res.writeLocks++
+		data := make([]byte, len(res.data))
+		copy(data, res.data)
+		buf = updatableByteStore{serial.NewByteStoreFromData(data, func(data []byte) {
+			res.data = data
+			res.writeLocks--
+		})}
+	} else {
+		err = fmt.Errorf("Cannot open for updating")
+	}
+
+	return
+}
+
+type updatableByteStore struct {
+	*serial.ByteStore
+}
+
+func (store updatableByteStore) ReadAt(p []byte, off int64) (int, error) {
+	return bytes.NewReader(store.Data()).ReadAt(p, off)
+}
@@ -1,6 +1,7 @@
 package release
 
 import (
+	"io"
 	"io/ioutil"
 
 	check "gopkg.in/check.v1"
@@ -112,3 +113,29 @@ func (suite *MemoryResourceSuite) TestAsSinkReturnsWriterForData(c *check.C) {
 
 	c.Check(data, check.DeepEquals, []byte{0x0A, 0x0B})
 }
+
+func (suite *MemoryResourceSuite) TestAsUpdatableModifiesExistingData(c *check.C) {
+	resource := NewMemoryResource("test1.res", "rel", []byte{0x01, 0x02, 0x03})
+
+	updatable, _ := resource.(UpdatableResource).AsUpdatable()
+	buf := make([]byte, 1)
+	updatable.ReadAt(buf, 2)
+	updatable.Seek(1, io.SeekStart)
+	updatable.Write([]byte{0x0B})
+	updatable.Close()
+
+	reader, _ := resource.AsSource()
+	data, _ := ioutil.ReadAll(reader)
+
+	c.Check(buf, check.DeepEquals, []byte{0x03})
+	c.Check(data, check.DeepEquals, []byte{0x01, 0x0B, 0x03})
+}
+
+func (suite *MemoryResourceSuite) TestAsUpdatableProhibitsAsSourceWhileOpen(c *check.C) {
+	resource := NewMemoryResource("test1.res", "rel", []byte{0x01, 0x02, 0x03})
+
+	resource.(UpdatableResource).AsUpdatable()
+	_, err := resource.AsSource()
+
+	c.Check(err, check.NotNil)
+}
@@ -1,6 +1,10 @@
 package release
 
-import "github.com/inkyblackness/res/serial"
+import (
+	"io"
+
+	"github.com/inkyblackness/res/serial"
+)
 
 // Resource describes one resource file.
 type Resource interface {
@@ -13,3 +17,24 @@ type Resource interface {
 	// AsSink returns an interface for writing the resource.
 	AsSink() (serial.SeekingWriteCloser, error)
 }
+
+// Updatable is a resource that is opened for being modified in place.
+type Updatable interface {
+	io.ReaderAt
+	io.WriteSeeker
+	io.Closer
+}
+
+// UpdatableResource is a resource that can be modified in place.
+type UpdatableResource interface {
+	Resource
+	// AsUpdatable returns an interface for reading and modifying the existing resource.
+	AsUpdatable() (Updatable, error)
+}
+
+// LocalResource is a resource that is stored as a file of the local file system.
+type LocalResource interface {
+	Resource
+	// LocalPath returns the path of the file that stores the resource.
+	LocalPath() string
+}
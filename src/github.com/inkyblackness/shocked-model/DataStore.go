@@ -73,6 +73,8 @@ type DataStore interface {
 
 	// Palette queries a palette.
 	Palette(projectID string, paletteID string, onSuccess func(colors [256]Color), onFailure FailureFunc)
+	// ShadingTable queries the table of light levels of the game palette.
+	ShadingTable(projectID string, onSuccess func(table ShadingTable), onFailure FailureFunc)
 	// Levels queries all levels of a project.
 	Levels(projectID string, archiveID string, onSuccess func(levels []Level), onFailure FailureFunc)
 
@@ -0,0 +1,8 @@
+package model
+
+// ShadingTable maps palette indices to the indices that show the same colors
+// at lower light levels.
+type ShadingTable struct {
+	// Levels contains one mapping per light level, starting with the brightest.
+	Levels [][256]byte
+}